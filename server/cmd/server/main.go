@@ -12,6 +12,7 @@ import (
 	"github.com/yair12/lists-viewer/server/internal/config"
 	"github.com/yair12/lists-viewer/server/internal/database"
 	"github.com/yair12/lists-viewer/server/internal/setup"
+	"github.com/yair12/lists-viewer/server/internal/smtpd"
 )
 
 func main() {
@@ -29,7 +30,12 @@ func main() {
 	defer dbClient.Disconnect(context.Background())
 
 	// Initialize router
-	router := setup.SetupRouter(dbClient)
+	router := setup.SetupRouter(dbClient, cfg)
+
+	// Start background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	jobs := setup.SetupScheduler(dbClient, cfg)
+	jobs.Start(jobsCtx)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -48,12 +54,28 @@ func main() {
 		}
 	}()
 
+	// Start the SMTP listener when enabled
+	mailServer := setup.SetupMailServer(dbClient, cfg)
+	if mailServer != nil {
+		go func() {
+			log.Printf("Starting SMTP listener on %s", mailServer.Addr)
+			if err := mailServer.ListenAndServe(); err != nil && err != smtpd.ErrServerClosed {
+				log.Fatalf("SMTP listener error: %v", err)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopJobs()
+	jobs.Wait()
+	if mailServer != nil {
+		mailServer.Close()
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -38,8 +38,23 @@ func ErrorHandler(w http.ResponseWriter, err error) {
 		ErrorResponse(w, http.StatusNotFound, "not_found", "List not found", nil)
 	case strings.Contains(errMsg, "item not found"):
 		ErrorResponse(w, http.StatusNotFound, "not_found", "Item not found", nil)
+	case strings.Contains(errMsg, "template not found"):
+		ErrorResponse(w, http.StatusNotFound, "not_found", "Template not found", nil)
 	case strings.Contains(errMsg, "user not found"):
 		ErrorResponse(w, http.StatusNotFound, "not_found", "User not found", nil)
+	case strings.Contains(errMsg, "subscription not found"):
+		ErrorResponse(w, http.StatusNotFound, "not_found", "Subscription not found", nil)
+	case strings.Contains(errMsg, "webhook not found"):
+		ErrorResponse(w, http.StatusNotFound, "not_found", "Webhook not found", nil)
+	case strings.Contains(errMsg, "delivery not found"):
+		ErrorResponse(w, http.StatusNotFound, "not_found", "Delivery not found", nil)
+	case strings.Contains(errMsg, "token not found"):
+		ErrorResponse(w, http.StatusNotFound, "not_found", "Token not found", nil)
+	case strings.Contains(errMsg, "validation_error: "):
+		_, message, _ := strings.Cut(errMsg, "validation_error: ")
+		ErrorResponse(w, http.StatusBadRequest, "validation_error", message, nil)
+	case strings.Contains(errMsg, "push_unavailable"):
+		ErrorResponse(w, http.StatusServiceUnavailable, "push_unavailable", "Push notifications are not configured", nil)
 	case strings.Contains(errMsg, "unauthorized"):
 		ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing or invalid user ID", nil)
 	default:
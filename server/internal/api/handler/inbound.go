@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/yair12/lists-viewer/server/internal/api"
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/service"
+)
+
+// maxInboundTextSize bounds plain-text quick-add bodies
+const maxInboundTextSize = 4 << 10
+
+// InboundHandler handles HTTP requests for adding items from voice assistants and shortcuts
+type InboundHandler struct {
+	service *service.InboundService
+}
+
+// NewInboundHandler creates a new inbound handler
+func NewInboundHandler(svc *service.InboundService) *InboundHandler {
+	return &InboundHandler{service: svc}
+}
+
+// QuickAdd adds an item described by plain text ("eggs to groceries") or JSON ({"text": "eggs", "list": "groceries"})
+// The token is passed only as a bearer token, so it stays out of URLs and access logs; plain-text requests get a plain-text reply
+// POST /api/v1/inbound/quick-add?list=groceries
+func (h *InboundHandler) QuickAdd(w http.ResponseWriter, r *http.Request) {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	token = strings.TrimSpace(token)
+	if token == "" {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing token", nil)
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	isJSON := mediaType == "application/json"
+
+	req := models.InboundQuickAddRequest{List: r.URL.Query().Get("list")}
+	if isJSON {
+		if err := api.ParseJSONRequest(r, &req); err != nil {
+			api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+			return
+		}
+	} else {
+		text, err := io.ReadAll(io.LimitReader(r.Body, maxInboundTextSize))
+		if err != nil {
+			api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+			return
+		}
+		req.Text = string(text)
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "Text is required", nil)
+		return
+	}
+
+	result, err := h.service.QuickAdd(r.Context(), token, &req)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Invalid token", nil)
+			return
+		}
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	if !isJSON {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, result.Message)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetTokens retrieves the caller's inbound tokens
+// GET /api/v1/inbound/tokens
+func (h *InboundHandler) GetTokens(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	tokens, err := h.service.GetTokens(r.Context(), userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.InboundTokensResponse{Data: tokens})
+}
+
+// CreateToken creates an inbound token for the caller; the token is only returned by this call
+// POST /api/v1/inbound/tokens
+func (h *InboundHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	var req models.CreateInboundTokenRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	token, err := h.service.CreateToken(r.Context(), &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(token)
+}
+
+// DeleteToken revokes one of the caller's inbound tokens
+// DELETE /api/v1/inbound/tokens/:id
+func (h *InboundHandler) DeleteToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	if err := h.service.DeleteToken(r.Context(), mux.Vars(r)["id"], userID); err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
@@ -22,10 +23,64 @@ func NewItemHandler(svc *service.ItemService) *ItemHandler {
 	return &ItemHandler{service: svc}
 }
 
-// GetItemsByList retrieves all items in a list
-// GET /api/v1/lists/:listId/items
+// GetItemsByList retrieves the items in a list
+// GET /api/v1/lists/:listId/items?completed=false&type=item&prefix=to&sort=name&direction=desc&limit=50&cursor=...
 func (h *ItemHandler) GetItemsByList(w http.ResponseWriter, r *http.Request) {
-	_, ok := api.ValidateUserID(r)
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	listID := mux.Vars(r)["listId"]
+	if listID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID is required", nil)
+		return
+	}
+
+	params := r.URL.Query()
+	query := models.ItemQuery{
+		IncludeArchived: params.Get("includeArchived") == "true",
+		Type:            params.Get("type"),
+		NamePrefix:      params.Get("prefix"),
+		SortBy:          params.Get("sort"),
+		Descending:      params.Get("direction") == "desc",
+		Cursor:          params.Get("cursor"),
+	}
+
+	if completed := params.Get("completed"); completed != "" {
+		value, err := strconv.ParseBool(completed)
+		if err != nil {
+			api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "completed must be true or false", nil)
+			return
+		}
+		query.Completed = &value
+	}
+
+	if limit := params.Get("limit"); limit != "" {
+		value, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil || value < 1 {
+			api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "limit must be a positive number", nil)
+			return
+		}
+		query.Limit = value
+	}
+
+	items, next, err := h.service.GetItemsByList(r.Context(), listID, query, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.ItemsResponse{Data: items, NextCursor: next})
+}
+
+// GetRecentlyCompletedItems retrieves items completed within the last N days
+// GET /api/v1/lists/:listId/items/recent?days=7
+func (h *ItemHandler) GetRecentlyCompletedItems(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
 	if !ok {
 		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
 		return
@@ -37,9 +92,17 @@ func (h *ItemHandler) GetItemsByList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	includeArchived := r.URL.Query().Get("includeArchived") == "true"
+	days := 7
+	if value := r.URL.Query().Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "days must be a number", nil)
+			return
+		}
+		days = parsed
+	}
 
-	items, err := h.service.GetItemsByList(r.Context(), listID, includeArchived)
+	items, err := h.service.GetRecentlyCompletedItems(r.Context(), listID, days, userID)
 	if err != nil {
 		api.ErrorHandler(w, err)
 		return
@@ -50,6 +113,66 @@ func (h *ItemHandler) GetItemsByList(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(models.ItemsResponse{Data: items})
 }
 
+// GetDuplicateItems retrieves groups of items sharing the same name
+// GET /api/v1/lists/:listId/items/duplicates?foldUnicode=true
+func (h *ItemHandler) GetDuplicateItems(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	listID := mux.Vars(r)["listId"]
+	if listID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID is required", nil)
+		return
+	}
+
+	foldUnicode := r.URL.Query().Get("foldUnicode") == "true"
+
+	groups, err := h.service.GetDuplicateItems(r.Context(), listID, foldUnicode, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.DuplicatesResponse{Data: groups})
+}
+
+// MergeItems merges duplicate items into one
+// POST /api/v1/lists/:listId/items/merge
+func (h *ItemHandler) MergeItems(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	listID := mux.Vars(r)["listId"]
+	if listID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID is required", nil)
+		return
+	}
+
+	var req models.MergeItemsRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	result, err := h.service.MergeItems(r.Context(), listID, &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
 // CreateItem creates a new item
 // POST /api/v1/lists/:listId/items
 func (h *ItemHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
@@ -82,10 +205,47 @@ func (h *ItemHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(item)
 }
 
+// QuickAddItem creates an item from free text such as "2kg tomatoes"
+// POST /api/v1/lists/:listId/items/quick
+func (h *ItemHandler) QuickAddItem(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	listID := mux.Vars(r)["listId"]
+	if listID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID is required", nil)
+		return
+	}
+
+	var req models.QuickAddItemRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	result, err := h.service.QuickAddItem(r.Context(), listID, &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if req.Preview {
+		status = http.StatusOK
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
+
 // GetItem retrieves a specific item
 // GET /api/v1/lists/:listId/items/:itemId
 func (h *ItemHandler) GetItem(w http.ResponseWriter, r *http.Request) {
-	_, ok := api.ValidateUserID(r)
+	userID, ok := api.ValidateUserID(r)
 	if !ok {
 		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
 		return
@@ -100,7 +260,7 @@ func (h *ItemHandler) GetItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	item, err := h.service.GetItem(r.Context(), listID, itemID)
+	item, err := h.service.GetItem(r.Context(), listID, itemID, userID)
 	if err != nil {
 		api.ErrorHandler(w, err)
 		return
@@ -150,6 +310,76 @@ func (h *ItemHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(item)
 }
 
+// FulfillItem records a partially bought quantity of an item
+// PATCH /api/v1/lists/:listId/items/:itemId/fulfill
+func (h *ItemHandler) FulfillItem(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	vars := mux.Vars(r)
+	listID := vars["listId"]
+	itemID := vars["itemId"]
+
+	if listID == "" || itemID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID and Item ID are required", nil)
+		return
+	}
+
+	var req models.FulfillItemRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	item, err := h.service.FulfillItem(r.Context(), listID, itemID, &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(item)
+}
+
+// ConvertItem converts an item into a nested list or an empty nested list into an item
+// PATCH /api/v1/lists/:listId/items/:itemId/convert
+func (h *ItemHandler) ConvertItem(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	vars := mux.Vars(r)
+	listID := vars["listId"]
+	itemID := vars["itemId"]
+
+	if listID == "" || itemID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID and Item ID are required", nil)
+		return
+	}
+
+	var req models.ConvertItemRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	item, err := h.service.ConvertItem(r.Context(), listID, itemID, &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(item)
+}
+
 // DeleteItem deletes an item
 // DELETE /api/v1/lists/:listId/items/:itemId
 func (h *ItemHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
@@ -243,27 +473,30 @@ func (h *ItemHandler) BulkCompleteItems(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	log.Printf("BulkCompleteItems: listID=%s, itemIDs=%v, userID=%s", listID, req.ItemIDs, userID)
-	items, err := h.service.BulkCompleteItems(r.Context(), listID, req.ItemIDs, userID)
+	log.Printf("BulkCompleteItems: listID=%s, itemIDs=%v, items=%d, userID=%s", listID, req.ItemIDs, len(req.Items), userID)
+	results, err := h.service.BulkCompleteItems(r.Context(), listID, &req, userID)
 	if err != nil {
 		log.Printf("BulkCompleteItems ERROR: %v", err)
 		api.ErrorHandler(w, err)
 		return
 	}
-	log.Printf("BulkCompleteItems SUCCESS: completed %d items", len(items))
+
+	items := doneItems(results)
+	log.Printf("BulkCompleteItems SUCCESS: completed %d of %d items", len(items), len(results))
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(models.BulkCompleteResponse{
-		CompletedCount: int(len(items)),
+		CompletedCount: len(items),
 		Data:           items,
+		Results:        results,
 	})
 }
 
 // BulkDeleteItems deletes multiple items
 // DELETE /api/v1/lists/:listId/items
 func (h *ItemHandler) BulkDeleteItems(w http.ResponseWriter, r *http.Request) {
-	_, ok := api.ValidateUserID(r)
+	userID, ok := api.ValidateUserID(r)
 	if !ok {
 		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
 		return
@@ -281,21 +514,31 @@ func (h *ItemHandler) BulkDeleteItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	count, err := h.service.BulkDeleteItems(r.Context(), listID, req.ItemIDs)
+	results, err := h.service.BulkDeleteItems(r.Context(), listID, &req, userID)
 	if err != nil {
 		api.ErrorHandler(w, err)
 		return
 	}
 
+	deletedCount := 0
+	for _, result := range results {
+		if result.Status == models.BulkStatusDone {
+			deletedCount++
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(models.BulkDeleteResponse{DeletedCount: int(count)})
+	json.NewEncoder(w).Encode(models.BulkDeleteResponse{
+		DeletedCount: deletedCount,
+		Results:      results,
+	})
 }
 
 // DeleteCompletedItems deletes all completed items in a list
 // DELETE /api/v1/lists/:listId/items/completed
 func (h *ItemHandler) DeleteCompletedItems(w http.ResponseWriter, r *http.Request) {
-	_, ok := api.ValidateUserID(r)
+	userID, ok := api.ValidateUserID(r)
 	if !ok {
 		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
 		return
@@ -307,7 +550,7 @@ func (h *ItemHandler) DeleteCompletedItems(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	count, err := h.service.DeleteCompletedItems(r.Context(), listID)
+	count, err := h.service.DeleteCompletedItems(r.Context(), listID, userID)
 	if err != nil {
 		api.ErrorHandler(w, err)
 		return
@@ -342,7 +585,261 @@ func (h *ItemHandler) MoveItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	item, err := h.service.MoveItem(r.Context(), listID, itemID, req.TargetListID, req.Order, userID)
+	item, err := h.service.MoveItem(r.Context(), listID, itemID, req.TargetListID, req.Order, req.Version, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(item)
+}
+
+// BulkUncompleteItems returns multiple completed items to open
+// PATCH /api/v1/lists/:listId/items/uncomplete
+func (h *ItemHandler) BulkUncompleteItems(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	listID := mux.Vars(r)["listId"]
+	if listID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID is required", nil)
+		return
+	}
+
+	var req models.BulkUncompleteRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	results, err := h.service.BulkUncompleteItems(r.Context(), listID, &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	writeBulkUpdateResponse(w, results)
+}
+
+// BulkMoveItems moves multiple items to another list
+// PATCH /api/v1/lists/:listId/items/move
+func (h *ItemHandler) BulkMoveItems(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	listID := mux.Vars(r)["listId"]
+	if listID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID is required", nil)
+		return
+	}
+
+	var req models.BulkMoveRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	results, err := h.service.BulkMoveItems(r.Context(), listID, &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	writeBulkUpdateResponse(w, results)
+}
+
+// BulkUpdateItems sets fields on multiple items
+// PATCH /api/v1/lists/:listId/items
+func (h *ItemHandler) BulkUpdateItems(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	listID := mux.Vars(r)["listId"]
+	if listID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID is required", nil)
+		return
+	}
+
+	var req models.BulkUpdateRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	results, err := h.service.BulkUpdateItems(r.Context(), listID, &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	writeBulkUpdateResponse(w, results)
+}
+
+// writeBulkUpdateResponse writes the per-item results of a bulk operation
+func writeBulkUpdateResponse(w http.ResponseWriter, results []models.BulkItemResult) {
+	items := doneItems(results)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.BulkUpdateResponse{
+		UpdatedCount: len(items),
+		Data:         items,
+		Results:      results,
+	})
+}
+
+// doneItems returns the items of successful bulk results
+func doneItems(results []models.BulkItemResult) []models.ItemResponse {
+	items := []models.ItemResponse{}
+	for _, result := range results {
+		if result.Status == models.BulkStatusDone && result.Item != nil {
+			items = append(items, *result.Item)
+		}
+	}
+	return items
+}
+
+// SetRecurrence handles making an item recur
+// PUT /api/v1/lists/:listId/items/:itemId/recurrence
+func (h *ItemHandler) SetRecurrence(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	vars := mux.Vars(r)
+	listID := vars["listId"]
+	itemID := vars["itemId"]
+
+	if listID == "" || itemID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID and Item ID are required", nil)
+		return
+	}
+
+	var req models.SetRecurrenceRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	item, err := h.service.SetRecurrence(r.Context(), listID, itemID, &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(item)
+}
+
+// DeleteRecurrence handles stopping an item from recurring
+// DELETE /api/v1/lists/:listId/items/:itemId/recurrence
+func (h *ItemHandler) DeleteRecurrence(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	vars := mux.Vars(r)
+	listID := vars["listId"]
+	itemID := vars["itemId"]
+
+	if listID == "" || itemID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID and Item ID are required", nil)
+		return
+	}
+
+	var req models.DeleteItemRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	item, err := h.service.DeleteRecurrence(r.Context(), listID, itemID, req.Version, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(item)
+}
+
+// SetDueDate handles setting the due date and reminder of an item
+// PUT /api/v1/lists/:listId/items/:itemId/due
+func (h *ItemHandler) SetDueDate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	vars := mux.Vars(r)
+	listID := vars["listId"]
+	itemID := vars["itemId"]
+
+	if listID == "" || itemID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID and Item ID are required", nil)
+		return
+	}
+
+	var req models.SetDueDateRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	item, err := h.service.SetDueDate(r.Context(), listID, itemID, &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(item)
+}
+
+// DeleteDueDate handles removing the due date and reminder of an item
+// DELETE /api/v1/lists/:listId/items/:itemId/due
+func (h *ItemHandler) DeleteDueDate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	vars := mux.Vars(r)
+	listID := vars["listId"]
+	itemID := vars["itemId"]
+
+	if listID == "" || itemID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID and Item ID are required", nil)
+		return
+	}
+
+	var req models.DeleteItemRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	item, err := h.service.DeleteDueDate(r.Context(), listID, itemID, req.Version, userID)
 	if err != nil {
 		api.ErrorHandler(w, err)
 		return
@@ -69,7 +69,7 @@ func (h *ListHandler) CreateList(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetList retrieves a specific list by ID
-// GET /api/v1/lists/:id
+// GET /api/v1/lists/:id?expand=items,children
 func (h *ListHandler) GetList(w http.ResponseWriter, r *http.Request) {
 	userID, ok := api.ValidateUserID(r)
 	if !ok {
@@ -83,7 +83,13 @@ func (h *ListHandler) GetList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	list, err := h.service.GetList(r.Context(), listID, userID)
+	var list *models.ListResponse
+	var err error
+	if expand := r.URL.Query().Get("expand"); expand != "" {
+		list, err = h.service.GetListExpanded(r.Context(), listID, strings.Split(expand, ","), userID)
+	} else {
+		list, err = h.service.GetList(r.Context(), listID, userID)
+	}
 	if err != nil {
 		api.ErrorHandler(w, err)
 		return
@@ -166,3 +172,190 @@ func (h *ListHandler) DeleteList(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// PromoteNestedList turns a nested list into a top-level list
+// POST /api/v1/lists/:listId/items/:itemId/promote
+func (h *ListHandler) PromoteNestedList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	vars := mux.Vars(r)
+	listID := vars["listId"]
+	itemID := vars["itemId"]
+	if listID == "" || itemID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID and Item ID are required", nil)
+		return
+	}
+
+	var req models.PromoteNestedListRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	list, err := h.service.PromoteNestedList(r.Context(), listID, itemID, &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(list)
+}
+
+// DemoteList turns a top-level list into a nested list of another list
+// POST /api/v1/lists/:id/demote
+func (h *ListHandler) DemoteList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	listID := mux.Vars(r)["id"]
+	if listID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID is required", nil)
+		return
+	}
+
+	var req models.DemoteListRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	item, err := h.service.DemoteList(r.Context(), listID, &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+// CloneList handles deep-copying a list with its items and nested lists
+// POST /api/v1/lists/:id/clone
+func (h *ListHandler) CloneList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	listID := mux.Vars(r)["id"]
+	if listID == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "List ID is required", nil)
+		return
+	}
+
+	var req models.CloneListRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	list, err := h.service.CloneList(r.Context(), listID, &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(list)
+}
+
+// SetResetSchedule handles scheduling recurring resets of a list
+// PUT /api/v1/lists/:id/schedule
+func (h *ListHandler) SetResetSchedule(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	var req models.SetResetScheduleRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	list, err := h.service.SetResetSchedule(r.Context(), mux.Vars(r)["id"], &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(list)
+}
+
+// DeleteResetSchedule handles stopping the recurring resets of a list
+// DELETE /api/v1/lists/:id/schedule
+func (h *ListHandler) DeleteResetSchedule(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	if err := h.service.DeleteResetSchedule(r.Context(), mux.Vars(r)["id"], userID); err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ResetList handles resetting a list right away
+// POST /api/v1/lists/:id/reset
+func (h *ListHandler) ResetList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	var req models.ResetListRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	reset, err := h.service.ResetList(r.Context(), mux.Vars(r)["id"], &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(reset)
+}
+
+// GetResetHistory retrieves the reset history of a list
+// GET /api/v1/lists/:id/resets
+func (h *ListHandler) GetResetHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	resets, err := h.service.GetResetHistory(r.Context(), mux.Vars(r)["id"], userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.ListResetsResponse{Data: resets})
+}
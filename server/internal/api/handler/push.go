@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/yair12/lists-viewer/server/internal/api"
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/service"
+)
+
+// PushHandler handles HTTP requests for Web Push subscriptions
+type PushHandler struct {
+	service *service.PushService
+}
+
+// NewPushHandler creates a new push handler
+func NewPushHandler(svc *service.PushService) *PushHandler {
+	return &PushHandler{service: svc}
+}
+
+// GetPublicKey retrieves the VAPID public key to pass as applicationServerKey when subscribing
+// GET /api/v1/push/key
+func (h *PushHandler) GetPublicKey(w http.ResponseWriter, r *http.Request) {
+	publicKey, err := h.service.PublicKey()
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.PushKeyResponse{PublicKey: publicKey})
+}
+
+// GetSubscriptions retrieves the caller's push subscriptions
+// GET /api/v1/push/subscriptions
+func (h *PushHandler) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	subs, err := h.service.GetSubscriptions(r.Context(), userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.PushSubscriptionsResponse{Data: subs})
+}
+
+// RegisterSubscription registers a browser of the caller for push notifications
+// POST /api/v1/push/subscriptions
+func (h *PushHandler) RegisterSubscription(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	var req models.RegisterPushSubscriptionRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	sub, err := h.service.RegisterSubscription(r.Context(), &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sub)
+}
+
+// DeleteSubscription unregisters one of the caller's push subscriptions
+// DELETE /api/v1/push/subscriptions/:id
+func (h *PushHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	if err := h.service.DeleteSubscription(r.Context(), mux.Vars(r)["id"], userID); err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
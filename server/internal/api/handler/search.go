@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/yair12/lists-viewer/server/internal/api"
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/service"
+)
+
+// SearchHandler handles search HTTP requests
+type SearchHandler struct {
+	service *service.SearchService
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(svc *service.SearchService) *SearchHandler {
+	return &SearchHandler{service: svc}
+}
+
+// Search finds lists and items by name or description
+// GET /api/v1/search?q=batteries&completed=false&type=item&list=:listId&limit=50
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	req := models.SearchRequest{
+		Query:  query.Get("q"),
+		Type:   query.Get("type"),
+		ListID: query.Get("list"),
+	}
+
+	if completed := query.Get("completed"); completed != "" {
+		value, err := strconv.ParseBool(completed)
+		if err != nil {
+			api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "completed must be true or false", nil)
+			return
+		}
+		req.Completed = &value
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil {
+			api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "limit must be a number", nil)
+			return
+		}
+		req.Limit = value
+	}
+
+	response, err := h.service.Search(r.Context(), &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
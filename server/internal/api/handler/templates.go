@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/yair12/lists-viewer/server/internal/api"
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/service"
+)
+
+// TemplateHandler handles template-related HTTP requests
+type TemplateHandler struct {
+	service *service.TemplateService
+}
+
+// NewTemplateHandler creates a new template handler
+func NewTemplateHandler(svc *service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{service: svc}
+}
+
+// GetAllTemplates retrieves all templates
+// GET /api/v1/templates
+func (h *TemplateHandler) GetAllTemplates(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	templates, err := h.service.GetAllTemplates(r.Context(), userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.TemplatesResponse{Data: templates})
+}
+
+// CreateTemplate creates a template, or saves a list as a template
+// POST /api/v1/templates
+func (h *TemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	var req models.CreateTemplateRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	template, err := h.service.CreateTemplate(r.Context(), &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(template)
+}
+
+// GetTemplate retrieves a specific template
+// GET /api/v1/templates/:id
+func (h *TemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	if _, ok := api.ValidateUserID(r); !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	template, err := h.service.GetTemplate(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(template)
+}
+
+// UpdateTemplate updates a template
+// PUT /api/v1/templates/:id
+func (h *TemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	var req models.UpdateTemplateRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	template, err := h.service.UpdateTemplate(r.Context(), mux.Vars(r)["id"], &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(template)
+}
+
+// DeleteTemplate deletes a template
+// DELETE /api/v1/templates/:id
+func (h *TemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	if _, ok := api.ValidateUserID(r); !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	var req models.DeleteTemplateRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	if err := h.service.DeleteTemplate(r.Context(), mux.Vars(r)["id"], req.Version); err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// InstantiateTemplate creates a new list from a template
+// POST /api/v1/templates/:id/instantiate
+func (h *TemplateHandler) InstantiateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	var req models.InstantiateTemplateRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	list, err := h.service.InstantiateTemplate(r.Context(), mux.Vars(r)["id"], &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(list)
+}
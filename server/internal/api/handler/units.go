@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/units"
+)
+
+// UnitHandler handles quantity unit HTTP requests
+type UnitHandler struct{}
+
+// NewUnitHandler creates a new unit handler
+func NewUnitHandler() *UnitHandler {
+	return &UnitHandler{}
+}
+
+// GetUnits retrieves the known quantity units and their aliases
+// GET /api/v1/units
+func (h *UnitHandler) GetUnits(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.UnitsResponse{Data: units.All()})
+}
@@ -103,3 +103,29 @@ func (h *UserHandler) UpdateUserIcon(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(user)
 }
+
+// UpdatePreferences updates a user's preferences
+// PATCH /api/v1/users/:username/preferences
+func (h *UserHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	username := mux.Vars(r)["username"]
+	if username == "" {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "username is required", nil)
+		return
+	}
+
+	var req models.UpdatePreferencesRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	user, err := h.service.UpdatePreferences(r.Context(), username, &req)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(user)
+}
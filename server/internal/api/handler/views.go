@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yair12/lists-viewer/server/internal/api"
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/service"
+)
+
+// ViewHandler handles HTTP requests for views that span multiple lists
+type ViewHandler struct {
+	service *service.ItemService
+}
+
+// NewViewHandler creates a new view handler
+func NewViewHandler(svc *service.ItemService) *ViewHandler {
+	return &ViewHandler{service: svc}
+}
+
+// GetShoppingView retrieves the open items of several lists, grouped by name with summed quantities
+// GET /api/v1/views/shopping?lists=a,b,c
+func (h *ViewHandler) GetShoppingView(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	entries, err := h.service.GetShoppingView(r.Context(), listIDsParam(r), userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.ShoppingViewResponse{Data: entries})
+}
+
+// CompleteShoppingEntry completes every source item of a shopping view entry
+// PATCH /api/v1/views/shopping/complete
+func (h *ViewHandler) CompleteShoppingEntry(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	var req models.CompleteShoppingEntryRequest
+	if err := api.ParseJSONRequest(r, &req); err != nil {
+		api.ErrorResponse(w, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+
+	results, err := h.service.CompleteShoppingEntry(r.Context(), &req, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	items := doneItems(results)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.BulkCompleteResponse{
+		CompletedCount: len(items),
+		Data:           items,
+		Results:        results,
+	})
+}
+
+// GetOverdueItems retrieves the open items whose due date has passed, across all lists or the given ones
+// GET /api/v1/views/overdue?lists=a,b,c
+func (h *ViewHandler) GetOverdueItems(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	items, err := h.service.GetOverdueItems(r.Context(), listIDsParam(r), userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.DueItemsResponse{Data: items})
+}
+
+// GetDueSoonItems retrieves the open items coming due within the given number of hours (default 24)
+// GET /api/v1/views/due-soon?hours=24&lists=a,b,c
+func (h *ViewHandler) GetDueSoonItems(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.ValidateUserID(r)
+	if !ok {
+		api.ErrorResponse(w, http.StatusUnauthorized, "unauthorized", "Missing X-User-Id header", nil)
+		return
+	}
+
+	hours := 24
+	if hoursStr := r.URL.Query().Get("hours"); hoursStr != "" {
+		var err error
+		if hours, err = strconv.Atoi(hoursStr); err != nil {
+			api.ErrorResponse(w, http.StatusBadRequest, "validation_error", "hours must be a number", nil)
+			return
+		}
+	}
+
+	items, err := h.service.GetDueSoonItems(r.Context(), listIDsParam(r), time.Duration(hours)*time.Hour, userID)
+	if err != nil {
+		api.ErrorHandler(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.DueItemsResponse{Data: items})
+}
+
+// listIDsParam returns the list IDs of a comma-separated lists query parameter
+func listIDsParam(r *http.Request) []string {
+	listIDs := []string{}
+	for _, listID := range strings.Split(r.URL.Query().Get("lists"), ",") {
+		if listID = strings.TrimSpace(listID); listID != "" {
+			listIDs = append(listIDs, listID)
+		}
+	}
+	return listIDs
+}
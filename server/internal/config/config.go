@@ -1,13 +1,34 @@
 package config
 
 import (
+	"log"
 	"os"
+
+	"github.com/yair12/lists-viewer/server/internal/webpush"
 )
 
 type Config struct {
 	ServerPort   string
 	MongoDBURI   string
 	DatabaseName string
+
+	// ReminderWebhookURL receives due-date reminders as JSON when set
+	ReminderWebhookURL string
+
+	// VAPIDKeys identify the server to browser push services; push notifications are off when they are not set
+	VAPIDKeys    *webpush.VAPIDKeys
+	VAPIDSubject string
+
+	// SMTPPort enables the SMTP listener for mail sent to list addresses when set
+	SMTPPort     string
+	SMTPHostname string
+
+	// SMTPRelayHost enables email digests, which are sent through this relay from MailFrom
+	SMTPRelayHost     string
+	SMTPRelayPort     string
+	SMTPRelayUsername string
+	SMTPRelayPassword string
+	MailFrom          string
 }
 
 func Load() (*Config, error) {
@@ -15,11 +36,40 @@ func Load() (*Config, error) {
 		ServerPort:   getEnv("SERVER_PORT", "8080"),
 		MongoDBURI:   getEnv("MONGODB_URI", "mongodb://localhost:27017"),
 		DatabaseName: getEnv("DATABASE_NAME", "lists_viewer"),
+
+		ReminderWebhookURL: os.Getenv("REMINDER_WEBHOOK_URL"),
+
+		VAPIDSubject: getEnv("VAPID_SUBJECT", "mailto:admin@localhost"),
+
+		SMTPPort:     os.Getenv("SMTP_LISTEN_PORT"),
+		SMTPHostname: getEnv("SMTP_HOSTNAME", "localhost"),
+
+		SMTPRelayHost:     os.Getenv("SMTP_RELAY_HOST"),
+		SMTPRelayPort:     getEnv("SMTP_RELAY_PORT", "587"),
+		SMTPRelayUsername: os.Getenv("SMTP_RELAY_USERNAME"),
+		SMTPRelayPassword: os.Getenv("SMTP_RELAY_PASSWORD"),
+		MailFrom:          getEnv("MAIL_FROM", "Lists <lists@localhost>"),
+	}
+
+	keys, err := loadVAPIDKeys(os.Getenv("VAPID_PUBLIC_KEY"), os.Getenv("VAPID_PRIVATE_KEY"))
+	if err != nil {
+		return nil, err
 	}
+	cfg.VAPIDKeys = keys
 
 	return cfg, nil
 }
 
+// loadVAPIDKeys parses the configured VAPID key pair, returning nil when none is set
+// Browsers subscribe against the public key, so the keys must stay the same across restarts and are never generated here
+func loadVAPIDKeys(publicKey, privateKey string) (*webpush.VAPIDKeys, error) {
+	if publicKey == "" && privateKey == "" {
+		log.Printf("[CONFIG] VAPID_PUBLIC_KEY and VAPID_PRIVATE_KEY are not set; push notifications are off")
+		return nil, nil
+	}
+	return webpush.ParseVAPIDKeys(publicKey, privateKey)
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -0,0 +1,184 @@
+// Package mailer sends HTML and plain-text email through an SMTP relay (RFC 5321), using STARTTLS when the relay offers it
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Message is an email with a plain-text body and an optional HTML alternative
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer delivers messages to an SMTP relay
+type Mailer struct {
+	Addr     string // host:port of the relay
+	Username string // Authenticates with PLAIN when set
+	Password string
+	From     string // Sender address, optionally with a display name
+	Timeout  time.Duration
+}
+
+// New creates a mailer for the relay at addr
+func New(addr string, username string, password string, from string) *Mailer {
+	return &Mailer{
+		Addr:     addr,
+		Username: username,
+		Password: password,
+		From:     from,
+		Timeout:  30 * time.Second,
+	}
+}
+
+// Send delivers a message to its recipient through the relay
+func (m *Mailer) Send(ctx context.Context, msg Message) error {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", m.From, err)
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", msg.To, err)
+	}
+	data, err := Compose(from, to, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(m.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid relay address %q: %w", m.Addr, err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.Timeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to relay: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return fmt.Errorf("failed to greet relay: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if m.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, host)); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("relay refused sender: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("relay refused recipient: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("relay refused message: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("relay refused message: %w", err)
+	}
+	return client.Quit()
+}
+
+// Compose renders a message in MIME format: multipart/alternative when it has an HTML body, quoted-printable UTF-8 text otherwise
+func Compose(from *mail.Address, to *mail.Address, msg Message, date time.Time) ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate message ID: %w", err)
+	}
+	domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+
+	buf := new(bytes.Buffer)
+	header := [][2]string{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", msg.Subject)},
+		{"Date", date.Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", hex.EncodeToString(id), domain)},
+		{"MIME-Version", "1.0"},
+	}
+
+	if msg.HTML == "" {
+		header = append(header, [2]string{"Content-Type", "text/plain; charset=utf-8"}, [2]string{"Content-Transfer-Encoding", "quoted-printable"})
+		writeHeader(buf, header)
+		if err := writeQuotedPrintable(buf, msg.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	body := new(bytes.Buffer)
+	parts := multipart.NewWriter(body)
+	for _, part := range []struct{ mediaType, content string }{{"text/plain", msg.Text}, {"text/html", msg.HTML}} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.mediaType + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	header = append(header, [2]string{"Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": parts.Boundary()})})
+	writeHeader(buf, header)
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+// writeHeader writes the header fields in order, followed by the blank line ending the header
+func writeHeader(w io.Writer, fields [][2]string) {
+	for _, field := range fields {
+		fmt.Fprintf(w, "%s: %s\r\n", field[0], field[1])
+	}
+	io.WriteString(w, "\r\n")
+}
+
+// writeQuotedPrintable writes text with CRLF line endings in quoted-printable encoding
+func writeQuotedPrintable(w io.Writer, text string) error {
+	qp := quotedprintable.NewWriter(w)
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n")
+	if _, err := qp.Write([]byte(text)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
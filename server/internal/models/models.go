@@ -6,6 +6,24 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// List completion modes
+const (
+	CompletionModeShared    = "shared"     // Completing an item completes it for everyone
+	CompletionModePerMember = "per_member" // Each member completes items individually
+)
+
+// List reset actions
+const (
+	ResetActionUncomplete = "uncomplete" // Uncomplete every item, including the items of nested lists
+	ResetActionTemplate   = "template"   // Replace the items with those of a template
+)
+
+// List reset triggers
+const (
+	ResetTriggerSchedule = "schedule"
+	ResetTriggerManual   = "manual"
+)
+
 // List represents a todo list
 type List struct {
 	ID                 primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
@@ -20,8 +38,47 @@ type List struct {
 	Version            int32              `bson:"version" json:"version"`
 	UserID             string             `bson:"userId" json:"userId"`
 	Archived           bool               `bson:"archived" json:"archived"`
+	CompletionMode     string             `bson:"completionMode,omitempty" json:"completionMode,omitempty"` // "shared" (default) or "per_member"
+	Members            []string           `bson:"members,omitempty" json:"members,omitempty"`               // Users the list is shared with, who each complete items in per_member mode
+	Aliases            []string           `bson:"aliases,omitempty" json:"aliases,omitempty"`               // Other names the list is found by when adding items by name, e.g. "shopping" for "Groceries"
 	ItemCount          int32              `bson:"itemCount" json:"itemCount"`
 	CompletedItemCount int32              `bson:"completedItemCount" json:"completedItemCount"`
+	PartialItemCount   int32              `bson:"partialItemCount" json:"partialItemCount"`
+	ResetSchedule      *ResetSchedule     `bson:"resetSchedule,omitempty" json:"resetSchedule,omitempty"`
+}
+
+// ListSettings are the settings of a top-level list kept while it is nested in another list, restored when it is promoted again
+type ListSettings struct {
+	Color          string         `bson:"color"`
+	CompletionMode string         `bson:"completionMode,omitempty"`
+	Members        []string       `bson:"members,omitempty"`
+	Aliases        []string       `bson:"aliases,omitempty"`
+	ResetSchedule  *ResetSchedule `bson:"resetSchedule,omitempty"`
+}
+
+// ResetSchedule is a recurring reset of a list
+type ResetSchedule struct {
+	Preset     string     `bson:"preset,omitempty" json:"preset,omitempty"` // "daily", "weekly", or empty for a custom cron expression
+	Cron       string     `bson:"cron" json:"cron"`                         // Five-field cron expression, also set for presets
+	TimeZone   string     `bson:"timeZone" json:"timeZone"`                 // IANA time zone the cron expression is evaluated in
+	Action     string     `bson:"action" json:"action"`                     // "uncomplete" or "template"
+	TemplateID string     `bson:"templateId,omitempty" json:"templateId,omitempty"`
+	NextRunAt  time.Time  `bson:"nextRunAt" json:"nextRunAt"`
+	LastRunAt  *time.Time `bson:"lastRunAt,omitempty" json:"lastRunAt,omitempty"`
+}
+
+// ListReset records a reset of a list
+type ListReset struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UUID         string             `bson:"uuid" json:"uuid"`
+	ListID       string             `bson:"listId" json:"listId"`
+	Action       string             `bson:"action" json:"action"`
+	TemplateID   string             `bson:"templateId,omitempty" json:"templateId,omitempty"`
+	Trigger      string             `bson:"trigger" json:"trigger"`                               // "schedule" or "manual"
+	ScheduledFor *time.Time         `bson:"scheduledFor,omitempty" json:"scheduledFor,omitempty"` // Run time of a scheduled reset
+	ItemCount    int32              `bson:"itemCount" json:"itemCount"`                           // Items uncompleted or restored
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+	CreatedBy    string             `bson:"createdBy" json:"createdBy"` // User, or "scheduler"
 }
 
 // Item represents a todo item or nested list
@@ -32,6 +89,9 @@ type Item struct {
 	Type               string             `bson:"type" json:"type"` // "item" or "list"
 	Name               string             `bson:"name" json:"name"`
 	Completed          bool               `bson:"completed" json:"completed"`
+	CompletedAt        *time.Time         `bson:"completedAt,omitempty" json:"completedAt,omitempty"`
+	CompletedBy        string             `bson:"completedBy,omitempty" json:"completedBy,omitempty"`
+	CompletedByUsers   []string           `bson:"completedByUsers,omitempty" json:"completedByUsers,omitempty"` // For per_member lists
 	CreatedAt          time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt          time.Time          `bson:"updatedAt" json:"updatedAt"`
 	CreatedBy          string             `bson:"createdBy" json:"createdBy"`
@@ -40,12 +100,152 @@ type Item struct {
 	Order              int32              `bson:"order" json:"order"`
 	Quantity           *float64           `bson:"quantity,omitempty" json:"quantity,omitempty"`
 	QuantityType       string             `bson:"quantityType,omitempty" json:"quantityType,omitempty"`
+	FulfilledQuantity  *float64           `bson:"fulfilledQuantity,omitempty" json:"fulfilledQuantity,omitempty"` // Amount already bought of Quantity
 	UserIconID         string             `bson:"userIconId" json:"userIconId"`
 	Archived           bool               `bson:"archived" json:"archived"`
 	SyncStatus         string             `bson:"syncStatus" json:"syncStatus"`
-	Description        string             `bson:"description,omitempty" json:"description,omitempty"` // For nested lists
-	ItemCount          int32              `bson:"itemCount" json:"itemCount"`                         // For nested lists
-	CompletedItemCount int32              `bson:"completedItemCount" json:"completedItemCount"`       // For nested lists
+	Description        string             `bson:"description,omitempty" json:"description,omitempty"`       // Notes on an item or nested list
+	ItemCount          int32              `bson:"itemCount" json:"itemCount"`                               // For nested lists
+	CompletedItemCount int32              `bson:"completedItemCount" json:"completedItemCount"`             // For nested lists
+	PartialItemCount   int32              `bson:"partialItemCount" json:"partialItemCount"`                 // For nested lists
+	Recurrence         *Recurrence        `bson:"recurrence,omitempty" json:"recurrence,omitempty"`         // For items that come back after completion
+	DueAt              *time.Time         `bson:"dueAt,omitempty" json:"dueAt,omitempty"`                   // Start of the due day for all-day items
+	AllDay             bool               `bson:"allDay,omitempty" json:"allDay,omitempty"`                 // Due on a date rather than at a time
+	ReminderOffset     *int32             `bson:"reminderOffset,omitempty" json:"reminderOffset,omitempty"` // Minutes before DueAt to send a reminder
+	RemindAt           *time.Time         `bson:"remindAt,omitempty" json:"remindAt,omitempty"`             // Pending reminder, cleared once sent
+	AssignedTo         string             `bson:"assignedTo,omitempty" json:"assignedTo,omitempty"`         // Username of the user responsible for the item
+	ListSettings       *ListSettings      `bson:"listSettings,omitempty" json:"-"`                          // For nested lists demoted from top-level lists
+}
+
+// Recurrence makes a completed item reopen at its next occurrence
+type Recurrence struct {
+	Rule      string     `bson:"rule" json:"rule"`                               // RRULE subset, e.g. "FREQ=WEEKLY;BYDAY=MO,TH"
+	TimeZone  string     `bson:"timeZone" json:"timeZone"`                       // IANA time zone occurrence days are counted in
+	AnchorDay int        `bson:"anchorDay,omitempty" json:"anchorDay,omitempty"` // Day of the month MONTHLY rules without BYMONTHDAY recur on, taken when the rule is set
+	NextAt    *time.Time `bson:"nextAt,omitempty" json:"nextAt,omitempty"`       // When the completed item reopens
+}
+
+// ListTree is a list loaded together with its items
+type ListTree struct {
+	List  `bson:",inline"`
+	Items []ItemTree `bson:"items"`
+}
+
+// ItemTree is an item loaded together with its children, for nested lists
+type ItemTree struct {
+	Item     `bson:",inline"`
+	Children []Item `bson:"children"`
+}
+
+// Template is a reusable blueprint of a list, stored apart from lists
+type Template struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UUID           string             `bson:"uuid" json:"uuid"`
+	Name           string             `bson:"name" json:"name"` // May contain variables such as {date}
+	Description    string             `bson:"description" json:"description"`
+	Color          string             `bson:"color" json:"color"`
+	CompletionMode string             `bson:"completionMode,omitempty" json:"completionMode,omitempty"`
+	Items          []TemplateItem     `bson:"items" json:"items"`
+	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt"`
+	CreatedBy      string             `bson:"createdBy" json:"createdBy"`
+	UpdatedBy      string             `bson:"updatedBy" json:"updatedBy"`
+	Version        int32              `bson:"version" json:"version"`
+	UserID         string             `bson:"userId" json:"userId"`
+}
+
+// TemplateItem is an item or nested list of a template, in display order
+type TemplateItem struct {
+	Type         string         `bson:"type" json:"type"` // "item" or "list"
+	Name         string         `bson:"name" json:"name"` // May contain variables such as {date}
+	Description  string         `bson:"description,omitempty" json:"description,omitempty"`
+	Quantity     *float64       `bson:"quantity,omitempty" json:"quantity,omitempty"`
+	QuantityType string         `bson:"quantityType,omitempty" json:"quantityType,omitempty"`
+	Children     []TemplateItem `bson:"children,omitempty" json:"children,omitempty"` // For nested lists
+	Recurrence   *Recurrence    `bson:"recurrence,omitempty" json:"recurrence,omitempty"`
+}
+
+// PushSubscription is a Web Push subscription of one of a user's browsers or devices
+type PushSubscription struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UUID       string             `bson:"uuid" json:"uuid"`
+	UserID     string             `bson:"userId" json:"userId"`
+	Endpoint   string             `bson:"endpoint" json:"endpoint"` // Push service URL, unique per browser subscription
+	P256dh     string             `bson:"p256dh" json:"p256dh"`     // Browser public key, base64url
+	Auth       string             `bson:"auth" json:"auth"`         // Browser authentication secret, base64url
+	DeviceName string             `bson:"deviceName,omitempty" json:"deviceName,omitempty"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// InboundToken authorizes adding items through the inbound quick-add endpoint on behalf of a user
+// Only a hash of the token is stored; the token itself is shown once, when it is created
+type InboundToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UUID       string             `bson:"uuid" json:"uuid"`
+	UserID     string             `bson:"userId" json:"userId"`
+	Name       string             `bson:"name" json:"name"`                           // What the token is used by, e.g. "Siri"
+	TokenHash  string             `bson:"tokenHash" json:"-"`                         // Hex SHA-256 of the token
+	ListID     string             `bson:"listId,omitempty" json:"listId,omitempty"`   // List used when the text names none
+	Senders    []string           `bson:"senders,omitempty" json:"senders,omitempty"` // Lowercase email addresses allowed to mail items with the token
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+	LastUsedAt *time.Time         `bson:"lastUsedAt,omitempty" json:"lastUsedAt,omitempty"`
+}
+
+// Webhook event types
+const (
+	WebhookEventItemCreated     = "item.created"
+	WebhookEventItemUpdated     = "item.updated"
+	WebhookEventItemCompleted   = "item.completed"
+	WebhookEventItemUncompleted = "item.uncompleted"
+	WebhookEventItemDeleted     = "item.deleted"
+)
+
+// WebhookEventTypes lists every event type a webhook can subscribe to
+var WebhookEventTypes = []string{
+	WebhookEventItemCreated, WebhookEventItemUpdated, WebhookEventItemCompleted, WebhookEventItemUncompleted, WebhookEventItemDeleted,
+}
+
+// Webhook delivery statuses
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+)
+
+// Webhook posts signed events about item changes to a URL
+// It covers one list, or with no ListID every list its owner created or is a member of
+type Webhook struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UUID      string             `bson:"uuid" json:"uuid"`
+	UserID    string             `bson:"userId" json:"userId"`
+	ListID    string             `bson:"listId,omitempty" json:"listId,omitempty"`
+	URL       string             `bson:"url" json:"url"`
+	Secret    string             `bson:"secret" json:"-"`      // HMAC-SHA256 key for the signature header
+	Events    []string           `bson:"events" json:"events"` // Event types to send, all when empty
+	Active    bool               `bson:"active" json:"active"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+	Version   int32              `bson:"version" json:"version"`
+}
+
+// WebhookDelivery is one event queued for a webhook, kept as a delivery log once attempted
+type WebhookDelivery struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UUID           string             `bson:"uuid" json:"uuid"`
+	WebhookID      string             `bson:"webhookId" json:"webhookId"`
+	EventID        string             `bson:"eventId" json:"eventId"` // Shared by redeliveries of the same event
+	Event          string             `bson:"event" json:"event"`
+	Payload        string             `bson:"payload" json:"payload"` // JSON body, fixed when the event happened
+	Status         string             `bson:"status" json:"status"`   // "pending", "delivered" or "failed"
+	Attempts       int                `bson:"attempts" json:"attempts"`
+	NextAttemptAt  *time.Time         `bson:"nextAttemptAt,omitempty" json:"nextAttemptAt,omitempty"` // For pending deliveries
+	LastAttemptAt  *time.Time         `bson:"lastAttemptAt,omitempty" json:"lastAttemptAt,omitempty"`
+	LastStatusCode int                `bson:"lastStatusCode,omitempty" json:"lastStatusCode,omitempty"`
+	LastError      string             `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	RedeliveryOf   string             `bson:"redeliveryOf,omitempty" json:"redeliveryOf,omitempty"` // Delivery this one manually repeats
+	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
+	DeliveredAt    *time.Time         `bson:"deliveredAt,omitempty" json:"deliveredAt,omitempty"`
 }
 
 // User represents a user/profile
@@ -58,12 +258,16 @@ type User struct {
 	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
 	LastActivity time.Time          `bson:"lastActivity" json:"lastActivity"`
 	Preferences  UserPreferences    `bson:"preferences" json:"preferences"`
+	DigestSentAt *time.Time         `bson:"digestSentAt,omitempty" json:"-"` // When the last email digest was sent
 }
 
 // UserPreferences stores user preferences
 type UserPreferences struct {
 	Theme    string `bson:"theme" json:"theme"` // "light" or "dark"
 	Language string `bson:"language" json:"language"`
+	TimeZone string `bson:"timeZone,omitempty" json:"timeZone,omitempty"` // IANA time zone for due dates and reminders, defaults to UTC
+	Email    string `bson:"email,omitempty" json:"email,omitempty"`       // Where email digests are sent
+	Digest   string `bson:"digest,omitempty" json:"digest,omitempty"`     // "daily" or "weekly" email digest; empty when off
 }
 
 // Icon represents an available icon
@@ -75,17 +279,86 @@ type Icon struct {
 
 // CreateListRequest represents a request to create a list
 type CreateListRequest struct {
-	Name        string `json:"name" binding:"required,min=1,max=255"`
-	Description string `json:"description" binding:"max=500"`
-	Color       string `json:"color" binding:"max=7"`
+	Name           string   `json:"name" binding:"required,min=1,max=255"`
+	Description    string   `json:"description" binding:"max=500"`
+	Color          string   `json:"color" binding:"max=7"`
+	CompletionMode string   `json:"completionMode,omitempty" binding:"omitempty,oneof=shared per_member"`
+	Members        []string `json:"members,omitempty"`
+	Aliases        []string `json:"aliases,omitempty"`
+}
+
+// CloneListRequest represents a request to deep-copy a list with its items and nested lists
+type CloneListRequest struct {
+	Name            string `json:"name,omitempty" binding:"max=255"` // Defaults to the source name with a "(copy)" suffix
+	OwnerID         string `json:"ownerId,omitempty"`                // Owner of the copy, defaults to the caller
+	TargetListID    string `json:"targetListId,omitempty"`           // Workspace: a top-level list the copy is nested in instead of becoming a top-level list
+	ResetCompletion bool   `json:"resetCompletion,omitempty"`        // Copy every item as open
+	DropQuantities  bool   `json:"dropQuantities,omitempty"`         // Copy items without quantities
+}
+
+// CreateTemplateRequest represents a request to create a template
+// With ListID the template is saved from that list, and omitted fields are taken from it
+type CreateTemplateRequest struct {
+	ListID         string         `json:"listId,omitempty"`
+	Name           string         `json:"name,omitempty" binding:"max=255"`
+	Description    string         `json:"description,omitempty" binding:"max=500"`
+	Color          string         `json:"color,omitempty" binding:"max=7"`
+	CompletionMode string         `json:"completionMode,omitempty" binding:"omitempty,oneof=shared per_member"`
+	Items          []TemplateItem `json:"items,omitempty"` // Ignored with ListID
+}
+
+// UpdateTemplateRequest represents a request to update a template
+// Items are left unchanged when omitted
+type UpdateTemplateRequest struct {
+	Name           string          `json:"name" binding:"required,min=1,max=255"`
+	Description    string          `json:"description" binding:"max=500"`
+	Color          string          `json:"color" binding:"max=7"`
+	CompletionMode string          `json:"completionMode,omitempty" binding:"omitempty,oneof=shared per_member"`
+	Items          *[]TemplateItem `json:"items,omitempty"`
+	Version        int32           `json:"version" binding:"required"`
+}
+
+// DeleteTemplateRequest represents a request to delete a template
+type DeleteTemplateRequest struct {
+	Version int32 `json:"version" binding:"required"`
+}
+
+// InstantiateTemplateRequest represents a request to create a list from a template
+type InstantiateTemplateRequest struct {
+	Name      string            `json:"name,omitempty" binding:"max=255"` // Defaults to the template name; may contain variables
+	Date      string            `json:"date,omitempty"`                   // YYYY-MM-DD used for date variables, defaults to today
+	Variables map[string]string `json:"variables,omitempty"`              // Custom variables, overriding the built-in ones
+}
+
+// SetResetScheduleRequest represents a request to schedule recurring resets of a list
+// A preset ("daily" with Time, "weekly" with Weekday and Time) or a custom Cron expression is required
+type SetResetScheduleRequest struct {
+	Preset     string `json:"preset,omitempty" binding:"omitempty,oneof=daily weekly"`
+	Cron       string `json:"cron,omitempty"`
+	Time       string `json:"time,omitempty"`                                                 // HH:MM, for presets
+	Weekday    string `json:"weekday,omitempty"`                                              // Name or number (0 is Sunday), for the weekly preset
+	TimeZone   string `json:"timeZone,omitempty"`                                             // Defaults to UTC
+	Action     string `json:"action,omitempty" binding:"omitempty,oneof=uncomplete template"` // Defaults to uncomplete
+	TemplateID string `json:"templateId,omitempty"`                                           // Required for the template action
+}
+
+// ResetListRequest represents a request to reset a list now
+// Action and TemplateID default to the list's schedule, or to uncompleting items
+type ResetListRequest struct {
+	Action     string `json:"action,omitempty" binding:"omitempty,oneof=uncomplete template"`
+	TemplateID string `json:"templateId,omitempty"`
 }
 
 // UpdateListRequest represents a request to update a list
+// CompletionMode and Members are left unchanged when omitted
 type UpdateListRequest struct {
-	Name        string `json:"name" binding:"required,min=1,max=255"`
-	Description string `json:"description" binding:"max=500"`
-	Color       string `json:"color" binding:"max=7"`
-	Version     int32  `json:"version" binding:"required"`
+	Name           string   `json:"name" binding:"required,min=1,max=255"`
+	Description    string   `json:"description" binding:"max=500"`
+	Color          string   `json:"color" binding:"max=7"`
+	CompletionMode string   `json:"completionMode,omitempty" binding:"omitempty,oneof=shared per_member"`
+	Members        []string `json:"members,omitempty"`
+	Aliases        []string `json:"aliases,omitempty"`
+	Version        int32    `json:"version" binding:"required"`
 }
 
 // DeleteListRequest represents a request to delete a list
@@ -95,12 +368,24 @@ type DeleteListRequest struct {
 
 // CreateItemRequest represents a request to create an item
 type CreateItemRequest struct {
-	Type         string   `json:"type" binding:"required,oneof=item list"`
-	Name         string   `json:"name" binding:"required,min=1,max=255"`
-	Quantity     *float64 `json:"quantity,omitempty" binding:"omitempty,gt=0"`
-	QuantityType string   `json:"quantityType,omitempty" binding:"max=50"`
-	UserIconID   string   `json:"userIconId"`
-	Description  string   `json:"description,omitempty" binding:"max=500"`
+	Type           string                `json:"type" binding:"required,oneof=item list"`
+	Name           string                `json:"name" binding:"required,min=1,max=255"`
+	Quantity       *float64              `json:"quantity,omitempty" binding:"omitempty,gt=0"`
+	QuantityType   string                `json:"quantityType,omitempty" binding:"max=50"`
+	UserIconID     string                `json:"userIconId"`
+	Description    string                `json:"description,omitempty" binding:"max=500"`
+	FoldUnicode    bool                  `json:"foldUnicode,omitempty"` // Ignore accents and diacritics when reporting duplicates
+	Recurrence     *SetRecurrenceRequest `json:"recurrence,omitempty"`
+	Due            string                `json:"due,omitempty"`            // RFC 3339 time, or YYYY-MM-DD for an all-day item in the caller's time zone
+	ReminderOffset *int32                `json:"reminderOffset,omitempty"` // Minutes before the due time to send a reminder
+}
+
+// QuickAddItemRequest represents a request to create an item from free text such as "2kg tomatoes"
+type QuickAddItemRequest struct {
+	Text        string `json:"text" binding:"required,min=1,max=255"`
+	UserIconID  string `json:"userIconId"`
+	Preview     bool   `json:"preview,omitempty"`     // Only parse the text, without creating the item
+	FoldUnicode bool   `json:"foldUnicode,omitempty"` // Ignore accents and diacritics when reporting duplicates
 }
 
 // UpdateItemRequest represents a request to update an item
@@ -114,6 +399,26 @@ type UpdateItemRequest struct {
 	Description  string   `json:"description,omitempty" binding:"max=500"`
 }
 
+// FulfillItemRequest represents a request to record a partially bought quantity of an item
+type FulfillItemRequest struct {
+	Quantity float64 `json:"quantity" binding:"required,gt=0"` // Amount fulfilled now, added to the fulfilled quantity
+	Version  int32   `json:"version" binding:"required"`
+}
+
+// SetRecurrenceRequest represents a request to make an item recur
+type SetRecurrenceRequest struct {
+	Rule     string `json:"rule" binding:"required"` // RRULE subset: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY, BYMONTHDAY
+	TimeZone string `json:"timeZone,omitempty"`      // Defaults to UTC
+	Version  int32  `json:"version"`                 // Required when updating an existing item
+}
+
+// SetDueDateRequest represents a request to set the due date of an item
+type SetDueDateRequest struct {
+	Due            string `json:"due" binding:"required"`   // RFC 3339 time, or YYYY-MM-DD for an all-day item in the caller's time zone
+	ReminderOffset *int32 `json:"reminderOffset,omitempty"` // Minutes before the due time to send a reminder; omitted for no reminder
+	Version        int32  `json:"version" binding:"required"`
+}
+
 // DeleteItemRequest represents a request to delete an item
 type DeleteItemRequest struct {
 	Version int32 `json:"version" binding:"required"`
@@ -130,14 +435,101 @@ type ReorderItem struct {
 	Order int32  `json:"order" binding:"required"`
 }
 
+// BulkItemRef identifies an item in a bulk operation.
+// When Version is set the item is only changed if it still has that version.
+type BulkItemRef struct {
+	ID      string `json:"id" binding:"required"`
+	Version *int32 `json:"version,omitempty"`
+}
+
 // BulkCompleteRequest represents a request to complete multiple items
+// Either ItemIDs (no version checks) or Items (optional per-item versions) may be used
 type BulkCompleteRequest struct {
-	ItemIDs []string `json:"itemIds" binding:"required,min=1"`
+	ItemIDs []string      `json:"itemIds,omitempty"`
+	Items   []BulkItemRef `json:"items,omitempty"`
 }
 
 // BulkDeleteRequest represents a request to delete multiple items
+// Either ItemIDs (no version checks) or Items (optional per-item versions) may be used
 type BulkDeleteRequest struct {
-	ItemIDs []string `json:"itemIds" binding:"required,min=1"`
+	ItemIDs []string      `json:"itemIds,omitempty"`
+	Items   []BulkItemRef `json:"items,omitempty"`
+}
+
+// BulkUncompleteRequest represents a request to return multiple completed items to open
+// Either ItemIDs (no version checks) or Items (optional per-item versions) may be used
+type BulkUncompleteRequest struct {
+	ItemIDs []string      `json:"itemIds,omitempty"`
+	Items   []BulkItemRef `json:"items,omitempty"`
+}
+
+// BulkMoveRequest represents a request to move multiple items to another list
+// Either ItemIDs (no version checks) or Items (optional per-item versions) may be used
+type BulkMoveRequest struct {
+	TargetListID string        `json:"targetListId" binding:"required"`
+	ItemIDs      []string      `json:"itemIds,omitempty"`
+	Items        []BulkItemRef `json:"items,omitempty"`
+}
+
+// BulkUpdateRequest represents a request to set fields on multiple items
+// Only fields that are present are changed
+type BulkUpdateRequest struct {
+	ItemIDs      []string      `json:"itemIds,omitempty"`
+	Items        []BulkItemRef `json:"items,omitempty"`
+	Quantity     *float64      `json:"quantity,omitempty" binding:"omitempty,gt=0"`
+	QuantityType *string       `json:"quantityType,omitempty" binding:"omitempty,max=50"`
+}
+
+// MergeItemsRequest represents a request to merge duplicate items into one
+// Items lists every item to merge, including the kept one
+type MergeItemsRequest struct {
+	KeepID string        `json:"keepId" binding:"required"`
+	Items  []BulkItemRef `json:"items" binding:"required,min=2"`
+}
+
+// CompleteShoppingEntryRequest represents a request to complete every source item of a shopping view entry
+type CompleteShoppingEntryRequest struct {
+	Lists []string `json:"lists" binding:"required,min=1"` // Lists the shopping view was built from
+	Key   string   `json:"key" binding:"required"`         // Key of the grouped entry
+}
+
+// ItemQuery filters, sorts and pages the items of a list
+type ItemQuery struct {
+	IncludeArchived bool
+	Completed       *bool  // Only completed or only open items
+	CompletedBy     string // Evaluate Completed for this user instead of the item (per_member lists)
+	Type            string // "item" or "list"
+	NamePrefix      string // Case-insensitive name prefix
+	SortBy          string // "order", "name", "createdAt", "updatedAt" or "completedAt"; empty lists open items first
+	Descending      bool   // Reverse SortBy; ignored for the default order
+	Cursor          string // Next cursor returned with the previous page
+	Limit           int64  // Page size, 0 for every item
+}
+
+// SearchRequest represents the query parameters of a search across lists
+type SearchRequest struct {
+	Query     string // Text to find in names and descriptions
+	Completed *bool  // Only items with this completion state
+	Type      string // "item" or "list"
+	ListID    string // Only items within this list and its nested lists
+	Limit     int
+}
+
+// ConvertItemRequest represents a request to convert an item into a nested list or back
+type ConvertItemRequest struct {
+	Type    string `json:"type" binding:"required,oneof=item list"` // Type to convert to
+	Version int32  `json:"version" binding:"required"`
+}
+
+// PromoteNestedListRequest represents a request to turn a nested list into a top-level list
+type PromoteNestedListRequest struct {
+	Version int32 `json:"version" binding:"required"` // Version of the nested list item
+}
+
+// DemoteListRequest represents a request to turn a top-level list into a nested list of another list
+type DemoteListRequest struct {
+	TargetListID string `json:"targetListId" binding:"required"`
+	Version      int32  `json:"version" binding:"required"`
 }
 
 // MoveItemRequest represents a request to move an item between lists
@@ -147,6 +539,62 @@ type MoveItemRequest struct {
 	Version      int32  `json:"version" binding:"required"`
 }
 
+// UpdatePreferencesRequest represents a request to update user preferences
+// Omitted fields are left unchanged
+type UpdatePreferencesRequest struct {
+	Theme    string `json:"theme,omitempty" binding:"omitempty,oneof=light dark"`
+	Language string `json:"language,omitempty"`
+	TimeZone string `json:"timeZone,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Digest   string `json:"digest,omitempty" binding:"omitempty,oneof=off daily weekly"`
+}
+
+// RegisterPushSubscriptionRequest registers a browser for push notifications
+// Endpoint and Keys are the output of PushSubscription.toJSON() in the browser
+type RegisterPushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint" binding:"required,url"`
+	Keys     struct {
+		P256dh string `json:"p256dh" binding:"required"`
+		Auth   string `json:"auth" binding:"required"`
+	} `json:"keys"`
+	DeviceName string `json:"deviceName,omitempty" binding:"max=100"`
+}
+
+// CreateInboundTokenRequest represents a request to create an inbound quick-add token
+type CreateInboundTokenRequest struct {
+	Name    string   `json:"name" binding:"required,max=100"`
+	ListID  string   `json:"listId,omitempty"`  // Default list for texts that name none
+	Senders []string `json:"senders,omitempty"` // Email addresses allowed to mail items with the token; without any, mail is rejected
+}
+
+// InboundQuickAddRequest is the JSON form of an inbound quick-add, e.g. {"text": "2 kg tomatoes", "list": "groceries"}
+// Without List the text may name the list itself ("eggs to groceries"), or the token's default list is used
+type InboundQuickAddRequest struct {
+	Text string `json:"text" binding:"required"`
+	List string `json:"list,omitempty"` // List name or alias
+}
+
+// CreateWebhookRequest represents a request to create a webhook
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url"`
+	ListID string   `json:"listId,omitempty"` // Omitted for every list of the caller
+	Events []string `json:"events,omitempty"` // Omitted for every event type
+}
+
+// UpdateWebhookRequest represents a request to update a webhook
+type UpdateWebhookRequest struct {
+	URL     string   `json:"url" binding:"required,url"`
+	ListID  string   `json:"listId,omitempty"`
+	Events  []string `json:"events,omitempty"`
+	Active  bool     `json:"active"`
+	Version int32    `json:"version" binding:"required"`
+}
+
+// DeleteWebhookRequest represents a request to delete a webhook
+type DeleteWebhookRequest struct {
+	Version int32 `json:"version" binding:"required"`
+}
+
 // InitUserRequest represents a request to initialize/create a user
 type InitUserRequest struct {
 	Username string `json:"username" binding:"required,min=1,max=255"`
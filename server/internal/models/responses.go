@@ -1,5 +1,10 @@
 package models
 
+import (
+	"github.com/yair12/lists-viewer/server/internal/quickadd"
+	"github.com/yair12/lists-viewer/server/internal/units"
+)
+
 // APIError represents a standard API error response
 type APIError struct {
 	Error   string      `json:"error"`
@@ -9,17 +14,23 @@ type APIError struct {
 
 // ListResponse represents a response containing a single list
 type ListResponse struct {
-	ID                 string `json:"id"`
-	Name               string `json:"name"`
-	Description        string `json:"description"`
-	Color              string `json:"color"`
-	CreatedAt          string `json:"createdAt"`
-	UpdatedAt          string `json:"updatedAt"`
-	CreatedBy          string `json:"createdBy"`
-	UpdatedBy          string `json:"updatedBy"`
-	Version            int32  `json:"version"`
-	ItemCount          int32  `json:"itemCount"`
-	CompletedItemCount int32  `json:"completedItemCount"`
+	ID                 string         `json:"id"`
+	Name               string         `json:"name"`
+	Description        string         `json:"description"`
+	Color              string         `json:"color"`
+	CreatedAt          string         `json:"createdAt"`
+	UpdatedAt          string         `json:"updatedAt"`
+	CreatedBy          string         `json:"createdBy"`
+	UpdatedBy          string         `json:"updatedBy"`
+	Version            int32          `json:"version"`
+	CompletionMode     string         `json:"completionMode"`
+	Members            []string       `json:"members,omitempty"`
+	Aliases            []string       `json:"aliases,omitempty"`
+	ItemCount          int32          `json:"itemCount"`
+	CompletedItemCount int32          `json:"completedItemCount"`
+	PartialItemCount   int32          `json:"partialItemCount"`
+	Items              []ItemResponse `json:"items,omitempty"` // With ?expand=items
+	ResetSchedule      *ResetSchedule `json:"resetSchedule,omitempty"`
 }
 
 // ListsResponse represents a response containing multiple lists
@@ -27,38 +38,303 @@ type ListsResponse struct {
 	Data []ListResponse `json:"data"`
 }
 
+// ListResetResponse represents a response containing a single list reset
+type ListResetResponse struct {
+	ID           string `json:"id"`
+	ListID       string `json:"listId"`
+	Action       string `json:"action"`
+	TemplateID   string `json:"templateId,omitempty"`
+	Trigger      string `json:"trigger"`
+	ScheduledFor string `json:"scheduledFor,omitempty"`
+	ItemCount    int32  `json:"itemCount"`
+	CreatedAt    string `json:"createdAt"`
+	CreatedBy    string `json:"createdBy"`
+}
+
+// ListResetsResponse represents a response containing the reset history of a list, newest first
+type ListResetsResponse struct {
+	Data []ListResetResponse `json:"data"`
+}
+
+// TemplateResponse represents a response containing a single template
+type TemplateResponse struct {
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	Description    string         `json:"description"`
+	Color          string         `json:"color"`
+	CompletionMode string         `json:"completionMode"`
+	Items          []TemplateItem `json:"items"`
+	ItemCount      int32          `json:"itemCount"` // Top-level items and nested lists
+	CreatedAt      string         `json:"createdAt"`
+	UpdatedAt      string         `json:"updatedAt"`
+	CreatedBy      string         `json:"createdBy"`
+	UpdatedBy      string         `json:"updatedBy"`
+	Version        int32          `json:"version"`
+}
+
+// TemplatesResponse represents a response containing multiple templates
+type TemplatesResponse struct {
+	Data []TemplateResponse `json:"data"`
+}
+
 // ItemResponse represents a response containing a single item
 type ItemResponse struct {
-	ID                 string   `json:"id"`
-	ListID             string   `json:"listId"`
-	Type               string   `json:"type"`
-	Name               string   `json:"name"`
-	Completed          bool     `json:"completed"`
-	CreatedAt          string   `json:"createdAt"`
-	UpdatedAt          string   `json:"updatedAt"`
-	CreatedBy          string   `json:"createdBy"`
-	UpdatedBy          string   `json:"updatedBy"`
-	Version            int32    `json:"version"`
-	Order              int32    `json:"order"`
-	Quantity           *float64 `json:"quantity,omitempty"`
-	QuantityType       string   `json:"quantityType,omitempty"`
-	UserIconID         string   `json:"userIconId"`
-	Description        string   `json:"description,omitempty"`
-	ItemCount          int32    `json:"itemCount,omitempty"`
-	CompletedItemCount int32    `json:"completedItemCount,omitempty"`
+	ID                 string          `json:"id"`
+	ListID             string          `json:"listId"`
+	Type               string          `json:"type"`
+	Name               string          `json:"name"`
+	Completed          bool            `json:"completed"` // For per_member lists, the caller's completion state
+	CompletedAt        string          `json:"completedAt,omitempty"`
+	CompletedBy        string          `json:"completedBy,omitempty"`
+	CreatedAt          string          `json:"createdAt"`
+	UpdatedAt          string          `json:"updatedAt"`
+	CreatedBy          string          `json:"createdBy"`
+	UpdatedBy          string          `json:"updatedBy"`
+	Version            int32           `json:"version"`
+	Order              int32           `json:"order"`
+	Quantity           *float64        `json:"quantity,omitempty"`
+	QuantityType       string          `json:"quantityType,omitempty"`
+	FulfilledQuantity  *float64        `json:"fulfilledQuantity,omitempty"`
+	UserIconID         string          `json:"userIconId"`
+	Description        string          `json:"description,omitempty"`
+	ItemCount          int32           `json:"itemCount,omitempty"`
+	CompletedItemCount int32           `json:"completedItemCount,omitempty"`
+	PartialItemCount   int32           `json:"partialItemCount,omitempty"`
+	Recurrence         *Recurrence     `json:"recurrence,omitempty"`
+	DueAt              string          `json:"dueAt,omitempty"`
+	AllDay             bool            `json:"allDay,omitempty"`
+	ReminderOffset     *int32          `json:"reminderOffset,omitempty"`
+	RemindAt           string          `json:"remindAt,omitempty"`
+	AssignedTo         string          `json:"assignedTo,omitempty"`
+	CompletedByUsers   []string        `json:"completedByUsers,omitempty"`   // For per_member lists
+	CompletedUserCount int             `json:"completedUserCount,omitempty"` // For per_member lists
+	MemberCount        int             `json:"memberCount,omitempty"`        // For per_member lists
+	Duplicates         []DuplicateItem `json:"duplicates,omitempty"`         // Likely duplicates in the list, reported on create
+	Children           []ItemResponse  `json:"children,omitempty"`           // Items of a nested list, with ?expand=children
+}
+
+// DuplicateItem identifies an existing item whose name matches another item
+type DuplicateItem struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Completed bool   `json:"completed"`
+	Version   int32  `json:"version"`
+}
+
+// DuplicateGroup represents items in a list sharing the same normalized name
+type DuplicateGroup struct {
+	Name  string         `json:"name"`
+	Items []ItemResponse `json:"items"`
+}
+
+// DuplicatesResponse represents a response containing duplicate item groups
+type DuplicatesResponse struct {
+	Data []DuplicateGroup `json:"data"`
+}
+
+// MergeItemsResponse represents a response from merge operation
+type MergeItemsResponse struct {
+	Data       ItemResponse `json:"data"`
+	DeletedIDs []string     `json:"deletedIds"`
+}
+
+// QuickAddItemResponse represents a response from quick-add: the parsed text and the created item
+type QuickAddItemResponse struct {
+	Parsed quickadd.Result `json:"parsed"`
+	Item   *ItemResponse   `json:"item,omitempty"` // Omitted for previews
+}
+
+// ShoppingQuantity represents a summed quantity of a shopping view entry
+type ShoppingQuantity struct {
+	Quantity     float64 `json:"quantity"`
+	QuantityType string  `json:"quantityType,omitempty"`
+}
+
+// ShoppingSource links a shopping view entry back to one of its source items
+type ShoppingSource struct {
+	ID           string   `json:"id"`
+	ListID       string   `json:"listId"`
+	ListName     string   `json:"listName"`
+	Quantity     *float64 `json:"quantity,omitempty"` // Remaining quantity, after partial fulfillment
+	QuantityType string   `json:"quantityType,omitempty"`
+	Version      int32    `json:"version"`
+}
+
+// ShoppingEntry represents open items with the same name across the lists of a shopping view
+// Quantities holds one sum per group of convertible units
+type ShoppingEntry struct {
+	Key        string             `json:"key"`
+	Name       string             `json:"name"`
+	Quantities []ShoppingQuantity `json:"quantities"`
+	Sources    []ShoppingSource   `json:"sources"`
+}
+
+// ShoppingViewResponse represents a response containing the aggregated shopping view
+type ShoppingViewResponse struct {
+	Data []ShoppingEntry `json:"data"`
+}
+
+// Search result kinds
+const (
+	SearchKindList = "list" // A top-level list
+	SearchKindItem = "item" // An item or nested list
+)
+
+// SearchRange marks a matched part of a field, in characters; End is exclusive
+type SearchRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// SearchHighlight lists the matched parts of a field of a search result
+type SearchHighlight struct {
+	Field  string        `json:"field"` // "name" or "description"
+	Ranges []SearchRange `json:"ranges"`
+}
+
+// SearchResult represents a list or item matching a search
+type SearchResult struct {
+	Kind       string            `json:"kind"`
+	List       *ListResponse     `json:"list,omitempty"`
+	Item       *ItemResponse     `json:"item,omitempty"`
+	ListName   string            `json:"listName,omitempty"` // Name of the list or nested list containing the item
+	Highlights []SearchHighlight `json:"highlights"`
+}
+
+// SearchResponse represents a response containing search results
+type SearchResponse struct {
+	Data []SearchResult `json:"data"`
+	Mode string         `json:"mode"` // "text" when the text index was used, "substring" for the fallback
 }
 
 // ItemsResponse represents a response containing multiple items
 type ItemsResponse struct {
-	Data []ItemResponse `json:"data"`
+	Data       []ItemResponse `json:"data"`
+	NextCursor string         `json:"nextCursor,omitempty"` // Set when more items are available
 }
 
 // UserResponse represents a response containing user info
 type UserResponse struct {
-	ID       string `json:"id"`
-	Username string `json:"username"`
-	IconID   string `json:"iconId"`
-	Color    string `json:"color"`
+	ID          string          `json:"id"`
+	Username    string          `json:"username"`
+	IconID      string          `json:"iconId"`
+	Color       string          `json:"color"`
+	Preferences UserPreferences `json:"preferences"`
+}
+
+// PushSubscriptionResponse represents a registered push subscription
+type PushSubscriptionResponse struct {
+	ID         string `json:"id"`
+	Endpoint   string `json:"endpoint"`
+	DeviceName string `json:"deviceName,omitempty"`
+	CreatedAt  string `json:"createdAt"`
+	UpdatedAt  string `json:"updatedAt"`
+}
+
+// PushSubscriptionsResponse represents a response containing a user's push subscriptions
+type PushSubscriptionsResponse struct {
+	Data []PushSubscriptionResponse `json:"data"`
+}
+
+// PushKeyResponse carries the VAPID public key browsers subscribe with (applicationServerKey)
+type PushKeyResponse struct {
+	PublicKey string `json:"publicKey"`
+}
+
+// InboundTokenResponse represents an inbound quick-add token
+type InboundTokenResponse struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	ListID     string   `json:"listId,omitempty"`
+	Senders    []string `json:"senders,omitempty"`
+	Token      string   `json:"token,omitempty"` // Only returned when the token is created
+	CreatedAt  string   `json:"createdAt"`
+	LastUsedAt string   `json:"lastUsedAt,omitempty"`
+}
+
+// InboundTokensResponse represents a response containing a user's inbound tokens
+type InboundTokensResponse struct {
+	Data []InboundTokenResponse `json:"data"`
+}
+
+// InboundQuickAddResponse is the JSON reply to an inbound quick-add
+type InboundQuickAddResponse struct {
+	Message string        `json:"message"` // Short confirmation to read out, e.g. "Added 2 kg tomatoes to Groceries"
+	ListID  string        `json:"listId"`
+	Item    *ItemResponse `json:"item"`
+}
+
+// WebhookResponse represents a webhook in API responses
+type WebhookResponse struct {
+	ID        string   `json:"id"`
+	URL       string   `json:"url"`
+	ListID    string   `json:"listId,omitempty"`
+	Events    []string `json:"events"`
+	Active    bool     `json:"active"`
+	Secret    string   `json:"secret,omitempty"` // Only returned when the webhook is created
+	CreatedAt string   `json:"createdAt"`
+	UpdatedAt string   `json:"updatedAt"`
+	Version   int32    `json:"version"`
+}
+
+// WebhooksResponse represents a response containing webhooks
+type WebhooksResponse struct {
+	Data []WebhookResponse `json:"data"`
+}
+
+// WebhookDeliveryResponse represents an entry of a webhook's delivery log
+type WebhookDeliveryResponse struct {
+	ID             string `json:"id"`
+	EventID        string `json:"eventId"`
+	Event          string `json:"event"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	NextAttemptAt  string `json:"nextAttemptAt,omitempty"`
+	LastAttemptAt  string `json:"lastAttemptAt,omitempty"`
+	LastStatusCode int    `json:"lastStatusCode,omitempty"`
+	LastError      string `json:"lastError,omitempty"`
+	RedeliveryOf   string `json:"redeliveryOf,omitempty"`
+	CreatedAt      string `json:"createdAt"`
+	DeliveredAt    string `json:"deliveredAt,omitempty"`
+	Payload        string `json:"payload"`
+}
+
+// WebhookDeliveriesResponse represents a response containing a webhook's deliveries, newest first
+type WebhookDeliveriesResponse struct {
+	Data []WebhookDeliveryResponse `json:"data"`
+}
+
+// WebhookEvent is the JSON body posted to webhooks
+type WebhookEvent struct {
+	ID         string           `json:"id"` // Event ID, the same for redeliveries
+	Type       string           `json:"type"`
+	OccurredAt string           `json:"occurredAt"`
+	Actor      string           `json:"actor"`
+	List       WebhookEventList `json:"list"`
+	Item       *ItemResponse    `json:"item"`
+}
+
+// WebhookEventList identifies the top-level list of a webhook event
+type WebhookEventList struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// DueItem is an item with a due date, together with the list it belongs to
+type DueItem struct {
+	Item     *ItemResponse `json:"item"`
+	ListName string        `json:"listName"`
+	Overdue  bool          `json:"overdue"`
+}
+
+// DueItemsResponse represents a response containing items with due dates, soonest first
+type DueItemsResponse struct {
+	Data []DueItem `json:"data"`
+}
+
+// UnitsResponse represents a response containing the known quantity units
+type UnitsResponse struct {
+	Data []units.Unit `json:"data"`
 }
 
 // IconsResponse represents a response containing available icons
@@ -72,15 +348,38 @@ type HealthResponse struct {
 	Database string `json:"database,omitempty"`
 }
 
+// Bulk operation item statuses
+const (
+	BulkStatusDone            = "done"
+	BulkStatusNotFound        = "not_found"
+	BulkStatusVersionConflict = "version_conflict"
+)
+
+// BulkItemResult represents the outcome of a bulk operation for a single item
+type BulkItemResult struct {
+	ID     string        `json:"id"`
+	Status string        `json:"status"`         // "done", "not_found" or "version_conflict"
+	Item   *ItemResponse `json:"item,omitempty"` // Current state of the item (omitted when not found or deleted)
+}
+
 // BulkCompleteResponse represents a response from bulk complete operation
 type BulkCompleteResponse struct {
-	CompletedCount int            `json:"completedCount"`
-	Data           []ItemResponse `json:"data"`
+	CompletedCount int              `json:"completedCount"`
+	Data           []ItemResponse   `json:"data"`
+	Results        []BulkItemResult `json:"results"`
 }
 
 // BulkDeleteResponse represents a response from bulk delete operation
 type BulkDeleteResponse struct {
-	DeletedCount int `json:"deletedCount"`
+	DeletedCount int              `json:"deletedCount"`
+	Results      []BulkItemResult `json:"results,omitempty"`
+}
+
+// BulkUpdateResponse represents a response from bulk uncomplete, move and update operations
+type BulkUpdateResponse struct {
+	UpdatedCount int              `json:"updatedCount"`
+	Data         []ItemResponse   `json:"data"`
+	Results      []BulkItemResult `json:"results"`
 }
 
 // ReorderResponse represents a response from reorder operation
@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Event types
+const (
+	EventReminder  = "item.reminder"
+	EventItemAdded = "item.added"
+)
+
+// Notification is sent to a user about an item: a reminder that it is coming due, or a change made by someone else
+type Notification struct {
+	Type     string     `json:"type"`   // One of the event types
+	UserID   string     `json:"userId"` // The recipient
+	ListID   string     `json:"listId"`
+	ListName string     `json:"listName"`
+	ItemID   string     `json:"itemId"`
+	ItemName string     `json:"itemName"`
+	Actor    string     `json:"actor,omitempty"` // The user whose change caused the notification
+	DueAt    *time.Time `json:"dueAt,omitempty"`
+	AllDay   bool       `json:"allDay,omitempty"`
+	TimeZone string     `json:"timeZone,omitempty"` // The recipient's time zone, which DueText is written in
+	DueText  string     `json:"dueText,omitempty"`  // Human-readable due time, e.g. "Mon Oct 19 08:00" or "Mon Oct 19"
+}
+
+// Title returns a short headline for the notification
+func (n Notification) Title() string {
+	switch n.Type {
+	case EventItemAdded:
+		return fmt.Sprintf("New in %s", n.ListName)
+	}
+	return fmt.Sprintf("%s is due", n.ItemName)
+}
+
+// Body returns the notification text
+func (n Notification) Body() string {
+	switch n.Type {
+	case EventItemAdded:
+		return fmt.Sprintf("%s added %s", n.Actor, n.ItemName)
+	}
+	return fmt.Sprintf("%s in %s is due %s", n.ItemName, n.ListName, n.DueText)
+}
+
+// Notifier delivers notifications to users
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// LogNotifier writes notifications to the server log
+type LogNotifier struct{}
+
+// Notify logs the notification
+func (LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	log.Printf("[NOTIFY] type=%s, userID=%s, title=%q, body=%q", notification.Type, notification.UserID, notification.Title(), notification.Body())
+	return nil
+}
+
+// WebhookNotifier posts notifications as JSON to a URL
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier posting to the given URL
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Notify posts the notification, failing on non-2xx responses
+func (n *WebhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Multi delivers notifications through several notifiers, attempting all of them and joining their errors
+type Multi []Notifier
+
+// Notify delivers the notification through every notifier
+func (m Multi) Notify(ctx context.Context, notification Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/webpush"
+)
+
+// pushTTL is how long push services hold a notification for an offline device
+const pushTTL = 24 * time.Hour
+
+// PushSubscriptions looks up the push subscriptions of users and removes expired ones
+type PushSubscriptions interface {
+	GetByUserID(ctx context.Context, userID string) ([]models.PushSubscription, error)
+	DeleteByEndpoint(ctx context.Context, endpoint string) error
+}
+
+// PushPayload is the JSON message the service worker receives in its push event
+type PushPayload struct {
+	Title        string       `json:"title"`
+	Body         string       `json:"body"`
+	Tag          string       `json:"tag"` // Replaces an earlier notification about the same item
+	URL          string       `json:"url"` // Page to open when the notification is clicked
+	Notification Notification `json:"notification"`
+}
+
+// PushNotifier sends notifications as Web Push messages to every registered device of the recipient
+type PushNotifier struct {
+	subscriptions PushSubscriptions
+	client        *webpush.Client
+}
+
+// NewPushNotifier creates a notifier sending through the given push client
+func NewPushNotifier(subscriptions PushSubscriptions, client *webpush.Client) *PushNotifier {
+	return &PushNotifier{subscriptions: subscriptions, client: client}
+}
+
+// Notify pushes the notification to each device of the recipient
+// Subscriptions the push service reports as gone are removed; other failures are joined and returned
+func (n *PushNotifier) Notify(ctx context.Context, notification Notification) error {
+	subs, err := n.subscriptions.GetByUserID(ctx, notification.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get push subscriptions: %w", err)
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(PushPayload{
+		Title:        notification.Title(),
+		Body:         notification.Body(),
+		Tag:          notification.Type + ":" + notification.ItemID,
+		URL:          "/lists/" + notification.ListID,
+		Notification: notification,
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, sub := range subs {
+		err := n.client.Send(ctx, webpush.Subscription{Endpoint: sub.Endpoint, P256dh: sub.P256dh, Auth: sub.Auth}, payload, pushTTL)
+		switch {
+		case errors.Is(err, webpush.ErrSubscriptionGone):
+			log.Printf("[NOTIFY_PUSH] Removing expired subscription: userID=%s, subscription=%s", sub.UserID, sub.UUID)
+			if err := n.subscriptions.DeleteByEndpoint(ctx, sub.Endpoint); err != nil {
+				errs = append(errs, err)
+			}
+		case err != nil:
+			errs = append(errs, fmt.Errorf("push to subscription %s failed: %w", sub.UUID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,177 @@
+package quickadd
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yair12/lists-viewer/server/internal/units"
+)
+
+// ErrNoName is returned when the text contains only a quantity
+var ErrNoName = errors.New("item name is required")
+
+// Result is the item parsed from a quick-add text
+type Result struct {
+	Name         string   `json:"name"`
+	Quantity     *float64 `json:"quantity,omitempty"`
+	QuantityType string   `json:"quantityType,omitempty"`
+}
+
+var (
+	numberPattern     = regexp.MustCompile(`^\d+([.,]\d+)?$`)
+	fractionPattern   = regexp.MustCompile(`^(\d+)/(\d+)$`)
+	numberUnitPattern = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)(\D.*)$`)
+	timesPattern      = regexp.MustCompile(`^[xX×*](\d+(?:[.,]\d+)?)$`)
+)
+
+// connectors are words between a leading quantity and the name ("2 kg of tomatoes", "2 ק"ג של עגבניות")
+var connectors = map[string]bool{"of": true, "של": true}
+
+// bareUnits are units that imply a quantity of one when written without a number ("kilo tomatoes", "ליטר חלב")
+var bareUnits = map[string]bool{"kg": true, "g": true, "l": true, "ml": true}
+
+// Parse extracts quantity, unit and name from texts such as "2kg tomatoes", "tomatoes x3",
+// "עגבניות 1,5 ק"ג" or "2 ק"ג עגבניות". Quantities may lead or trail the name and may use decimal commas.
+// Plain numbers and "x3" forms are counted in pieces.
+func Parse(text string) (Result, error) {
+	tokens := strings.Fields(text)
+	if len(tokens) == 0 {
+		return Result{}, ErrNoName
+	}
+
+	if value, unit, consumed, ok := leadingQuantity(tokens); ok {
+		tokens = tokens[consumed:]
+		if len(tokens) > 1 && connectors[strings.ToLower(tokens[0])] {
+			tokens = tokens[1:]
+		}
+		return Result{Name: strings.Join(tokens, " "), Quantity: &value, QuantityType: unit}, nil
+	}
+
+	if value, unit, consumed, ok := trailingQuantity(tokens); ok {
+		tokens = tokens[:len(tokens)-consumed]
+		return Result{Name: strings.Join(tokens, " "), Quantity: &value, QuantityType: unit}, nil
+	}
+
+	if isQuantity(tokens) {
+		return Result{}, ErrNoName
+	}
+	return Result{Name: strings.Join(tokens, " ")}, nil
+}
+
+// isQuantity reports whether the tokens hold nothing but a quantity ("3", "2kg", "2 kg")
+func isQuantity(tokens []string) bool {
+	switch len(tokens) {
+	case 1:
+		_, isNumber := parseNumber(tokens[0])
+		_, _, isNumberWithUnit := parseNumberWithUnit(tokens[0])
+		return isNumber || isNumberWithUnit
+	case 2:
+		_, isNumber := parseNumber(tokens[0])
+		_, isUnit := lookupUnit(tokens[1])
+		return isNumber && isUnit
+	}
+	return false
+}
+
+// leadingQuantity parses a quantity at the start of the tokens, leaving at least one token for the name
+func leadingQuantity(tokens []string) (float64, string, int, bool) {
+	if len(tokens) < 2 {
+		return 0, "", 0, false
+	}
+
+	if value, ok := parseNumber(tokens[0]); ok {
+		if unit, ok := lookupUnit(tokens[1]); ok {
+			return value, unit, 2, len(tokens) > 2
+		}
+		return value, "pcs", 1, true
+	}
+
+	if value, unit, ok := parseNumberWithUnit(tokens[0]); ok {
+		return value, unit, 1, true
+	}
+
+	if unit, ok := lookupUnit(tokens[0]); ok && bareUnits[unit] {
+		return 1, unit, 1, true
+	}
+
+	return 0, "", 0, false
+}
+
+// trailingQuantity parses a quantity at the end of the tokens, leaving at least one token for the name
+func trailingQuantity(tokens []string) (float64, string, int, bool) {
+	n := len(tokens)
+	if n < 2 {
+		return 0, "", 0, false
+	}
+	last := tokens[n-1]
+
+	if value, unit, ok := parseNumberWithUnit(last); ok {
+		return value, unit, 1, true
+	}
+
+	if unit, ok := lookupUnit(last); ok && n > 2 {
+		if value, ok := parseNumber(tokens[n-2]); ok {
+			return value, unit, 2, true
+		}
+	}
+
+	if value, ok := parseNumber(last); ok {
+		return value, "pcs", 1, true
+	}
+
+	return 0, "", 0, false
+}
+
+// parseNumberWithUnit parses tokens such as "2kg", "1,5l", "3x" and "x3"
+func parseNumberWithUnit(token string) (float64, string, bool) {
+	if match := timesPattern.FindStringSubmatch(token); match != nil {
+		value, ok := parseNumber(match[1])
+		return value, "pcs", ok
+	}
+
+	match := numberUnitPattern.FindStringSubmatch(token)
+	if match == nil {
+		return 0, "", false
+	}
+	value, ok := parseNumber(match[1])
+	if !ok {
+		return 0, "", false
+	}
+	unit, ok := lookupUnit(match[2])
+	return value, unit, ok
+}
+
+// parseNumber parses positive numbers with a decimal point or comma ("1.5", "1,5") and simple fractions ("1/2")
+// A comma is always a decimal comma, so "1,500" is one and a half; thousands separators are not supported
+func parseNumber(token string) (float64, bool) {
+	if match := fractionPattern.FindStringSubmatch(token); match != nil {
+		numerator, _ := strconv.ParseFloat(match[1], 64)
+		denominator, _ := strconv.ParseFloat(match[2], 64)
+		if numerator == 0 || denominator == 0 {
+			return 0, false
+		}
+		return numerator / denominator, true
+	}
+
+	if !numberPattern.MatchString(token) {
+		return 0, false
+	}
+	token = strings.Replace(token, ",", ".", 1)
+
+	value, err := strconv.ParseFloat(token, 64)
+	if err != nil || value <= 0 {
+		return 0, false
+	}
+	return value, true
+}
+
+// lookupUnit returns the canonical code of a known unit
+func lookupUnit(token string) (string, bool) {
+	unit, ok := units.Lookup(token)
+	if !ok {
+		return "", false
+	}
+	return unit.Code, true
+}
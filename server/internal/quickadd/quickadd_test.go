@@ -0,0 +1,67 @@
+package quickadd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text     string
+		name     string
+		quantity float64 // Zero for no quantity
+		unit     string
+	}{
+		{"milk", "milk", 0, ""},
+		{"  whole   milk ", "whole milk", 0, ""},
+		{"2kg tomatoes", "tomatoes", 2, "kg"},
+		{"2 kg tomatoes", "tomatoes", 2, "kg"},
+		{"2 kg of tomatoes", "tomatoes", 2, "kg"},
+		{"3 eggs", "eggs", 3, "pcs"},
+		{"1/2 kg cheese", "cheese", 0.5, "kg"},
+		{"kilo tomatoes", "tomatoes", 1, "kg"},
+		{"tomatoes x3", "tomatoes", 3, "pcs"},
+		{"tomatoes 3x", "tomatoes", 3, "pcs"},
+		{"eggs 12", "eggs", 12, "pcs"},
+		{"flour 1.5kg", "flour", 1.5, "kg"},
+		{"flour 1.5 kg", "flour", 1.5, "kg"},
+		{"2 ק\"ג עגבניות", "עגבניות", 2, "kg"},
+		{"2 ק\"ג של עגבניות", "עגבניות", 2, "kg"},
+		{"עגבניות 1,5 ק\"ג", "עגבניות", 1.5, "kg"},
+		{"ליטר חלב", "חלב", 1, "l"},
+		// A comma is always a decimal comma
+		{"1,5 l milk", "milk", 1.5, "l"},
+		{"1,500 kg flour", "flour", 1.5, "kg"},
+		{"flour 1,25kg", "flour", 1.25, "kg"},
+		// Zero and malformed numbers are part of the name
+		{"0 eggs", "0 eggs", 0, ""},
+		{"1/0 eggs", "1/0 eggs", 0, ""},
+		{"1.2.3 eggs", "1.2.3 eggs", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			result, err := Parse(tt.text)
+			if err != nil {
+				t.Fatalf("Failed to parse %q: %v", tt.text, err)
+			}
+			if result.Name != tt.name {
+				t.Errorf("Expected name %q, got %q", tt.name, result.Name)
+			}
+			switch {
+			case tt.quantity == 0 && result.Quantity != nil:
+				t.Errorf("Expected no quantity, got %v %s", *result.Quantity, result.QuantityType)
+			case tt.quantity != 0 && (result.Quantity == nil || *result.Quantity != tt.quantity || result.QuantityType != tt.unit):
+				t.Errorf("Expected %v %s, got %+v", tt.quantity, tt.unit, result)
+			}
+		})
+	}
+}
+
+func TestParseWithoutName(t *testing.T) {
+	for _, text := range []string{"", "   ", "3", "2kg", "2 kg", "x3", "1,5 ליטר"} {
+		if _, err := Parse(text); !errors.Is(err, ErrNoName) {
+			t.Errorf("Expected %q to have no name, got %v", text, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InboundTokenRepositoryImpl implements InboundTokenRepository
+type InboundTokenRepositoryImpl struct {
+	collection *mongo.Collection
+}
+
+// NewInboundTokenRepository creates a new inbound token repository
+func NewInboundTokenRepository(db *mongo.Database) InboundTokenRepository {
+	return &InboundTokenRepositoryImpl{
+		collection: db.Collection("inbound_tokens"),
+	}
+}
+
+// Create stores a new token
+func (r *InboundTokenRepositoryImpl) Create(ctx context.Context, token *models.InboundToken) error {
+	token.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, token)
+	if err != nil {
+		log.Printf("[REPO_CREATE_INBOUND_TOKEN] Failed to insert token: uuid=%s, error=%v", token.UUID, err)
+		return err
+	}
+	token.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetByHash retrieves the token with the given hash
+func (r *InboundTokenRepositoryImpl) GetByHash(ctx context.Context, tokenHash string) (*models.InboundToken, error) {
+	var token models.InboundToken
+	err := r.collection.FindOne(ctx, bson.M{"tokenHash": tokenHash}).Decode(&token)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetByUserID retrieves the tokens of a user, oldest first
+func (r *InboundTokenRepositoryImpl) GetByUserID(ctx context.Context, userID string) ([]models.InboundToken, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tokens := []models.InboundToken{}
+	if err = cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// MarkUsed records when a token was last used
+func (r *InboundTokenRepositoryImpl) MarkUsed(ctx context.Context, uuid string, at time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"uuid": uuid}, bson.M{"$set": bson.M{"lastUsedAt": at}})
+	return err
+}
+
+// Delete revokes a token of a user, reporting whether it existed
+func (r *InboundTokenRepositoryImpl) Delete(ctx context.Context, userID string, uuid string) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"uuid": uuid, "userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount == 1, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/yair12/lists-viewer/server/internal/models"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -10,28 +11,78 @@ import (
 // ListRepository defines methods for list operations
 type ListRepository interface {
 	Create(ctx context.Context, list *models.List) error
+	CreateWithItems(ctx context.Context, list *models.List, items []models.Item) error
 	GetByID(ctx context.Context, uuid string, userID string) (*models.List, error)
+	GetTree(ctx context.Context, uuid string, includeChildren bool) (*models.ListTree, error)
 	GetAll(ctx context.Context, userID string) ([]models.List, error)
 	Update(ctx context.Context, list *models.List) error
 	Delete(ctx context.Context, uuid string, userID string, version int32) error
 	UpdateItemCounts(ctx context.Context, listID string) error
+	Search(ctx context.Context, filter SearchFilter) ([]models.List, error)
+	SetResetSchedule(ctx context.Context, uuid string, schedule *models.ResetSchedule) error
+	GetDueResets(ctx context.Context, now time.Time) ([]models.List, error)
+	ClaimReset(ctx context.Context, uuid string, dueAt time.Time, nextRunAt time.Time, ranAt time.Time) (bool, error)
+	ReleaseReset(ctx context.Context, uuid string, dueAt time.Time, nextRunAt time.Time, lastRunAt *time.Time) error
 }
 
 // ItemRepository defines methods for item operations
 type ItemRepository interface {
 	Create(ctx context.Context, item *models.Item) error
+	CreateMany(ctx context.Context, items []models.Item) error
 	GetByID(ctx context.Context, listID string, itemID string) (*models.Item, error)
-	GetByListID(ctx context.Context, listID string, includeArchived bool) ([]models.Item, error)
+	GetByListID(ctx context.Context, listID string, query models.ItemQuery) ([]models.Item, string, error)
+	GetNestedList(ctx context.Context, nestedListID string) (*models.Item, error)
+	GetCompletedSince(ctx context.Context, listID string, since time.Time) ([]models.Item, error)
 	Update(ctx context.Context, item *models.Item) error
 	Delete(ctx context.Context, listID string, itemID string, userID string, version int32) error
 	DeleteByListID(ctx context.Context, listID string) error
 	DeleteCompletedByListID(ctx context.Context, listID string) error
 	BulkDelete(ctx context.Context, listID string, itemIDs []string) error
-	BulkComplete(ctx context.Context, listID string, itemIDs []string, updatedBy string) ([]models.Item, error)
+	Merge(ctx context.Context, keep *models.Item, merged []models.Item) error
 	UpdateOrder(ctx context.Context, listID string, items []models.Item) error
-	Move(ctx context.Context, sourceListID string, targetListID string, itemID string, newOrder int32) (*models.Item, error)
+	Move(ctx context.Context, sourceListID string, targetListID string, itemID string, newOrder int32, version int32, updatedBy string) (*models.Item, error)
 	IncrementVersion(ctx context.Context, listID string, itemID string) error
 	UpdateItemCounts(ctx context.Context, listID string) error
+	Search(ctx context.Context, filter SearchFilter) ([]models.Item, error)
+	ResetCompletion(ctx context.Context, listIDs []string, updatedBy string) (int64, error)
+	GetDueRecurrences(ctx context.Context, now time.Time) ([]models.Item, error)
+	ReopenRecurring(ctx context.Context, item *models.Item, updatedBy string) (bool, error)
+	GetDue(ctx context.Context, filter DueFilter) ([]models.Item, error)
+	GetDueReminders(ctx context.Context, now time.Time) ([]models.Item, error)
+	ScheduleReminder(ctx context.Context, item *models.Item) error
+	ClaimReminder(ctx context.Context, item *models.Item) (bool, error)
+}
+
+// SearchFilter narrows a search over names and descriptions
+type SearchFilter struct {
+	Query   string
+	Regex   bool     // Match a case-insensitive substring instead of using the text index
+	Type    string   // "item" or "list" (items only)
+	ListIDs []string // Parent lists to search in (items only)
+	Limit   int64
+}
+
+// TemplateRepository defines methods for template operations
+type TemplateRepository interface {
+	Create(ctx context.Context, template *models.Template) error
+	GetByID(ctx context.Context, uuid string) (*models.Template, error)
+	GetAll(ctx context.Context, userID string) ([]models.Template, error)
+	Update(ctx context.Context, template *models.Template) error
+	Delete(ctx context.Context, uuid string, version int32) error
+}
+
+// ListResetRepository defines methods for the list reset history
+type ListResetRepository interface {
+	Create(ctx context.Context, reset *models.ListReset) error
+	GetByListID(ctx context.Context, listID string, limit int64) ([]models.ListReset, error)
+}
+
+// DueFilter selects open items by the deadline of their due date
+type DueFilter struct {
+	ListIDs []string  // Lists to search in, all lists when empty
+	After   time.Time // Inclusive lower bound, none when zero
+	Before  time.Time // Exclusive upper bound
+	Limit   int64
 }
 
 // UserRepository defines methods for user operations
@@ -39,20 +90,76 @@ type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
 	GetByUsername(ctx context.Context, username string) (*models.User, error)
 	Update(ctx context.Context, user *models.User) error
+	GetDigestSubscribers(ctx context.Context) ([]models.User, error)
+	ClaimDigest(ctx context.Context, user *models.User, sentAt time.Time) (bool, error)
+	ReleaseDigest(ctx context.Context, user *models.User, sentAt time.Time) error
+}
+
+// PushSubscriptionRepository defines operations for Web Push subscriptions
+type PushSubscriptionRepository interface {
+	Upsert(ctx context.Context, sub *models.PushSubscription) error
+	GetByUserID(ctx context.Context, userID string) ([]models.PushSubscription, error)
+	Delete(ctx context.Context, userID string, subscriptionID string) (bool, error)
+	DeleteByEndpoint(ctx context.Context, endpoint string) error
+}
+
+// WebhookRepository defines methods for webhook subscriptions
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	GetByID(ctx context.Context, uuid string) (*models.Webhook, error)
+	GetByUserID(ctx context.Context, userID string) ([]models.Webhook, error)
+	GetForList(ctx context.Context, listID string, userIDs []string) ([]models.Webhook, error)
+	Update(ctx context.Context, webhook *models.Webhook) error
+	Delete(ctx context.Context, uuid string, version int32) error
+}
+
+// WebhookDeliveryRepository defines methods for the webhook outbox and delivery log
+type WebhookDeliveryRepository interface {
+	CreateMany(ctx context.Context, deliveries []models.WebhookDelivery) error
+	GetByID(ctx context.Context, webhookID string, uuid string) (*models.WebhookDelivery, error)
+	GetByWebhookID(ctx context.Context, webhookID string, limit int64) ([]models.WebhookDelivery, error)
+	GetDue(ctx context.Context, now time.Time, limit int64) ([]models.WebhookDelivery, error)
+	Claim(ctx context.Context, delivery *models.WebhookDelivery, until time.Time) (bool, error)
+	RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery, claimedUntil time.Time) (bool, error)
+	DeleteByWebhookID(ctx context.Context, webhookID string) error
+}
+
+// InboundTokenRepository defines methods for inbound quick-add tokens
+type InboundTokenRepository interface {
+	Create(ctx context.Context, token *models.InboundToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*models.InboundToken, error)
+	GetByUserID(ctx context.Context, userID string) ([]models.InboundToken, error)
+	MarkUsed(ctx context.Context, uuid string, at time.Time) error
+	Delete(ctx context.Context, userID string, uuid string) (bool, error)
 }
 
 // Repositories holds all repository instances
 type Repositories struct {
-	List ListRepository
-	Item ItemRepository
-	User UserRepository
+	List     ListRepository
+	Item     ItemRepository
+	Template TemplateRepository
+	Reset    ListResetRepository
+	User     UserRepository
+	Push     PushSubscriptionRepository
+	Webhook  WebhookRepository
+	Delivery WebhookDeliveryRepository
+	Inbound  InboundTokenRepository
+
+	client *mongo.Client
 }
 
 // NewRepositories creates new repository instances
 func NewRepositories(db *mongo.Database) *Repositories {
 	return &Repositories{
-		List: NewListRepository(db),
-		Item: NewItemRepository(db),
-		User: NewUserRepository(db),
+		List:     NewListRepository(db),
+		Item:     NewItemRepository(db),
+		Template: NewTemplateRepository(db),
+		Reset:    NewListResetRepository(db),
+		User:     NewUserRepository(db),
+		Push:     NewPushSubscriptionRepository(db),
+		Webhook:  NewWebhookRepository(db),
+		Delivery: NewWebhookDeliveryRepository(db),
+		Inbound:  NewInboundTokenRepository(db),
+		client:   db.Client(),
 	}
 }
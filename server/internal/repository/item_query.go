@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidSort is returned for an unknown item sort field
+var ErrInvalidSort = errors.New("invalid sort field")
+
+// ErrInvalidCursor is returned for a malformed cursor or a cursor of a different sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// itemSortFields are the fields items can be sorted by
+var itemSortFields = map[string]bool{
+	"order":       true,
+	"name":        true,
+	"createdAt":   true,
+	"updatedAt":   true,
+	"completedAt": true,
+}
+
+// sortKey is a field of a sort order with its direction (1 ascending, -1 descending)
+type sortKey struct {
+	field     string
+	direction int
+}
+
+// itemCursor is the position after the last item of a page
+type itemCursor struct {
+	Sort   string `bson:"s"` // Signature of the sort order the cursor belongs to
+	Values bson.A `bson:"v"` // Sort key values of the last item
+}
+
+// itemSortKeys returns the sort order of a query; uuid is always the last key so that cursors are stable
+func itemSortKeys(query models.ItemQuery) ([]sortKey, error) {
+	var keys []sortKey
+	switch {
+	case query.SortBy == "":
+		// Open items keep their manual order, completed items are ordered by completion time (newest first)
+		keys = []sortKey{{"completed", 1}, {"completedAt", -1}, {"order", 1}}
+	case itemSortFields[query.SortBy]:
+		direction := 1
+		if query.Descending {
+			direction = -1
+		}
+		keys = []sortKey{{query.SortBy, direction}}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSort, query.SortBy)
+	}
+	return append(keys, sortKey{"uuid", 1}), nil
+}
+
+// sortSignature identifies a sort order inside cursors
+func sortSignature(keys []sortKey) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%s:%d", key.field, key.direction)
+	}
+	return strings.Join(parts, ",")
+}
+
+// sortDocument converts sort keys to a MongoDB sort document
+func sortDocument(keys []sortKey) bson.D {
+	sort := bson.D{}
+	for _, key := range keys {
+		sort = append(sort, bson.E{Key: key.field, Value: key.direction})
+	}
+	return sort
+}
+
+// itemQueryFilter builds the filter of a list item query, without the cursor
+func itemQueryFilter(listID string, query models.ItemQuery) bson.M {
+	filter := bson.M{"listId": listID}
+	if !query.IncludeArchived {
+		filter["archived"] = false
+	}
+	if query.Type != "" {
+		filter["type"] = query.Type
+	}
+	if query.NamePrefix != "" {
+		filter["name"] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(query.NamePrefix), Options: "i"}
+	}
+
+	if query.Completed != nil {
+		switch {
+		case query.CompletedBy != "" && *query.Completed:
+			filter["completedByUsers"] = query.CompletedBy
+		case query.CompletedBy != "":
+			filter["completedByUsers"] = bson.M{"$ne": query.CompletedBy}
+		default:
+			filter["completed"] = *query.Completed
+		}
+	}
+	return filter
+}
+
+// encodeItemCursor returns a cursor pointing after the given item
+func encodeItemCursor(keys []sortKey, item *models.Item) (string, error) {
+	doc, err := bson.Marshal(item)
+	if err != nil {
+		return "", err
+	}
+
+	values := bson.A{}
+	for _, key := range keys {
+		value, err := bson.Raw(doc).LookupErr(key.field)
+		if err != nil {
+			// Fields omitted when empty (e.g. completedAt) sort like null
+			values = append(values, nil)
+			continue
+		}
+		values = append(values, value)
+	}
+
+	encoded, err := bson.Marshal(itemCursor{Sort: sortSignature(keys), Values: values})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+// decodeItemCursor parses a cursor and checks that it belongs to the given sort order
+func decodeItemCursor(keys []sortKey, cursor string) (bson.A, error) {
+	encoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var decoded itemCursor
+	if err := bson.Unmarshal(encoded, &decoded); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if decoded.Sort != sortSignature(keys) || len(decoded.Values) != len(keys) {
+		return nil, ErrInvalidCursor
+	}
+	return decoded.Values, nil
+}
+
+// afterCursor matches the items sorted after the cursor values
+// Each clause keeps the leading keys equal and moves past the cursor on the next key
+func afterCursor(keys []sortKey, values bson.A) bson.A {
+	clauses := bson.A{}
+	for i, key := range keys {
+		after, ok := keyAfter(key, values[i])
+		if !ok {
+			continue
+		}
+
+		clause := bson.M{}
+		for j := 0; j < i; j++ {
+			clause[keys[j].field] = values[j]
+		}
+		for field, condition := range after {
+			clause[field] = condition
+		}
+		clauses = append(clauses, clause)
+	}
+	return clauses
+}
+
+// keyAfter returns the condition for values of a key sorted after the given value
+// Missing values and nulls sort before all others, so nothing follows null in descending order
+func keyAfter(key sortKey, value interface{}) (bson.M, bool) {
+	switch {
+	case value == nil && key.direction == 1:
+		return bson.M{key.field: bson.M{"$ne": nil}}, true
+	case value == nil:
+		return nil, false
+	case key.direction == 1:
+		return bson.M{key.field: bson.M{"$gt": value}}, true
+	default:
+		return bson.M{"$or": bson.A{
+			bson.M{key.field: bson.M{"$lt": value}},
+			bson.M{key.field: nil},
+		}}, true
+	}
+}
@@ -24,10 +24,13 @@ func NewItemRepository(db *mongo.Database) ItemRepository {
 	}
 }
 
-// Create creates a new item
+// Create creates a new item, keeping CreatedAt when it is already set
 func (r *ItemRepositoryImpl) Create(ctx context.Context, item *models.Item) error {
-	item.CreatedAt = time.Now()
-	item.UpdatedAt = time.Now()
+	now := time.Now()
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = now
+	}
+	item.UpdatedAt = now
 	item.Version = 1
 	item.Archived = false
 	if item.Type == "item" {
@@ -45,6 +48,44 @@ func (r *ItemRepositoryImpl) Create(ctx context.Context, item *models.Item) erro
 	return nil
 }
 
+// CreateMany creates several items in one insert
+func (r *ItemRepositoryImpl) CreateMany(ctx context.Context, items []models.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	documents := make([]interface{}, len(items))
+	for i := range items {
+		items[i].CreatedAt = now
+		items[i].UpdatedAt = now
+		items[i].Version = 1
+		documents[i] = items[i]
+	}
+
+	log.Printf("[REPO_CREATE_ITEMS] Creating items: count=%d", len(items))
+	if _, err := r.collection.InsertMany(ctx, documents); err != nil {
+		log.Printf("[REPO_CREATE_ITEMS] Failed to insert items: count=%d, error=%v", len(items), err)
+		// Inside a transaction the insert is rolled back; outside one the items inserted before the failure are removed
+		if mongo.SessionFromContext(ctx) == nil {
+			r.removeItems(ctx, items)
+		}
+		return err
+	}
+	return nil
+}
+
+// removeItems deletes the items inserted by a failed CreateMany
+func (r *ItemRepositoryImpl) removeItems(ctx context.Context, items []models.Item) {
+	uuids := make([]string, len(items))
+	for i := range items {
+		uuids[i] = items[i].UUID
+	}
+	if _, err := r.collection.DeleteMany(context.WithoutCancel(ctx), bson.M{"uuid": bson.M{"$in": uuids}}); err != nil {
+		log.Printf("[REPO_CREATE_ITEMS] Failed to remove items after error: count=%d, error=%v", len(uuids), err)
+	}
+}
+
 // GetByID retrieves an item by ID
 func (r *ItemRepositoryImpl) GetByID(ctx context.Context, listID string, itemID string) (*models.Item, error) {
 	var item models.Item
@@ -64,14 +105,64 @@ func (r *ItemRepositoryImpl) GetByID(ctx context.Context, listID string, itemID
 	return &item, nil
 }
 
-// GetByListID retrieves all items in a list
-func (r *ItemRepositoryImpl) GetByListID(ctx context.Context, listID string, includeArchived bool) ([]models.Item, error) {
-	filter := bson.M{"listId": listID}
-	if !includeArchived {
-		filter["archived"] = false
+// GetByListID retrieves the items in a list matching the query, one page at a time
+// The returned cursor fetches the next page and is empty on the last page
+func (r *ItemRepositoryImpl) GetByListID(ctx context.Context, listID string, query models.ItemQuery) ([]models.Item, string, error) {
+	keys, err := itemSortKeys(query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filter := itemQueryFilter(listID, query)
+	if query.Cursor != "" {
+		values, err := decodeItemCursor(keys, query.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		filter["$or"] = afterCursor(keys, values)
+	}
+
+	opts := options.Find().SetSort(sortDocument(keys))
+	if query.Limit > 0 {
+		// One extra item tells whether there is a next page
+		opts.SetLimit(query.Limit + 1)
+	}
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	defer cursor.Close(ctx)
+
+	var items []models.Item
+	if err = cursor.All(ctx, &items); err != nil {
+		return nil, "", err
+	}
+
+	if items == nil {
+		items = []models.Item{}
+	}
+	if query.Limit <= 0 || int64(len(items)) <= query.Limit {
+		return items, "", nil
+	}
+
+	items = items[:query.Limit]
+	next, err := encodeItemCursor(keys, &items[len(items)-1])
+	if err != nil {
+		return nil, "", err
+	}
+	return items, next, nil
+}
+
+// GetCompletedSince retrieves items in a list completed at or after the given time, newest first
+func (r *ItemRepositoryImpl) GetCompletedSince(ctx context.Context, listID string, since time.Time) ([]models.Item, error) {
+	filter := bson.M{
+		"listId":      listID,
+		"type":        "item",
+		"completed":   true,
+		"completedAt": bson.M{"$gte": since},
 	}
 
-	opts := options.Find().SetSort(bson.D{{"order", 1}})
+	opts := options.Find().SetSort(bson.D{{Key: "completedAt", Value: -1}})
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
@@ -89,6 +180,24 @@ func (r *ItemRepositoryImpl) GetByListID(ctx context.Context, listID string, inc
 	return items, nil
 }
 
+// GetNestedList retrieves a nested list item by ID regardless of its parent list
+func (r *ItemRepositoryImpl) GetNestedList(ctx context.Context, nestedListID string) (*models.Item, error) {
+	var item models.Item
+	err := r.collection.FindOne(ctx, bson.M{
+		"uuid": nestedListID,
+		"type": "list",
+	}).Decode(&item)
+
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		log.Printf("[REPO_GET_NESTED_LIST] Database error: uuid=%s, error=%v", nestedListID, err)
+		return nil, err
+	}
+	return &item, nil
+}
+
 // Update updates an existing item (with optimistic locking)
 func (r *ItemRepositoryImpl) Update(ctx context.Context, item *models.Item) error {
 	item.UpdatedAt = time.Now()
@@ -103,17 +212,26 @@ func (r *ItemRepositoryImpl) Update(ctx context.Context, item *models.Item) erro
 		},
 		bson.M{
 			"$set": bson.M{
-				"name":               item.Name,
-				"completed":          item.Completed,
-				"quantity":           item.Quantity,
-				"quantityType":       item.QuantityType,
-				"order":              item.Order,
-				"userIconId":         item.UserIconID,
-				"updatedAt":          item.UpdatedAt,
-				"updatedBy":          item.UpdatedBy,
-				"description":        item.Description,
-				"itemCount":          item.ItemCount,
-				"completedItemCount": item.CompletedItemCount,
+				"type":              item.Type,
+				"name":              item.Name,
+				"completed":         item.Completed,
+				"completedAt":       item.CompletedAt,
+				"completedBy":       item.CompletedBy,
+				"completedByUsers":  item.CompletedByUsers,
+				"fulfilledQuantity": item.FulfilledQuantity,
+				"quantity":          item.Quantity,
+				"quantityType":      item.QuantityType,
+				"order":             item.Order,
+				"userIconId":        item.UserIconID,
+				"updatedAt":         item.UpdatedAt,
+				"updatedBy":         item.UpdatedBy,
+				"description":       item.Description,
+				"recurrence":        item.Recurrence,
+				"dueAt":             item.DueAt,
+				"allDay":            item.AllDay,
+				"reminderOffset":    item.ReminderOffset,
+				"remindAt":          item.RemindAt,
+				"assignedTo":        item.AssignedTo,
 			},
 			"$inc": bson.M{"version": 1},
 		},
@@ -207,56 +325,102 @@ func (r *ItemRepositoryImpl) BulkDelete(ctx context.Context, listID string, item
 	return err
 }
 
-// BulkComplete completes multiple items
-func (r *ItemRepositoryImpl) BulkComplete(ctx context.Context, listID string, itemIDs []string, updatedBy string) ([]models.Item, error) {
-	now := time.Now()
-
-	result, err := r.collection.UpdateMany(
-		ctx,
-		bson.M{
-			"listId": listID,
-			"type":   "item",
-			"uuid": bson.M{
-				"$in": itemIDs,
-			},
-		},
-		bson.M{
-			"$set": bson.M{
-				"completed": true,
-				"updatedAt": now,
-				"updatedBy": updatedBy,
-			},
-			"$inc": bson.M{
-				"version": 1,
-			},
-		},
-	)
-
+// Merge updates the kept item and deletes the merged items (with optimistic locking on every item)
+// It runs in a transaction where the deployment supports one; on standalone MongoDB servers a failed merge is undone,
+// so either every merged item is folded into the kept item or the merge fails
+func (r *ItemRepositoryImpl) Merge(ctx context.Context, keep *models.Item, merged []models.Item) error {
+	version := keep.Version
+	err := withTransaction(ctx, r.collection.Database().Client(), func(txCtx context.Context) error {
+		// The transaction may be retried after the kept item's version was already bumped
+		keep.Version = version
+		return r.mergeItems(txCtx, keep, merged)
+	})
+	if errors.Is(err, ErrTransactionsUnsupported) {
+		log.Printf("[REPO_MERGE_ITEMS] Transactions are not supported, merging without one: uuid=%s", keep.UUID)
+		keep.Version = version
+		err = r.mergeWithoutTransaction(ctx, keep, merged)
+	}
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	_ = result // silence unused variable
+	log.Printf("[REPO_MERGE_ITEMS] Merged %d items into uuid=%s", len(merged), keep.UUID)
+	return nil
+}
 
-	// Retrieve updated items
-	cursor, err := r.collection.Find(ctx, bson.M{
-		"listId": listID,
-		"uuid": bson.M{
-			"$in": itemIDs,
-		},
-	})
+// mergeItems updates the kept item and deletes the merged items inside a transaction
+// A merged item that changed meanwhile aborts the merge with a version conflict
+func (r *ItemRepositoryImpl) mergeItems(ctx context.Context, keep *models.Item, merged []models.Item) error {
+	if err := r.Update(ctx, keep); err != nil {
+		return err
+	}
+	if len(merged) == 0 {
+		return nil
+	}
 
+	filters := make([]bson.M, len(merged))
+	for i, item := range merged {
+		filters[i] = bson.M{"uuid": item.UUID, "listId": item.ListID, "version": item.Version}
+	}
+	result, err := r.collection.DeleteMany(ctx, bson.M{"$or": filters})
 	if err != nil {
-		return nil, err
+		log.Printf("[REPO_MERGE_ITEMS] Failed to delete merged items: uuid=%s, error=%v", keep.UUID, err)
+		return err
 	}
-	defer cursor.Close(ctx)
+	if result.DeletedCount < int64(len(merged)) {
+		log.Printf("[REPO_MERGE_ITEMS] Version conflict: uuid=%s, deleted=%d, expected=%d", keep.UUID, result.DeletedCount, len(merged))
+		return errors.New("version_conflict")
+	}
+	return nil
+}
 
-	var items []models.Item
-	if err = cursor.All(ctx, &items); err != nil {
-		return nil, err
+// mergeWithoutTransaction updates the kept item first and then deletes the merged items one by one
+// If a merged item changed meanwhile or a delete fails, the deleted items are restored and the kept item reverted,
+// so the merge either happens completely or fails with a version conflict
+func (r *ItemRepositoryImpl) mergeWithoutTransaction(ctx context.Context, keep *models.Item, merged []models.Item) error {
+	var original models.Item
+	err := r.collection.FindOne(ctx, bson.M{"uuid": keep.UUID, "listId": keep.ListID, "version": keep.Version}).Decode(&original)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		log.Printf("[REPO_MERGE_ITEMS] Version conflict: uuid=%s, version=%d", keep.UUID, keep.Version)
+		return errors.New("version_conflict")
+	}
+	if err != nil {
+		return err
+	}
+	if err := r.Update(ctx, keep); err != nil {
+		return err
 	}
 
-	return items, nil
+	deleted := []interface{}{}
+	var deleteErr error
+	for _, item := range merged {
+		result, err := r.collection.DeleteOne(ctx, bson.M{"uuid": item.UUID, "listId": item.ListID, "version": item.Version})
+		if err != nil || result.DeletedCount == 0 {
+			deleteErr = err
+			break
+		}
+		deleted = append(deleted, item)
+	}
+	if len(deleted) == len(merged) {
+		return nil
+	}
+
+	// Undo the partial merge; the context may already be cancelled
+	log.Printf("[REPO_MERGE_ITEMS] Merged item changed or failed to delete, reverting: uuid=%s, deleted=%d, expected=%d, error=%v", keep.UUID, len(deleted), len(merged), deleteErr)
+	undoCtx := context.WithoutCancel(ctx)
+	if len(deleted) > 0 {
+		if _, err := r.collection.InsertMany(undoCtx, deleted); err != nil {
+			log.Printf("[REPO_MERGE_ITEMS] Failed to restore merged items: uuid=%s, error=%v", keep.UUID, err)
+		}
+	}
+	original.Version = keep.Version
+	if err := r.Update(undoCtx, &original); err != nil {
+		log.Printf("[REPO_MERGE_ITEMS] Failed to revert kept item: uuid=%s, error=%v", keep.UUID, err)
+	}
+	if deleteErr != nil {
+		return deleteErr
+	}
+	return errors.New("version_conflict")
 }
 
 // UpdateOrder updates the order of items
@@ -281,20 +445,23 @@ func (r *ItemRepositoryImpl) UpdateOrder(ctx context.Context, listID string, ite
 	return nil
 }
 
-// Move moves an item to a different list
-func (r *ItemRepositoryImpl) Move(ctx context.Context, sourceListID string, targetListID string, itemID string, newOrder int32) (*models.Item, error) {
+// Move moves an item to a different list (with optimistic locking)
+func (r *ItemRepositoryImpl) Move(ctx context.Context, sourceListID string, targetListID string, itemID string, newOrder int32, version int32, updatedBy string) (*models.Item, error) {
 	result, err := r.collection.UpdateOne(
 		ctx,
 		bson.M{
-			"uuid":   itemID,
-			"listId": sourceListID,
+			"uuid":    itemID,
+			"listId":  sourceListID,
+			"version": version,
 		},
 		bson.M{
 			"$set": bson.M{
 				"listId":    targetListID,
 				"order":     newOrder,
 				"updatedAt": time.Now(),
+				"updatedBy": updatedBy,
 			},
+			"$inc": bson.M{"version": 1},
 		},
 	)
 
@@ -302,7 +469,15 @@ func (r *ItemRepositoryImpl) Move(ctx context.Context, sourceListID string, targ
 		return nil, err
 	}
 
-	if result.ModifiedCount == 0 {
+	if result.MatchedCount == 0 {
+		existingItem, err := r.GetByID(ctx, sourceListID, itemID)
+		if err != nil {
+			return nil, err
+		}
+		if existingItem != nil {
+			log.Printf("[REPO_MOVE_ITEM] Version conflict: uuid=%s, requested_version=%d, current_version=%d", itemID, version, existingItem.Version)
+			return nil, errors.New("version_conflict")
+		}
 		return nil, errors.New("item not found")
 	}
 
@@ -328,41 +503,275 @@ func (r *ItemRepositoryImpl) IncrementVersion(ctx context.Context, listID string
 
 // UpdateItemCounts updates denormalized item counts
 func (r *ItemRepositoryImpl) UpdateItemCounts(ctx context.Context, listID string) error {
+	counts, err := countListItems(ctx, r.collection, listID)
+	if err != nil {
+		return err
+	}
+
+	// Update parent list (if this is a nested list)
+	_, err = r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"uuid": listID,
+			"type": "list",
+		},
+		bson.M{
+			"$set": counts.toSet(),
+		},
+	)
+
+	return err
+}
+
+// itemCounts holds the denormalized item counts of a list
+type itemCounts struct {
+	total     int64
+	completed int64
+	partial   int64
+}
+
+// toSet returns the update document for the denormalized count fields
+func (c itemCounts) toSet() bson.M {
+	return bson.M{
+		"itemCount":          int32(c.total),
+		"completedItemCount": int32(c.completed),
+		"partialItemCount":   int32(c.partial),
+	}
+}
+
+// countListItems counts the regular, completed and partially fulfilled items of a list
+func countListItems(ctx context.Context, items *mongo.Collection, listID string) (itemCounts, error) {
+	var counts itemCounts
+	var err error
+
 	// Count regular items
-	regularCount, err := r.collection.CountDocuments(ctx, bson.M{
+	counts.total, err = items.CountDocuments(ctx, bson.M{
 		"listId": listID,
 		"type":   "item",
 	})
-
 	if err != nil {
-		return err
+		return counts, err
 	}
 
 	// Count completed items
-	completedCount, err := r.collection.CountDocuments(ctx, bson.M{
+	counts.completed, err = items.CountDocuments(ctx, bson.M{
 		"listId":    listID,
 		"type":      "item",
 		"completed": true,
 	})
+	if err != nil {
+		return counts, err
+	}
+
+	// Count open items that are partially fulfilled
+	counts.partial, err = items.CountDocuments(ctx, bson.M{
+		"listId":            listID,
+		"type":              "item",
+		"completed":         false,
+		"fulfilledQuantity": bson.M{"$gt": 0},
+	})
+	return counts, err
+}
 
+// Search finds items and nested lists whose name or description matches the filter
+func (r *ItemRepositoryImpl) Search(ctx context.Context, filter SearchFilter) ([]models.Item, error) {
+	query := searchQuery(filter)
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if len(filter.ListIDs) > 0 {
+		query["listId"] = bson.M{"$in": filter.ListIDs}
+	}
+
+	cursor, err := r.collection.Find(ctx, query, searchOptions(filter))
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer cursor.Close(ctx)
 
-	// Update parent list (if this is a nested list)
-	_, err = r.collection.UpdateOne(
+	items := []models.Item{}
+	if err = cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ResetCompletion uncompletes every completed or partly fulfilled item of the given lists, returning how many changed
+func (r *ItemRepositoryImpl) ResetCompletion(ctx context.Context, listIDs []string, updatedBy string) (int64, error) {
+	result, err := r.collection.UpdateMany(
 		ctx,
 		bson.M{
-			"uuid": listID,
-			"type": "list",
+			"listId": bson.M{"$in": listIDs},
+			"type":   "item",
+			"$or": bson.A{
+				bson.M{"completed": true},
+				bson.M{"completedByUsers.0": bson.M{"$exists": true}},
+				bson.M{"fulfilledQuantity": bson.M{"$ne": nil}},
+			},
 		},
 		bson.M{
 			"$set": bson.M{
-				"itemCount":          int32(regularCount),
-				"completedItemCount": int32(completedCount),
+				"completed": false,
+				"updatedAt": time.Now(),
+				"updatedBy": updatedBy,
+			},
+			"$unset": bson.M{
+				"completedAt":       "",
+				"completedBy":       "",
+				"completedByUsers":  "",
+				"fulfilledQuantity": "",
+				"recurrence.nextAt": "",
 			},
+			"$inc": bson.M{"version": 1},
 		},
 	)
+	if err != nil {
+		log.Printf("[REPO_RESET_COMPLETION] Database error: lists=%v, error=%v", listIDs, err)
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// GetDueRecurrences retrieves the completed recurring items whose next occurrence has come
+func (r *ItemRepositoryImpl) GetDueRecurrences(ctx context.Context, now time.Time) ([]models.Item, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"recurrence.nextAt": bson.M{"$lte": now}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
 
+	items := []models.Item{}
+	if err = cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ReopenRecurring uncompletes a recurring item for its next occurrence, reporting whether this call reopened it
+// The item's due date and reminder are set to those of the new occurrence
+// The update only matches while the occurrence is still pending, so repeated or concurrent runs reopen an item once
+func (r *ItemRepositoryImpl) ReopenRecurring(ctx context.Context, item *models.Item, updatedBy string) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"uuid":              item.UUID,
+			"listId":            item.ListID,
+			"recurrence.nextAt": item.Recurrence.NextAt,
+		},
+		bson.M{
+			"$set": bson.M{
+				"completed": false,
+				"dueAt":     item.DueAt,
+				"remindAt":  item.RemindAt,
+				"updatedAt": time.Now(),
+				"updatedBy": updatedBy,
+			},
+			"$unset": bson.M{
+				"completedAt":       "",
+				"completedBy":       "",
+				"completedByUsers":  "",
+				"fulfilledQuantity": "",
+				"recurrence.nextAt": "",
+			},
+			"$inc": bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		log.Printf("[REPO_REOPEN_RECURRING] Database error: uuid=%s, error=%v", item.UUID, err)
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// GetDue retrieves the open items whose deadline falls in the filter's window
+// The deadline of an all-day item is the end of its due day
+func (r *ItemRepositoryImpl) GetDue(ctx context.Context, filter DueFilter) ([]models.Item, error) {
+	// Items without the allDay field predate due dates and count as timed
+	deadline := func(allDay bool) bson.M {
+		shift := time.Duration(0)
+		selector := bson.M{"$ne": true}
+		if allDay {
+			shift = 24 * time.Hour
+			selector = bson.M{"$eq": true}
+		}
+		dueAt := bson.M{"$lt": filter.Before.Add(-shift)}
+		if !filter.After.IsZero() {
+			dueAt["$gte"] = filter.After.Add(-shift)
+		}
+		return bson.M{"allDay": selector, "dueAt": dueAt}
+	}
+
+	query := bson.M{
+		"type":      "item",
+		"completed": false,
+		"archived":  false,
+		"$or":       bson.A{deadline(false), deadline(true)},
+	}
+	if len(filter.ListIDs) > 0 {
+		query["listId"] = bson.M{"$in": filter.ListIDs}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "dueAt", Value: 1}, {Key: "uuid", Value: 1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	items := []models.Item{}
+	if err = cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetDueReminders retrieves the open items whose reminder is due
+func (r *ItemRepositoryImpl) GetDueReminders(ctx context.Context, now time.Time) ([]models.Item, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"remindAt":  bson.M{"$lte": now},
+		"completed": false,
+		"archived":  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	items := []models.Item{}
+	if err = cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ScheduleReminder sets when the reminder of an item is sent, unless the item changed since it was loaded
+func (r *ItemRepositoryImpl) ScheduleReminder(ctx context.Context, item *models.Item) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"uuid": item.UUID, "listId": item.ListID, "version": item.Version},
+		bson.M{"$set": bson.M{"remindAt": item.RemindAt}},
+	)
+	if err != nil {
+		log.Printf("[REPO_SCHEDULE_REMINDER] Database error: uuid=%s, error=%v", item.UUID, err)
+	}
 	return err
 }
+
+// ClaimReminder clears the pending reminder of an item, reporting whether this call claimed it
+// Only one caller can claim a reminder, so it is sent once even with concurrent schedulers
+// The reminder is delivery bookkeeping, so the item version is left unchanged
+func (r *ItemRepositoryImpl) ClaimReminder(ctx context.Context, item *models.Item) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"uuid": item.UUID, "listId": item.ListID, "remindAt": item.RemindAt},
+		bson.M{"$unset": bson.M{"remindAt": ""}},
+	)
+	if err != nil {
+		log.Printf("[REPO_CLAIM_REMINDER] Database error: uuid=%s, error=%v", item.UUID, err)
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
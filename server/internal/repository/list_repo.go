@@ -15,19 +15,24 @@ import (
 // ListRepositoryImpl implements ListRepository
 type ListRepositoryImpl struct {
 	collection *mongo.Collection
+	items      *mongo.Collection
 }
 
 // NewListRepository creates a new list repository
 func NewListRepository(db *mongo.Database) ListRepository {
 	return &ListRepositoryImpl{
 		collection: db.Collection("lists"),
+		items:      db.Collection("items"),
 	}
 }
 
-// Create creates a new list
+// Create creates a new list, keeping CreatedAt when it is already set
 func (r *ListRepositoryImpl) Create(ctx context.Context, list *models.List) error {
-	list.CreatedAt = time.Now()
-	list.UpdatedAt = time.Now()
+	now := time.Now()
+	if list.CreatedAt.IsZero() {
+		list.CreatedAt = now
+	}
+	list.UpdatedAt = now
 	list.Version = 1
 	list.ItemCount = 0
 	list.CompletedItemCount = 0
@@ -44,6 +49,60 @@ func (r *ListRepositoryImpl) Create(ctx context.Context, list *models.List) erro
 	return nil
 }
 
+// CreateWithItems creates a list together with its items
+// It runs in a transaction where the deployment supports one; otherwise items are inserted first and the list last,
+// so the list never appears partially filled, and on failure the items are removed again
+func (r *ListRepositoryImpl) CreateWithItems(ctx context.Context, list *models.List, items []models.Item) error {
+	err := withTransaction(ctx, r.collection.Database().Client(), func(txCtx context.Context) error {
+		return r.createWithItems(txCtx, list, items)
+	})
+	if errors.Is(err, ErrTransactionsUnsupported) {
+		log.Printf("[REPO_CREATE_LIST_WITH_ITEMS] Transactions are not supported, creating without one: listUUID=%s", list.UUID)
+		err = r.createWithItems(ctx, list, items)
+	}
+	return err
+}
+
+// createWithItems inserts the items and then the list, removing the items again on failure outside a transaction
+func (r *ListRepositoryImpl) createWithItems(ctx context.Context, list *models.List, items []models.Item) error {
+	uuids := make([]string, len(items))
+	if len(items) > 0 {
+		now := time.Now()
+		documents := make([]interface{}, len(items))
+		for i := range items {
+			items[i].CreatedAt = now
+			items[i].UpdatedAt = now
+			items[i].Version = 1
+			documents[i] = items[i]
+			uuids[i] = items[i].UUID
+		}
+
+		log.Printf("[REPO_CREATE_LIST_WITH_ITEMS] Inserting items: listUUID=%s, count=%d", list.UUID, len(items))
+		if _, err := r.items.InsertMany(ctx, documents); err != nil {
+			log.Printf("[REPO_CREATE_LIST_WITH_ITEMS] Failed to insert items: listUUID=%s, error=%v", list.UUID, err)
+			r.removeItems(ctx, uuids)
+			return err
+		}
+	}
+
+	if err := r.Create(ctx, list); err != nil {
+		r.removeItems(ctx, uuids)
+		return err
+	}
+	return nil
+}
+
+// removeItems deletes items inserted by a failed CreateWithItems
+// Inside a transaction there is nothing to remove, as the failed transaction is rolled back
+func (r *ListRepositoryImpl) removeItems(ctx context.Context, uuids []string) {
+	if len(uuids) == 0 || mongo.SessionFromContext(ctx) != nil {
+		return
+	}
+	if _, err := r.items.DeleteMany(context.WithoutCancel(ctx), bson.M{"uuid": bson.M{"$in": uuids}}); err != nil {
+		log.Printf("[REPO_CREATE_LIST_WITH_ITEMS] Failed to remove items after error: count=%d, error=%v", len(uuids), err)
+	}
+}
+
 // GetByID retrieves a list by ID
 func (r *ListRepositoryImpl) GetByID(ctx context.Context, uuid string, userID string) (*models.List, error) {
 	var list models.List
@@ -59,6 +118,58 @@ func (r *ListRepositoryImpl) GetByID(ctx context.Context, uuid string, userID st
 	return &list, nil
 }
 
+// GetTree retrieves a list with its items and, optionally, the items of its nested lists in a single aggregation
+// Items are ordered like GetByListID's default order; archived items are left out
+func (r *ListRepositoryImpl) GetTree(ctx context.Context, uuid string, includeChildren bool) (*models.ListTree, error) {
+	keys, _ := itemSortKeys(models.ItemQuery{})
+	itemPipeline := bson.A{
+		bson.M{"$match": bson.M{"archived": false, "$expr": bson.M{"$eq": bson.A{"$listId", "$$listId"}}}},
+		bson.M{"$sort": sortDocument(keys)},
+	}
+	if includeChildren {
+		// Only nested lists can have children; the depth limit means children are never lists themselves
+		itemPipeline = append(itemPipeline, bson.M{"$lookup": bson.M{
+			"from": r.items.Name(),
+			"let":  bson.M{"parentId": "$uuid", "parentType": "$type"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"archived": false, "$expr": bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{"$$parentType", "list"}},
+					bson.M{"$eq": bson.A{"$listId", "$$parentId"}},
+				}}}},
+				bson.M{"$sort": sortDocument(keys)},
+			},
+			"as": "children",
+		}})
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"uuid": uuid}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":     r.items.Name(),
+			"let":      bson.M{"listId": "$uuid"},
+			"pipeline": itemPipeline,
+			"as":       "items",
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		log.Printf("[REPO_GET_LIST_TREE] Aggregation failed: uuid=%s, error=%v", uuid, err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var trees []models.ListTree
+	if err := cursor.All(ctx, &trees); err != nil {
+		return nil, err
+	}
+	if len(trees) == 0 {
+		log.Printf("[REPO_GET_LIST_TREE] List not found: uuid=%s", uuid)
+		return nil, nil
+	}
+	return &trees[0], nil
+}
+
 // GetAll retrieves all lists for a user
 func (r *ListRepositoryImpl) GetAll(ctx context.Context, userID string) ([]models.List, error) {
 	cursor, err := r.collection.Find(ctx, bson.M{
@@ -94,12 +205,15 @@ func (r *ListRepositoryImpl) Update(ctx context.Context, list *models.List) erro
 		},
 		bson.M{
 			"$set": bson.M{
-				"name":        list.Name,
-				"description": list.Description,
-				"color":       list.Color,
-				"updatedAt":   list.UpdatedAt,
-				"updatedBy":   list.UpdatedBy,
-				"version":     list.Version + 1,
+				"name":           list.Name,
+				"description":    list.Description,
+				"color":          list.Color,
+				"completionMode": list.CompletionMode,
+				"members":        list.Members,
+				"aliases":        list.Aliases,
+				"updatedAt":      list.UpdatedAt,
+				"updatedBy":      list.UpdatedBy,
+				"version":        list.Version + 1,
 			},
 		},
 	)
@@ -158,7 +272,103 @@ func (r *ListRepositoryImpl) Delete(ctx context.Context, uuid string, userID str
 
 // UpdateItemCounts updates the denormalized item counts for a list
 func (r *ListRepositoryImpl) UpdateItemCounts(ctx context.Context, listID string) error {
-	// This will be implemented when we have item repository ready
-	// It will count items in the items collection
+	counts, err := countListItems(ctx, r.items, listID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(
+		ctx,
+		bson.M{"uuid": listID},
+		bson.M{"$set": counts.toSet()},
+	)
+	return err
+}
+
+// Search finds lists whose name or description matches the filter
+func (r *ListRepositoryImpl) Search(ctx context.Context, filter SearchFilter) ([]models.List, error) {
+	cursor, err := r.collection.Find(ctx, searchQuery(filter), searchOptions(filter))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	lists := []models.List{}
+	if err = cursor.All(ctx, &lists); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// SetResetSchedule sets the reset schedule of a list, or removes it when schedule is nil
+// The schedule is not part of the list's versioned content, so the version is left unchanged
+func (r *ListRepositoryImpl) SetResetSchedule(ctx context.Context, uuid string, schedule *models.ResetSchedule) error {
+	update := bson.M{"$unset": bson.M{"resetSchedule": ""}}
+	if schedule != nil {
+		update = bson.M{"$set": bson.M{"resetSchedule": schedule}}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"uuid": uuid}, update)
+	if err != nil {
+		log.Printf("[REPO_SET_RESET_SCHEDULE] Database error: uuid=%s, error=%v", uuid, err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("list not found")
+	}
+	return nil
+}
+
+// GetDueResets retrieves the lists whose scheduled reset is due
+func (r *ListRepositoryImpl) GetDueResets(ctx context.Context, now time.Time) ([]models.List, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"archived":                false,
+		"resetSchedule.nextRunAt": bson.M{"$lte": now},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	lists := []models.List{}
+	if err = cursor.All(ctx, &lists); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// ClaimReset moves a due reset to its next run time, reporting whether this call claimed it
+// Only one caller can claim a run, so concurrent schedulers never reset a list twice for the same run
+func (r *ListRepositoryImpl) ClaimReset(ctx context.Context, uuid string, dueAt time.Time, nextRunAt time.Time, ranAt time.Time) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"uuid": uuid, "resetSchedule.nextRunAt": dueAt},
+		bson.M{"$set": bson.M{
+			"resetSchedule.nextRunAt": nextRunAt,
+			"resetSchedule.lastRunAt": ranAt,
+		}},
+	)
+	if err != nil {
+		log.Printf("[REPO_CLAIM_RESET] Database error: uuid=%s, error=%v", uuid, err)
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// ReleaseReset gives back a run claimed by ClaimReset, so the next scheduler pass performs it again
+// Nothing changes once the schedule moved on, e.g. because it was replaced
+func (r *ListRepositoryImpl) ReleaseReset(ctx context.Context, uuid string, dueAt time.Time, nextRunAt time.Time, lastRunAt *time.Time) error {
+	update := bson.M{"$set": bson.M{"resetSchedule.nextRunAt": dueAt}}
+	if lastRunAt != nil {
+		update["$set"].(bson.M)["resetSchedule.lastRunAt"] = *lastRunAt
+	} else {
+		update["$unset"] = bson.M{"resetSchedule.lastRunAt": ""}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"uuid": uuid, "resetSchedule.nextRunAt": nextRunAt}, update)
+	if err != nil {
+		log.Printf("[REPO_RELEASE_RESET] Database error: uuid=%s, error=%v", uuid, err)
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ListResetRepositoryImpl implements ListResetRepository
+type ListResetRepositoryImpl struct {
+	collection *mongo.Collection
+}
+
+// NewListResetRepository creates a new list reset repository
+func NewListResetRepository(db *mongo.Database) ListResetRepository {
+	return &ListResetRepositoryImpl{
+		collection: db.Collection("list_resets"),
+	}
+}
+
+// Create records a list reset
+func (r *ListResetRepositoryImpl) Create(ctx context.Context, reset *models.ListReset) error {
+	result, err := r.collection.InsertOne(ctx, reset)
+	if err != nil {
+		log.Printf("[REPO_CREATE_LIST_RESET] Failed to insert reset: listID=%s, error=%v", reset.ListID, err)
+		return err
+	}
+
+	reset.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetByListID retrieves the most recent resets of a list, newest first
+func (r *ListResetRepositoryImpl) GetByListID(ctx context.Context, listID string, limit int64) ([]models.ListReset, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, bson.M{"listId": listID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	resets := []models.ListReset{}
+	if err = cursor.All(ctx, &resets); err != nil {
+		return nil, err
+	}
+	return resets, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PushSubscriptionRepositoryImpl implements PushSubscriptionRepository
+type PushSubscriptionRepositoryImpl struct {
+	collection *mongo.Collection
+}
+
+// NewPushSubscriptionRepository creates a new push subscription repository
+func NewPushSubscriptionRepository(db *mongo.Database) PushSubscriptionRepository {
+	return &PushSubscriptionRepositoryImpl{
+		collection: db.Collection("push_subscriptions"),
+	}
+}
+
+// Upsert stores a subscription by endpoint, updating the keys and owner of an endpoint registered before
+// A browser keeps its endpoint across sign-ins, so the endpoint moves to the user who registered it last
+func (r *PushSubscriptionRepositoryImpl) Upsert(ctx context.Context, sub *models.PushSubscription) error {
+	now := time.Now()
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	filter := bson.M{"endpoint": sub.Endpoint}
+	update := bson.M{
+		"$set": bson.M{
+			"userId":     sub.UserID,
+			"p256dh":     sub.P256dh,
+			"auth":       sub.Auth,
+			"deviceName": sub.DeviceName,
+			"updatedAt":  now,
+		},
+		"$setOnInsert": bson.M{
+			"uuid":      uuid.New().String(),
+			"createdAt": now,
+		},
+	}
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(sub)
+	// Of two concurrent first registrations of an endpoint one insert fails on the unique index; it updates the other instead
+	if mongo.IsDuplicateKeyError(err) {
+		err = r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(sub)
+	}
+	if err != nil {
+		log.Printf("[REPO_UPSERT_PUSH_SUBSCRIPTION] Database error: userID=%s, error=%v", sub.UserID, err)
+		return err
+	}
+	return nil
+}
+
+// GetByUserID retrieves the subscriptions of a user, oldest first
+func (r *PushSubscriptionRepositoryImpl) GetByUserID(ctx context.Context, userID string) ([]models.PushSubscription, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	subs := []models.PushSubscription{}
+	if err = cursor.All(ctx, &subs); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// Delete removes a subscription of a user, reporting whether it existed
+func (r *PushSubscriptionRepositoryImpl) Delete(ctx context.Context, userID string, subscriptionID string) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"uuid": subscriptionID, "userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount == 1, nil
+}
+
+// DeleteByEndpoint removes the subscription of an endpoint the push service no longer accepts
+func (r *PushSubscriptionRepositoryImpl) DeleteByEndpoint(ctx context.Context, endpoint string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"endpoint": endpoint})
+	return err
+}
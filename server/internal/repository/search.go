@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureIndexes creates the indexes the repositories rely on; existing indexes are left untouched
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	for _, collection := range []string{"lists", "items"} {
+		name, err := db.Collection(collection).Indexes().CreateOne(ctx, textIndex())
+		if err != nil {
+			log.Printf("[REPO_INDEXES] Failed to create text index: collection=%s, error=%v", collection, err)
+			return err
+		}
+		log.Printf("[REPO_INDEXES] Ensured index: collection=%s, name=%s", collection, name)
+	}
+
+	// Push subscriptions are upserted by endpoint, so concurrent registrations of a browser must not insert it twice
+	name, err := db.Collection("push_subscriptions").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "endpoint", Value: 1}},
+		Options: options.Index().SetName("endpoint_unique").SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("[REPO_INDEXES] Failed to create endpoint index: collection=push_subscriptions, error=%v", err)
+		return err
+	}
+	log.Printf("[REPO_INDEXES] Ensured index: collection=push_subscriptions, name=%s", name)
+	return nil
+}
+
+// textIndex is the full-text index over names and descriptions used by Search
+// Names weigh more than descriptions; scripts without stemming support (e.g. Hebrew) are tokenized on whitespace
+func textIndex() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{{Key: "name", Value: "text"}, {Key: "description", Value: "text"}},
+		Options: options.Index().
+			SetName("name_description_text").
+			SetWeights(bson.D{{Key: "name", Value: 3}, {Key: "description", Value: 1}}).
+			SetDefaultLanguage("english"),
+	}
+}
+
+// searchQuery builds the filter shared by list and item searches
+func searchQuery(filter SearchFilter) bson.M {
+	query := bson.M{"archived": false}
+	if filter.Regex {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(filter.Query), Options: "i"}
+		query["$or"] = bson.A{bson.M{"name": pattern}, bson.M{"description": pattern}}
+	} else {
+		query["$text"] = bson.M{"$search": filter.Query}
+	}
+	return query
+}
+
+// searchOptions orders text matches by relevance and substring matches by recency
+func searchOptions(filter SearchFilter) *options.FindOptions {
+	opts := options.Find().SetLimit(filter.Limit)
+	if filter.Regex {
+		return opts.SetSort(bson.D{{Key: "updatedAt", Value: -1}})
+	}
+	return opts.SetSort(bson.M{"score": bson.M{"$meta": "textScore"}})
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TemplateRepositoryImpl implements TemplateRepository
+type TemplateRepositoryImpl struct {
+	collection *mongo.Collection
+}
+
+// NewTemplateRepository creates a new template repository
+func NewTemplateRepository(db *mongo.Database) TemplateRepository {
+	return &TemplateRepositoryImpl{
+		collection: db.Collection("templates"),
+	}
+}
+
+// Create creates a new template
+func (r *TemplateRepositoryImpl) Create(ctx context.Context, template *models.Template) error {
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = time.Now()
+	template.Version = 1
+
+	log.Printf("[REPO_CREATE_TEMPLATE] Creating template: uuid=%s, name=%s", template.UUID, template.Name)
+	result, err := r.collection.InsertOne(ctx, template)
+	if err != nil {
+		log.Printf("[REPO_CREATE_TEMPLATE] Failed to insert template: uuid=%s, error=%v", template.UUID, err)
+		return err
+	}
+
+	template.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetByID retrieves a template by ID
+func (r *TemplateRepositoryImpl) GetByID(ctx context.Context, uuid string) (*models.Template, error) {
+	var template models.Template
+	err := r.collection.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&template)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			log.Printf("[REPO_GET_TEMPLATE] Template not found: uuid=%s", uuid)
+			return nil, nil
+		}
+		log.Printf("[REPO_GET_TEMPLATE] Database error: uuid=%s, error=%v", uuid, err)
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetAll retrieves the templates of a user, ordered by name
+func (r *TemplateRepositoryImpl) GetAll(ctx context.Context, userID string) ([]models.Template, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "uuid", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	templates := []models.Template{}
+	if err = cursor.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// Update updates an existing template (with optimistic locking)
+func (r *TemplateRepositoryImpl) Update(ctx context.Context, template *models.Template) error {
+	template.UpdatedAt = time.Now()
+
+	log.Printf("[REPO_UPDATE_TEMPLATE] Updating template: uuid=%s, version=%d", template.UUID, template.Version)
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"uuid":    template.UUID,
+			"version": template.Version,
+		},
+		bson.M{
+			"$set": bson.M{
+				"name":           template.Name,
+				"description":    template.Description,
+				"color":          template.Color,
+				"completionMode": template.CompletionMode,
+				"items":          template.Items,
+				"updatedAt":      template.UpdatedAt,
+				"updatedBy":      template.UpdatedBy,
+				"version":        template.Version + 1,
+			},
+		},
+	)
+	if err != nil {
+		log.Printf("[REPO_UPDATE_TEMPLATE] Database error: uuid=%s, error=%v", template.UUID, err)
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		log.Printf("[REPO_UPDATE_TEMPLATE] Version conflict: uuid=%s, version=%d", template.UUID, template.Version)
+		return errors.New("version_conflict")
+	}
+
+	template.Version = template.Version + 1
+	return nil
+}
+
+// Delete deletes a template (with optimistic locking)
+// Idempotent - returns success even if the template doesn't exist
+func (r *TemplateRepositoryImpl) Delete(ctx context.Context, uuid string, version int32) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{
+		"uuid":    uuid,
+		"version": version,
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		var existing models.Template
+		err := r.collection.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&existing)
+		if err == nil {
+			log.Printf("[REPO_DELETE_TEMPLATE] Version conflict: uuid=%s, requested_version=%d, current_version=%d", uuid, version, existing.Version)
+			return errors.New("version_conflict")
+		}
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			log.Printf("[REPO_DELETE_TEMPLATE] Template not found (idempotent delete): uuid=%s", uuid)
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrTransactionsUnsupported is returned by InTransaction on deployments without transactions, such as standalone servers
+// Nothing was written when it is returned, so callers can fall back to writing without one
+var ErrTransactionsUnsupported = errors.New("transactions are not supported")
+
+// InTransaction runs fn in a transaction, committing its writes together or not at all
+// Repository calls must use the context fn receives to take part; fn may run more than once on transient errors
+func (r *Repositories) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withTransaction(ctx, r.client, fn)
+}
+
+// withTransaction runs fn in a transaction of the given client, see InTransaction
+func withTransaction(ctx context.Context, client *mongo.Client, fn func(ctx context.Context) error) error {
+	session, err := client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(txCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(txCtx)
+	})
+	if isTransactionUnsupported(err) {
+		return ErrTransactionsUnsupported
+	}
+	return err
+}
+
+// isTransactionUnsupported reports whether an error is a standalone server refusing a transaction
+func isTransactionUnsupported(err error) bool {
+	var cmdErr mongo.CommandError
+	// IllegalOperation: "Transaction numbers are only allowed on a replica set member or mongos"
+	return errors.As(err, &cmdErr) && cmdErr.Code == 20 && strings.HasPrefix(strings.ToLower(cmdErr.Message), "transaction numbers")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/yair12/lists-viewer/server/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
@@ -48,14 +49,68 @@ func (r *UserRepositoryImpl) GetByUsername(ctx context.Context, username string)
 	return &user, nil
 }
 
-// Update updates an existing user
+// Update updates an existing user's profile and preferences
+// The digest sent time is left out, since it is only changed by ClaimDigest and ReleaseDigest
 func (r *UserRepositoryImpl) Update(ctx context.Context, user *models.User) error {
 	_, err := r.collection.UpdateOne(
 		ctx,
 		bson.M{"username": user.Username},
 		bson.M{
-			"$set": user,
+			"$set": bson.M{
+				"iconId":       user.IconID,
+				"color":        user.Color,
+				"lastActivity": user.LastActivity,
+				"preferences":  user.Preferences,
+			},
 		},
 	)
 	return err
 }
+
+// GetDigestSubscribers retrieves the users who opted in to email digests
+func (r *UserRepositoryImpl) GetDigestSubscribers(ctx context.Context) ([]models.User, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"preferences.digest": bson.M{"$in": []string{"daily", "weekly"}},
+		"preferences.email":  bson.M{"$nin": []any{nil, ""}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	users := []models.User{}
+	if err = cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ClaimDigest records that a user's digest is sent at sentAt, reporting whether this call claimed it
+// The claim only succeeds while the last sent time is the one the user was loaded with, so each digest is sent once even with concurrent schedulers
+func (r *UserRepositoryImpl) ClaimDigest(ctx context.Context, user *models.User, sentAt time.Time) (bool, error) {
+	filter := bson.M{"username": user.Username, "digestSentAt": user.DigestSentAt}
+	if user.DigestSentAt == nil {
+		filter["digestSentAt"] = bson.M{"$exists": false}
+	}
+	// MongoDB keeps milliseconds, so the claim is stored as ReleaseDigest looks for it
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"digestSentAt": sentAt.Truncate(time.Millisecond)}})
+	if err != nil {
+		log.Printf("[REPO_CLAIM_DIGEST] Database error: username=%s, error=%v", user.Username, err)
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// ReleaseDigest gives back a digest claimed at sentAt, restoring the sent time the user was loaded with
+func (r *UserRepositoryImpl) ReleaseDigest(ctx context.Context, user *models.User, sentAt time.Time) error {
+	update := bson.M{"$unset": bson.M{"digestSentAt": ""}}
+	if user.DigestSentAt != nil {
+		update = bson.M{"$set": bson.M{"digestSentAt": *user.DigestSentAt}}
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"username": user.Username, "digestSentAt": sentAt.Truncate(time.Millisecond)}, update)
+	if err != nil {
+		log.Printf("[REPO_RELEASE_DIGEST] Database error: username=%s, error=%v", user.Username, err)
+		return err
+	}
+	return nil
+}
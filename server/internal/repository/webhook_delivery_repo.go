@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookDeliveryRepositoryImpl implements WebhookDeliveryRepository
+// The collection is the webhook outbox: deliveries are inserted pending and updated after every attempt
+type WebhookDeliveryRepositoryImpl struct {
+	collection *mongo.Collection
+}
+
+// NewWebhookDeliveryRepository creates a new webhook delivery repository
+func NewWebhookDeliveryRepository(db *mongo.Database) WebhookDeliveryRepository {
+	return &WebhookDeliveryRepositoryImpl{
+		collection: db.Collection("webhook_deliveries"),
+	}
+}
+
+// CreateMany queues deliveries
+func (r *WebhookDeliveryRepositoryImpl) CreateMany(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	documents := make([]interface{}, len(deliveries))
+	for i := range deliveries {
+		documents[i] = deliveries[i]
+	}
+	if _, err := r.collection.InsertMany(ctx, documents); err != nil {
+		log.Printf("[REPO_CREATE_WEBHOOK_DELIVERIES] Failed to insert deliveries: count=%d, error=%v", len(deliveries), err)
+		return err
+	}
+	return nil
+}
+
+// GetByID retrieves a delivery of a webhook
+func (r *WebhookDeliveryRepositoryImpl) GetByID(ctx context.Context, webhookID string, uuid string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.collection.FindOne(ctx, bson.M{"uuid": uuid, "webhookId": webhookID}).Decode(&delivery)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// GetByWebhookID retrieves the most recent deliveries of a webhook, newest first
+func (r *WebhookDeliveryRepositoryImpl) GetByWebhookID(ctx context.Context, webhookID string, limit int64) ([]models.WebhookDelivery, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, bson.M{"webhookId": webhookID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []models.WebhookDelivery{}
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// GetDue retrieves pending deliveries whose next attempt has come, oldest first
+func (r *WebhookDeliveryRepositoryImpl) GetDue(ctx context.Context, now time.Time, limit int64) ([]models.WebhookDelivery, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"status":        models.DeliveryStatusPending,
+		"nextAttemptAt": bson.M{"$lte": now},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []models.WebhookDelivery{}
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Claim leases a pending delivery until the given time, reporting whether this call claimed it
+// Only one caller can claim an attempt; a claim that is never recorded expires and the attempt is retried
+func (r *WebhookDeliveryRepositoryImpl) Claim(ctx context.Context, delivery *models.WebhookDelivery, until time.Time) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"uuid": delivery.UUID, "status": models.DeliveryStatusPending, "nextAttemptAt": delivery.NextAttemptAt},
+		bson.M{"$set": bson.M{"nextAttemptAt": until}},
+	)
+	if err != nil {
+		log.Printf("[REPO_CLAIM_WEBHOOK_DELIVERY] Database error: uuid=%s, error=%v", delivery.UUID, err)
+		return false, err
+	}
+	if result.ModifiedCount == 1 {
+		delivery.NextAttemptAt = &until
+		return true, nil
+	}
+	return false, nil
+}
+
+// RecordAttempt stores the outcome of an attempt of a delivery claimed until the given time, reporting whether the claim still held
+// A claim that expired and was taken over by another run is left to that run
+func (r *WebhookDeliveryRepositoryImpl) RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery, claimedUntil time.Time) (bool, error) {
+	set := bson.M{
+		"status":         delivery.Status,
+		"attempts":       delivery.Attempts,
+		"lastAttemptAt":  delivery.LastAttemptAt,
+		"lastStatusCode": delivery.LastStatusCode,
+		"lastError":      delivery.LastError,
+	}
+	update := bson.M{"$set": set}
+	if delivery.Status == models.DeliveryStatusPending {
+		set["nextAttemptAt"] = delivery.NextAttemptAt
+	} else {
+		set["deliveredAt"] = delivery.DeliveredAt
+		update["$unset"] = bson.M{"nextAttemptAt": ""}
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"uuid": delivery.UUID, "status": models.DeliveryStatusPending, "nextAttemptAt": claimedUntil},
+		update,
+	)
+	if err != nil {
+		log.Printf("[REPO_RECORD_WEBHOOK_ATTEMPT] Database error: uuid=%s, error=%v", delivery.UUID, err)
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// DeleteByWebhookID removes the delivery log of a deleted webhook
+func (r *WebhookDeliveryRepositoryImpl) DeleteByWebhookID(ctx context.Context, webhookID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"webhookId": webhookID})
+	return err
+}
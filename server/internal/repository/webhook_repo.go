@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookRepositoryImpl implements WebhookRepository
+type WebhookRepositoryImpl struct {
+	collection *mongo.Collection
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *mongo.Database) WebhookRepository {
+	return &WebhookRepositoryImpl{
+		collection: db.Collection("webhooks"),
+	}
+}
+
+// Create creates a new webhook
+func (r *WebhookRepositoryImpl) Create(ctx context.Context, webhook *models.Webhook) error {
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = time.Now()
+	webhook.Version = 1
+
+	log.Printf("[REPO_CREATE_WEBHOOK] Creating webhook: uuid=%s, userID=%s", webhook.UUID, webhook.UserID)
+	result, err := r.collection.InsertOne(ctx, webhook)
+	if err != nil {
+		log.Printf("[REPO_CREATE_WEBHOOK] Failed to insert webhook: uuid=%s, error=%v", webhook.UUID, err)
+		return err
+	}
+
+	webhook.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetByID retrieves a webhook by ID
+func (r *WebhookRepositoryImpl) GetByID(ctx context.Context, uuid string) (*models.Webhook, error) {
+	var webhook models.Webhook
+	err := r.collection.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&webhook)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		log.Printf("[REPO_GET_WEBHOOK] Database error: uuid=%s, error=%v", uuid, err)
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// GetByUserID retrieves the webhooks of a user, oldest first
+func (r *WebhookRepositoryImpl) GetByUserID(ctx context.Context, userID string) ([]models.Webhook, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "uuid", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := []models.Webhook{}
+	if err = cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetForList retrieves the active webhooks covering a list: those on the list itself,
+// and those on every list of one of the given users
+func (r *WebhookRepositoryImpl) GetForList(ctx context.Context, listID string, userIDs []string) ([]models.Webhook, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"active": true,
+		"$or": bson.A{
+			bson.M{"listId": listID},
+			bson.M{"listId": bson.M{"$exists": false}, "userId": bson.M{"$in": userIDs}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := []models.Webhook{}
+	if err = cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// Update updates an existing webhook (with optimistic locking)
+func (r *WebhookRepositoryImpl) Update(ctx context.Context, webhook *models.Webhook) error {
+	webhook.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"url":       webhook.URL,
+			"events":    webhook.Events,
+			"active":    webhook.Active,
+			"updatedAt": webhook.UpdatedAt,
+			"version":   webhook.Version + 1,
+		},
+	}
+	// An empty list ID is left out of the document, so GetForList can tell user-wide webhooks apart
+	if webhook.ListID != "" {
+		update["$set"].(bson.M)["listId"] = webhook.ListID
+	} else {
+		update["$unset"] = bson.M{"listId": ""}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"uuid": webhook.UUID, "version": webhook.Version}, update)
+	if err != nil {
+		log.Printf("[REPO_UPDATE_WEBHOOK] Database error: uuid=%s, error=%v", webhook.UUID, err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		log.Printf("[REPO_UPDATE_WEBHOOK] Version conflict: uuid=%s, version=%d", webhook.UUID, webhook.Version)
+		return errors.New("version_conflict")
+	}
+
+	webhook.Version = webhook.Version + 1
+	return nil
+}
+
+// Delete deletes a webhook (with optimistic locking)
+func (r *WebhookRepositoryImpl) Delete(ctx context.Context, uuid string, version int32) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"uuid": uuid, "version": version})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		log.Printf("[REPO_DELETE_WEBHOOK] Version conflict: uuid=%s, version=%d", uuid, version)
+		return errors.New("version_conflict")
+	}
+	return nil
+}
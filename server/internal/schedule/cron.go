@@ -0,0 +1,241 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	// Embedded time zone database, so schedules work on hosts without zoneinfo
+	_ "time/tzdata"
+)
+
+// ErrInvalidCron is returned for a malformed cron expression
+var ErrInvalidCron = errors.New("invalid cron expression")
+
+// maxSearch bounds the search for the next run, so impossible dates such as February 30th terminate
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// cronField describes the allowed range of a cron field
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField  = cronField{name: "minute", min: 0, max: 59}
+	hourField    = cronField{name: "hour", min: 0, max: 23}
+	dayField     = cronField{name: "day of month", min: 1, max: 31}
+	monthField   = cronField{name: "month", min: 1, max: 12, names: monthNames}
+	weekdayField = cronField{name: "day of week", min: 0, max: 7, names: weekdayNames}
+)
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var weekdayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// Cron is a parsed five-field cron expression: minute, hour, day of month, month and day of week
+type Cron struct {
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	anyDay   bool // Day of month is "*"
+	anyWeek  bool // Day of week is "*"
+}
+
+// ParseCron parses a cron expression such as "0 8 * * mon"
+// Fields accept "*", numbers, names (jan, mon), ranges (1-5), lists (1,15) and steps (*/2, 1-10/3)
+func ParseCron(expr string) (*Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 fields, got %d", ErrInvalidCron, len(fields))
+	}
+
+	c := &Cron{anyDay: fields[2] == "*", anyWeek: fields[4] == "*"}
+	var err error
+	if c.minutes, err = parseField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if c.hours, err = parseField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if c.days, err = parseField(fields[2], dayField); err != nil {
+		return nil, err
+	}
+	if c.months, err = parseField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if c.weekdays, err = parseField(fields[4], weekdayField); err != nil {
+		return nil, err
+	}
+
+	// 7 is an alias for Sunday
+	if c.weekdays&(1<<7) != 0 {
+		c.weekdays |= 1
+	}
+	return c, nil
+}
+
+// Next returns the first time strictly after the given time matching the expression, in the location of the given time
+// Times skipped when clocks move forward run at the end of the gap, and times repeated when clocks move back run once
+// It returns the zero time when nothing matches within five years
+func (c *Cron) Next(after time.Time) time.Time {
+	loc := after.Location()
+	// Wall-clock times are searched in UTC, which has no daylight saving transitions, so every step moves forward
+	wall := time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), after.Minute(), 0, 0, time.UTC).Add(time.Minute)
+	limit := wall.Add(maxSearch)
+
+	for wall.Before(limit) {
+		switch {
+		case !has(c.months, int(wall.Month())):
+			wall = time.Date(wall.Year(), wall.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !c.matchesDay(wall):
+			wall = time.Date(wall.Year(), wall.Month(), wall.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !has(c.hours, wall.Hour()):
+			wall = wall.Truncate(time.Hour).Add(time.Hour)
+		case !has(c.minutes, wall.Minute()):
+			wall = wall.Add(time.Minute)
+		default:
+			// A repeated wall-clock time resolves to its first occurrence, which may already have passed
+			if t := resolveWallClock(wall, loc); t.After(after) {
+				return t
+			}
+			wall = wall.Add(time.Minute)
+		}
+	}
+	return time.Time{}
+}
+
+// resolveWallClock returns the instant a wall-clock time, given in UTC, occurs at in loc
+// A time skipped by a daylight saving gap resolves to the end of the gap
+func resolveWallClock(wall time.Time, loc *time.Location) time.Time {
+	t := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), 0, 0, loc)
+	resolved := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	if resolved.Equal(wall) {
+		return t
+	}
+
+	// time.Date moved the skipped time out of the gap, to one side or the other
+	start, end := t.ZoneBounds()
+	if resolved.Before(wall) {
+		return end
+	}
+	return start
+}
+
+// matchesDay applies the cron rule that a restricted day of month and day of week match when either does
+func (c *Cron) matchesDay(t time.Time) bool {
+	day := has(c.days, t.Day())
+	weekday := has(c.weekdays, int(t.Weekday()))
+	if c.anyDay || c.anyWeek {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+// Daily returns the cron expression running every day at the given time
+func Daily(hour int, minute int) string {
+	return fmt.Sprintf("%d %d * * *", minute, hour)
+}
+
+// Weekly returns the cron expression running every week on the given day at the given time
+func Weekly(weekday time.Weekday, hour int, minute int) string {
+	return fmt.Sprintf("%d %d * * %d", minute, hour, weekday)
+}
+
+// ParseClock parses a "HH:MM" time of day
+func ParseClock(clock string) (int, int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, 0, fmt.Errorf("time must be in HH:MM format")
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// ParseWeekday parses a weekday name ("monday", "mon") or number (0 is Sunday)
+func ParseWeekday(value string) (time.Weekday, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if len(value) >= 3 {
+		if day, ok := weekdayNames[value[:3]]; ok && strings.HasPrefix(strings.ToLower(time.Weekday(day).String()), value) {
+			return time.Weekday(day), nil
+		}
+	}
+	if day, err := strconv.Atoi(value); err == nil && day >= 0 && day <= 6 {
+		return time.Weekday(day), nil
+	}
+	return 0, fmt.Errorf("invalid weekday: %s", value)
+}
+
+// parseField parses a comma-separated cron field into a bit set
+func parseField(field string, spec cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("%w: invalid step in %s field: %s", ErrInvalidCron, spec.name, part)
+			}
+		}
+
+		low, high := spec.min, spec.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			lowPart, highPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = parseValue(lowPart, spec); err != nil {
+				return 0, err
+			}
+			if high, err = parseValue(highPart, spec); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("%w: invalid range in %s field: %s", ErrInvalidCron, spec.name, part)
+			}
+		default:
+			value, err := parseValue(rangePart, spec)
+			if err != nil {
+				return 0, err
+			}
+			low = value
+			if !hasStep {
+				high = value
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+// parseValue parses a single number or name of a cron field
+func parseValue(value string, spec cronField) (int, error) {
+	if n, ok := spec.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < spec.min || n > spec.max {
+		return 0, fmt.Errorf("%w: invalid %s: %s", ErrInvalidCron, spec.name, value)
+	}
+	return n, nil
+}
+
+// has reports whether a value is in a bit set
+func has(bits uint64, value int) bool {
+	return bits&(1<<uint(value)) != 0
+}
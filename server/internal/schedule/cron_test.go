@@ -0,0 +1,119 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCronFields(t *testing.T) {
+	tests := []struct {
+		expr  string
+		after string
+		want  []string
+	}{
+		{"*/20 * * * *", "2026-01-05T10:05:00Z", []string{"2026-01-05T10:20:00Z", "2026-01-05T10:40:00Z", "2026-01-05T11:00:00Z"}},
+		{"0 9-17/4 * * *", "2026-01-05T10:00:00Z", []string{"2026-01-05T13:00:00Z", "2026-01-05T17:00:00Z", "2026-01-06T09:00:00Z"}},
+		{"15 8 * * mon-wed", "2026-01-06T09:00:00Z", []string{"2026-01-07T08:15:00Z", "2026-01-12T08:15:00Z"}},
+		{"0 0 1,15 * *", "2026-01-01T00:00:00Z", []string{"2026-01-15T00:00:00Z", "2026-02-01T00:00:00Z"}},
+		{"0 12 * jan,jul 7", "2026-01-31T12:00:00Z", []string{"2026-07-05T12:00:00Z"}},
+		// A restricted day of month and day of week match when either does
+		{"0 6 13 * fri", "2026-02-01T00:00:00Z", []string{"2026-02-06T06:00:00Z", "2026-02-13T06:00:00Z", "2026-02-20T06:00:00Z"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("Failed to parse %q: %v", tt.expr, err)
+			}
+			next := mustParseTime(t, tt.after)
+			for _, want := range tt.want {
+				next = c.Next(next)
+				if !next.Equal(mustParseTime(t, want)) {
+					t.Fatalf("Expected %s, got %s", want, next.Format(time.RFC3339))
+				}
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		if _, err := ParseCron(expr); !errors.Is(err, ErrInvalidCron) {
+			t.Errorf("Expected %q to be rejected, got %v", expr, err)
+		}
+	}
+}
+
+func TestCronNextAcrossDaylightSaving(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load time zone: %v", err)
+	}
+
+	t.Run("Times skipped by the gap run when it ends", func(t *testing.T) {
+		c, _ := ParseCron("30 2 * * *")
+		next := c.Next(time.Date(2026, 3, 7, 12, 0, 0, 0, newYork))
+		if want := time.Date(2026, 3, 8, 3, 0, 0, 0, newYork); !next.Equal(want) {
+			t.Errorf("Expected %s, got %s", want, next)
+		}
+		if following := c.Next(next); following.Day() != 9 || following.Hour() != 2 || following.Minute() != 30 {
+			t.Errorf("Expected 02:30 on March 9th, got %s", following)
+		}
+	})
+
+	t.Run("Hourly schedule steps over the gap", func(t *testing.T) {
+		c, _ := ParseCron("0 * * * *")
+		first := c.Next(time.Date(2026, 3, 8, 1, 30, 0, 0, newYork))
+		if first.Hour() != 3 || first.Sub(time.Date(2026, 3, 8, 1, 30, 0, 0, newYork)) != 30*time.Minute {
+			t.Errorf("Expected 03:00 half an hour later, got %s", first)
+		}
+	})
+
+	t.Run("Times repeated by the overlap run once", func(t *testing.T) {
+		c, _ := ParseCron("30 1 * * *")
+		first := c.Next(time.Date(2026, 10, 31, 12, 0, 0, 0, newYork))
+		if first.Day() != 1 || first.Hour() != 1 || first.Minute() != 30 {
+			t.Fatalf("Expected 01:30 on November 1st, got %s", first)
+		}
+		if second := c.Next(first); second.Day() != 2 || second.Hour() != 1 || second.Minute() != 30 {
+			t.Errorf("Expected the next run on November 2nd, got %s", second)
+		}
+		// Starting between the two occurrences does not run it again
+		if next := c.Next(first.Add(time.Hour - time.Minute)); next.Day() != 2 {
+			t.Errorf("Expected the repeated time to be skipped, got %s", next)
+		}
+	})
+
+	t.Run("Every minute keeps moving forward", func(t *testing.T) {
+		c, _ := ParseCron("* * * * *")
+		for _, start := range []time.Time{time.Date(2026, 3, 8, 1, 0, 0, 0, newYork), time.Date(2026, 11, 1, 0, 30, 0, 0, newYork)} {
+			previous := start
+			for i := 0; i < 180; i++ {
+				next := c.Next(previous)
+				// Runs are a minute apart, except across the repeated hour, which runs once
+				if !next.After(previous) || next.Sub(previous) > time.Hour+time.Minute {
+					t.Fatalf("Expected the run after %s to follow it, got %s", previous, next)
+				}
+				previous = next
+			}
+		}
+	})
+}
+
+func TestCronNextImpossibleDate(t *testing.T) {
+	c, _ := ParseCron("0 0 30 2 *")
+	if next := c.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); !next.IsZero() {
+		t.Errorf("Expected no run on February 30th, got %s", next)
+	}
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("Failed to parse time %q: %v", value, err)
+	}
+	return parsed
+}
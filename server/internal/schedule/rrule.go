@@ -0,0 +1,185 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRule is returned for a malformed or unsupported recurrence rule
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// Recurrence frequencies
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+)
+
+var ruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Rule is a recurrence rule in a subset of the iCalendar RRULE syntax
+// Supported parts are FREQ (DAILY, WEEKLY, MONTHLY), INTERVAL, BYDAY (daily and weekly) and BYMONTHDAY (monthly)
+type Rule struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay int // Day of the month; negative values count from the end (-1 is the last day)
+
+	// AnchorDay is the day of the month MONTHLY rules without BYMONTHDAY fall on, so short months and late
+	// completions don't move later occurrences; it is not part of the RRULE text and defaults to the day of the given time
+	AnchorDay int
+}
+
+// ParseRule parses a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", with or without an "RRULE:" prefix
+func ParseRule(text string) (*Rule, error) {
+	text = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(text)), "RRULE:")
+	rule := &Rule{Interval: 1}
+	for _, part := range strings.Split(text, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not a KEY=VALUE pair", ErrInvalidRule, part)
+		}
+
+		switch key {
+		case "FREQ":
+			if value != FreqDaily && value != FreqWeekly && value != FreqMonthly {
+				return nil, fmt.Errorf("%w: FREQ must be DAILY, WEEKLY or MONTHLY", ErrInvalidRule)
+			}
+			rule.Freq = value
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 || interval > 366 {
+				return nil, fmt.Errorf("%w: INTERVAL must be between 1 and 366", ErrInvalidRule)
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := ruleWeekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("%w: invalid BYDAY value %q", ErrInvalidRule, day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			day, err := strconv.Atoi(value)
+			if err != nil || day == 0 || day < -31 || day > 31 {
+				return nil, fmt.Errorf("%w: BYMONTHDAY must be between 1 and 31 or -31 and -1", ErrInvalidRule)
+			}
+			rule.ByMonthDay = day
+		default:
+			return nil, fmt.Errorf("%w: %s is not supported", ErrInvalidRule, key)
+		}
+	}
+
+	switch {
+	case rule.Freq == "":
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	case len(rule.ByDay) > 0 && rule.Freq == FreqMonthly:
+		return nil, fmt.Errorf("%w: BYDAY is only supported with DAILY or WEEKLY", ErrInvalidRule)
+	case rule.ByMonthDay != 0 && rule.Freq != FreqMonthly:
+		return nil, fmt.Errorf("%w: BYMONTHDAY is only supported with MONTHLY", ErrInvalidRule)
+	}
+	return rule, nil
+}
+
+// String returns the rule in canonical RRULE syntax
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, weekday := range r.ByDay {
+			days[i] = strings.ToUpper(weekday.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.ByMonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the start of the first occurrence day after the day of the given time, in the location of the given time
+// Occurrences are whole days: an item completed on a Monday with a daily rule recurs at midnight on Tuesday
+func (r *Rule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	day := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, loc)
+
+	switch r.Freq {
+	case FreqDaily:
+		for i := 1; i <= 7; i++ {
+			next := day.AddDate(0, 0, i*r.Interval)
+			if r.onDay(next) {
+				return next
+			}
+		}
+		// None of the days is reachable with this interval, e.g. every 7 days on another weekday
+		return time.Time{}
+	case FreqWeekly:
+		if len(r.ByDay) == 0 {
+			return day.AddDate(0, 0, 7*r.Interval)
+		}
+		for i := 1; i <= 7; i++ {
+			next := day.AddDate(0, 0, i)
+			if !r.onDay(next) {
+				continue
+			}
+			// Later days of the same week follow directly; the next week's days wait for the interval
+			if weekStart(next).After(weekStart(day)) {
+				next = next.AddDate(0, 0, 7*(r.Interval-1))
+			}
+			return next
+		}
+	case FreqMonthly:
+		target := r.ByMonthDay
+		if target == 0 {
+			target = r.AnchorDay
+		}
+		if target == 0 {
+			target = after.Day()
+		}
+		if this := monthDay(day.Year(), day.Month(), target, loc); this.After(day) {
+			return this
+		}
+		return monthDay(day.Year(), day.Month()+time.Month(r.Interval), target, loc)
+	}
+	return time.Time{}
+}
+
+// onDay reports whether BYDAY allows the given day
+func (r *Rule) onDay(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, weekday := range r.ByDay {
+		if t.Weekday() == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// weekStart returns the Monday starting the week of a day
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// monthDay returns the given day of a month; days past the end of the month fall on its last day
+func monthDay(year int, month time.Month, day int, loc *time.Location) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	last := first.AddDate(0, 1, -1).Day()
+	if day < 0 {
+		day = last + day + 1
+	}
+	day = min(max(day, 1), last)
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, loc)
+}
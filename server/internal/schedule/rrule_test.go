@@ -0,0 +1,117 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRule(t *testing.T) {
+	rule, err := ParseRule("rrule:freq=weekly;interval=2;byday=mo,th")
+	if err != nil {
+		t.Fatalf("Failed to parse rule: %v", err)
+	}
+	if got := rule.String(); got != "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH" {
+		t.Errorf("Expected the canonical rule, got %s", got)
+	}
+
+	for _, text := range []string{"", "FREQ=YEARLY", "INTERVAL=2", "FREQ=DAILY;INTERVAL=0", "FREQ=DAILY;BYDAY=XX", "FREQ=MONTHLY;BYDAY=MO", "FREQ=WEEKLY;BYMONTHDAY=1", "FREQ=MONTHLY;BYMONTHDAY=32", "FREQ=DAILY;COUNT=3"} {
+		if _, err := ParseRule(text); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("Expected %q to be rejected, got %v", text, err)
+		}
+	}
+}
+
+func TestRuleNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		after time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "Daily",
+			rule:  "FREQ=DAILY;INTERVAL=2",
+			after: time.Date(2026, 1, 5, 18, 30, 0, 0, time.UTC),
+			want:  []time.Time{time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:  "Daily on weekdays",
+			rule:  "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+			after: time.Date(2026, 1, 9, 12, 0, 0, 0, time.UTC), // Friday
+			want:  []time.Time{time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 13, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:  "Every other week on Monday and Thursday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			after: time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC), // Monday
+			want: []time.Time{
+				time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 22, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "Last day of the month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			after: time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC),
+			want:  []time.Time{time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRule(tt.rule)
+			if err != nil {
+				t.Fatalf("Failed to parse rule: %v", err)
+			}
+			next := tt.after
+			for _, want := range tt.want {
+				next = rule.Next(next)
+				if !next.Equal(want) {
+					t.Fatalf("Expected %s, got %s", want, next)
+				}
+			}
+		})
+	}
+}
+
+func TestRuleNextMonthlyAnchor(t *testing.T) {
+	rule, _ := ParseRule("FREQ=MONTHLY")
+	rule.AnchorDay = 31
+
+	t.Run("Short months don't move later occurrences", func(t *testing.T) {
+		next := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+		for _, want := range []time.Time{
+			time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC),
+		} {
+			next = rule.Next(next)
+			if !next.Equal(want) {
+				t.Fatalf("Expected %s, got %s", want, next)
+			}
+		}
+	})
+
+	t.Run("Late completions don't move later occurrences", func(t *testing.T) {
+		rule.AnchorDay = 10
+		next := rule.Next(time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC))
+		if want := time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC); !next.Equal(want) {
+			t.Errorf("Expected %s, got %s", want, next)
+		}
+		next = rule.Next(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+		if want := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC); !next.Equal(want) {
+			t.Errorf("Expected an early completion to recur this month, got %s", next)
+		}
+	})
+
+	t.Run("Without an anchor the day of the given time is used", func(t *testing.T) {
+		rule.AnchorDay = 0
+		next := rule.Next(time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC))
+		if want := time.Date(2026, 4, 14, 0, 0, 0, 0, time.UTC); !next.Equal(want) {
+			t.Errorf("Expected %s, got %s", want, next)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a task the scheduler runs periodically
+// Run receives the time of the tick and must be safe to repeat, since ticks can overlap restarts or other instances
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context, now time.Time) error
+}
+
+// Scheduler runs background jobs until its context is cancelled
+type Scheduler struct {
+	jobs []Job
+	wg   sync.WaitGroup
+}
+
+// New creates a scheduler for the given jobs
+func New(jobs ...Job) *Scheduler {
+	return &Scheduler{jobs: jobs}
+}
+
+// Start runs every job once immediately and then on its interval, each in its own goroutine
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+	log.Printf("[SCHEDULER] Started %d jobs", len(s.jobs))
+}
+
+// Wait blocks until every job has stopped after its context was cancelled
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// loop runs a job on its interval; a run never overlaps the previous one
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.run(ctx, job, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run runs a job once, logging failures and recovering from panics so that other runs continue
+func (s *Scheduler) run(ctx context.Context, job Job, now time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[SCHEDULER] Job panicked: job=%s, panic=%v", job.Name, r)
+		}
+	}()
+
+	if err := job.Run(ctx, now); err != nil {
+		log.Printf("[SCHEDULER] Job failed: job=%s, error=%v", job.Name, err)
+	}
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"log"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/yair12/lists-viewer/server/internal/mailer"
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/repository"
+)
+
+const (
+	// digestHour is the local hour digests are sent at; weekly digests go out on Mondays
+	digestHour = 7
+
+	// maxDigestOpenItems is the most open items listed per list; the rest are counted
+	maxDigestOpenItems = 20
+)
+
+//go:embed templates/digest.txt.tmpl templates/digest.html.tmpl
+var digestTemplateFiles embed.FS
+
+var (
+	digestTextTemplate = texttemplate.Must(texttemplate.ParseFS(digestTemplateFiles, "templates/digest.txt.tmpl"))
+	digestHTMLTemplate = htmltemplate.Must(htmltemplate.ParseFS(digestTemplateFiles, "templates/digest.html.tmpl"))
+)
+
+// digest is the content of one user's email digest, as passed to the templates
+type digest struct {
+	Username       string
+	Frequency      string // "daily" or "weekly"
+	Period         string // The days covered, e.g. "Mon Oct 19" or "Oct 12 – Oct 18"
+	Subject        string
+	Lists          []digestList
+	TotalAdded     int
+	TotalCompleted int
+	TotalOpen      int
+}
+
+// digestList is the activity of one list in a digest
+type digestList struct {
+	Name      string
+	Added     []digestItem
+	Completed []digestItem
+	Open      []digestItem
+	MoreOpen  int // Open items beyond those listed
+}
+
+// digestItem is an item in a digest, e.g. "2 kg tomatoes" with detail "in Produce, by dana"
+type digestItem struct {
+	Name   string
+	Detail string
+}
+
+// DigestService emails users who opted in a daily or weekly summary of the activity on their lists
+type DigestService struct {
+	repo   *repository.Repositories
+	mailer *mailer.Mailer
+}
+
+// NewDigestService creates a new digest service sending through the given mailer
+func NewDigestService(repo *repository.Repositories, m *mailer.Mailer) *DigestService {
+	return &DigestService{repo: repo, mailer: m}
+}
+
+// SendDueDigests mails the daily or weekly digest of every subscriber whose digest time has passed since their last one
+// A period with nothing to report is marked done without mail. A digest is claimed just before it is mailed, so concurrent
+// runs mail it once, and the claim is given back when mailing fails, so the next run tries again
+func (s *DigestService) SendDueDigests(ctx context.Context, now time.Time) error {
+	users, err := s.repo.User.GetDigestSubscribers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get digest subscribers: %w", err)
+	}
+
+	for i := range users {
+		user := &users[i]
+		slot := digestSlot(user.Preferences.Digest, now.In(preferredLocation(user)))
+		if user.DigestSentAt != nil && !user.DigestSentAt.Before(slot) {
+			continue
+		}
+
+		content, err := s.buildDigest(ctx, user, digestStart(user.Preferences.Digest, slot), slot)
+		if err != nil {
+			log.Printf("[SERVICE_SEND_DIGESTS] Failed to build digest: username=%s, error=%v", user.Username, err)
+			continue
+		}
+
+		if len(content.Lists) == 0 {
+			log.Printf("[SERVICE_SEND_DIGESTS] Nothing to report, skipping: username=%s", user.Username)
+			s.repo.User.ClaimDigest(ctx, user, now)
+			continue
+		}
+
+		msg, err := renderDigest(content)
+		if err != nil {
+			log.Printf("[SERVICE_SEND_DIGESTS] Failed to render digest: username=%s, error=%v", user.Username, err)
+			continue
+		}
+		msg.To = user.Preferences.Email
+
+		claimed, err := s.repo.User.ClaimDigest(ctx, user, now)
+		if err != nil || !claimed {
+			continue
+		}
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			log.Printf("[SERVICE_SEND_DIGESTS] Failed to send digest, releasing it: username=%s, error=%v", user.Username, err)
+			if err := s.repo.User.ReleaseDigest(context.WithoutCancel(ctx), user, now); err != nil {
+				log.Printf("[SERVICE_SEND_DIGESTS] Failed to release digest: username=%s, error=%v", user.Username, err)
+			}
+			continue
+		}
+		log.Printf("[SERVICE_SEND_DIGESTS] Sent digest: username=%s, frequency=%s, lists=%d", user.Username, user.Preferences.Digest, len(content.Lists))
+	}
+	return nil
+}
+
+// buildDigest summarizes the lists a user owns or is a member of for the period from start to end
+// Lists with nothing added, completed or open are left out
+func (s *DigestService) buildDigest(ctx context.Context, user *models.User, start time.Time, end time.Time) (*digest, error) {
+	lists, err := s.repo.List.GetAll(ctx, user.Username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lists: %w", err)
+	}
+
+	content := &digest{
+		Username:  user.Username,
+		Frequency: user.Preferences.Digest,
+		Period:    digestPeriod(user.Preferences.Digest, start, end),
+	}
+	content.Subject = fmt.Sprintf("Your %s list digest for %s", content.Frequency, content.Period)
+
+	for i := range lists {
+		list := &lists[i]
+		if list.UserID != user.Username && !slices.Contains(list.Members, user.Username) {
+			continue
+		}
+
+		summary := digestList{Name: list.Name}
+		if err := s.collectDigestItems(ctx, list, list.UUID, "", user.Username, start, end, map[string]bool{}, &summary); err != nil {
+			return nil, err
+		}
+		if len(summary.Added) == 0 && len(summary.Completed) == 0 && len(summary.Open) == 0 {
+			continue
+		}
+
+		content.TotalAdded += len(summary.Added)
+		content.TotalCompleted += len(summary.Completed)
+		content.TotalOpen += len(summary.Open) + summary.MoreOpen
+		content.Lists = append(content.Lists, summary)
+	}
+	return content, nil
+}
+
+// collectDigestItems adds the items of a list and its nested lists to a list summary
+// nestedName is the name of the nested list being collected, empty for the top-level list
+func (s *DigestService) collectDigestItems(ctx context.Context, list *models.List, listID string, nestedName string, userID string, start time.Time, end time.Time, seen map[string]bool, summary *digestList) error {
+	seen[listID] = true
+	items, _, err := s.repo.Item.GetByListID(ctx, listID, models.ItemQuery{})
+	if err != nil {
+		return fmt.Errorf("failed to get items: %w", err)
+	}
+
+	for i := range items {
+		item := &items[i]
+		if item.Type == "list" {
+			if !seen[item.UUID] {
+				if err := s.collectDigestItems(ctx, list, item.UUID, item.Name, userID, start, end, seen, summary); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		name := describeQuantity(item.Name, item.Quantity, item.QuantityType)
+		if !item.CreatedAt.Before(start) && item.CreatedAt.Before(end) {
+			summary.Added = append(summary.Added, digestItem{Name: name, Detail: digestDetail(nestedName, item.CreatedBy)})
+		}
+		if item.Completed && item.CompletedAt != nil && !item.CompletedAt.Before(start) && item.CompletedAt.Before(end) {
+			summary.Completed = append(summary.Completed, digestItem{Name: name, Detail: digestDetail(nestedName, item.CompletedBy)})
+		}
+		// Open items are those still open at the end of the period: added before it and not completed by then
+		completedLater := item.CompletedAt != nil && !item.CompletedAt.Before(end)
+		if item.CreatedAt.Before(end) && (!isCompletedBy(item, list, userID) || completedLater) {
+			if len(summary.Open) < maxDigestOpenItems {
+				summary.Open = append(summary.Open, digestItem{Name: name, Detail: digestDetail(nestedName, "")})
+			} else {
+				summary.MoreOpen++
+			}
+		}
+	}
+	return nil
+}
+
+// renderDigest renders a digest as plain text and HTML
+func renderDigest(content *digest) (mailer.Message, error) {
+	text := new(bytes.Buffer)
+	if err := digestTextTemplate.Execute(text, content); err != nil {
+		return mailer.Message{}, fmt.Errorf("failed to render text digest: %w", err)
+	}
+	html := new(bytes.Buffer)
+	if err := digestHTMLTemplate.Execute(html, content); err != nil {
+		return mailer.Message{}, fmt.Errorf("failed to render HTML digest: %w", err)
+	}
+	return mailer.Message{Subject: content.Subject, Text: text.String(), HTML: html.String()}, nil
+}
+
+// digestSlot returns the latest scheduled digest time at or before now, in now's location
+func digestSlot(frequency string, now time.Time) time.Time {
+	slot := time.Date(now.Year(), now.Month(), now.Day(), digestHour, 0, 0, 0, now.Location())
+	if slot.After(now) {
+		slot = slot.AddDate(0, 0, -1)
+	}
+	if frequency == "weekly" {
+		for slot.Weekday() != time.Monday {
+			slot = slot.AddDate(0, 0, -1)
+		}
+	}
+	return slot
+}
+
+// digestStart returns the start of the period a digest sent at slot covers
+func digestStart(frequency string, slot time.Time) time.Time {
+	if frequency == "weekly" {
+		return slot.AddDate(0, 0, -7)
+	}
+	return slot.AddDate(0, 0, -1)
+}
+
+// digestPeriod describes the days a digest covers, e.g. "Mon Oct 19" or "Oct 12 – Oct 18"
+func digestPeriod(frequency string, start time.Time, end time.Time) string {
+	if frequency == "weekly" {
+		return start.Format("Jan 2") + " – " + end.AddDate(0, 0, -1).Format("Jan 2")
+	}
+	return start.Format("Mon Jan 2")
+}
+
+// digestDetail describes where an item is and who changed it, e.g. "in Produce, by dana"
+func digestDetail(nestedName string, by string) string {
+	details := []string{}
+	if nestedName != "" {
+		details = append(details, "in "+nestedName)
+	}
+	if by != "" {
+		details = append(details, "by "+by)
+	}
+	return strings.Join(details, ", ")
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/mail"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/quickadd"
+	"github.com/yair12/lists-viewer/server/internal/repository"
+)
+
+// listPrepositions introduce the list named at the end of a spoken text ("eggs to groceries")
+var listPrepositions = map[string]bool{"to": true, "onto": true, "on": true, "in": true, "into": true}
+
+// InboundService adds items from outside the app, e.g. voice assistants and shortcuts, authorized by tokens
+type InboundService struct {
+	repo  *repository.Repositories
+	items *ItemService
+}
+
+// NewInboundService creates a new inbound service adding items through the given item service
+func NewInboundService(repo *repository.Repositories, items *ItemService) *InboundService {
+	return &InboundService{repo: repo, items: items}
+}
+
+// CreateToken creates an inbound token for the caller, returning the token itself once
+func (s *InboundService) CreateToken(ctx context.Context, req *models.CreateInboundTokenRequest, userID string) (*models.InboundTokenResponse, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		return nil, fmt.Errorf("validation_error: name must be between 1 and 100 characters")
+	}
+	if req.ListID != "" {
+		list, err := s.repo.List.GetByID(ctx, req.ListID, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get list: %w", err)
+		}
+		if list == nil {
+			return nil, fmt.Errorf("list not found")
+		}
+	}
+
+	senders := []string{}
+	for _, sender := range req.Senders {
+		address, err := mail.ParseAddress(sender)
+		if err != nil {
+			return nil, fmt.Errorf("validation_error: %s is not an email address", sender)
+		}
+		if normalized := strings.ToLower(address.Address); !slices.Contains(senders, normalized) {
+			senders = append(senders, normalized)
+		}
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	value := hex.EncodeToString(secret)
+
+	token := &models.InboundToken{
+		UUID:      uuid.New().String(),
+		UserID:    userID,
+		Name:      req.Name,
+		TokenHash: hashToken(value),
+		ListID:    req.ListID,
+		Senders:   senders,
+	}
+	if err := s.repo.Inbound.Create(ctx, token); err != nil {
+		return nil, fmt.Errorf("failed to create token: %w", err)
+	}
+
+	log.Printf("[SERVICE_CREATE_INBOUND_TOKEN] Created token: uuid=%s, userID=%s, listID=%s", token.UUID, userID, token.ListID)
+	response := mapInboundTokenToResponse(token)
+	response.Token = value
+	return response, nil
+}
+
+// GetTokens retrieves the caller's inbound tokens
+func (s *InboundService) GetTokens(ctx context.Context, userID string) ([]models.InboundTokenResponse, error) {
+	tokens, err := s.repo.Inbound.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tokens: %w", err)
+	}
+
+	responses := make([]models.InboundTokenResponse, len(tokens))
+	for i := range tokens {
+		responses[i] = *mapInboundTokenToResponse(&tokens[i])
+	}
+	return responses, nil
+}
+
+// DeleteToken revokes one of the caller's inbound tokens
+func (s *InboundService) DeleteToken(ctx context.Context, tokenID string, userID string) error {
+	deleted, err := s.repo.Inbound.Delete(ctx, userID, tokenID)
+	if err != nil {
+		return fmt.Errorf("failed to delete token: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("token not found")
+	}
+	log.Printf("[SERVICE_DELETE_INBOUND_TOKEN] Deleted token: userID=%s, uuid=%s", userID, tokenID)
+	return nil
+}
+
+// QuickAdd adds the item described by a text on behalf of the token's user
+// The list is taken from req.List, from the end of the text ("add 2 kg tomatoes to groceries"), or from the token's default list
+func (s *InboundService) QuickAdd(ctx context.Context, tokenValue string, req *models.InboundQuickAddRequest) (*models.InboundQuickAddResponse, error) {
+	token, err := s.authenticate(ctx, tokenValue)
+	if err != nil {
+		return nil, err
+	}
+
+	lists, err := s.repo.List.GetAll(ctx, token.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lists: %w", err)
+	}
+
+	text := strings.TrimSpace(req.Text)
+	if tokens := strings.Fields(text); len(tokens) > 1 && strings.EqualFold(tokens[0], "add") {
+		text = strings.Join(tokens[1:], " ")
+	}
+
+	var list *models.List
+	switch {
+	case req.List != "":
+		if list = findList(lists, req.List, token.UserID); list == nil {
+			return nil, fmt.Errorf("validation_error: there is no list called %s", req.List)
+		}
+	default:
+		text, list = splitListName(text, lists, token.UserID)
+	}
+	if list == nil && token.ListID != "" {
+		list = findListByID(lists, token.ListID)
+	}
+	if list == nil {
+		return nil, fmt.Errorf("validation_error: say which list to add to, e.g. \"eggs to groceries\"")
+	}
+
+	parsed, err := quickadd.Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("validation_error: %v", err)
+	}
+
+	item, err := s.items.CreateItem(ctx, list.UUID, &models.CreateItemRequest{
+		Type:         "item",
+		Name:         parsed.Name,
+		Quantity:     parsed.Quantity,
+		QuantityType: parsed.QuantityType,
+	}, token.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Inbound.MarkUsed(ctx, token.UUID, time.Now()); err != nil {
+		log.Printf("[SERVICE_INBOUND_QUICK_ADD] Failed to record token use: uuid=%s, error=%v", token.UUID, err)
+	}
+
+	message := fmt.Sprintf("Added %s to %s", describeParsedItem(parsed), list.Name)
+	if len(item.Duplicates) > 0 {
+		message += " (it was already on the list)"
+	}
+	log.Printf("[SERVICE_INBOUND_QUICK_ADD] Added item: itemID=%s, listID=%s, token=%s", item.ID, list.UUID, token.UUID)
+	return &models.InboundQuickAddResponse{Message: message, ListID: list.UUID, Item: item}, nil
+}
+
+// authenticate looks up the token a request was made with
+func (s *InboundService) authenticate(ctx context.Context, tokenValue string) (*models.InboundToken, error) {
+	if tokenValue == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
+	token, err := s.repo.Inbound.GetByHash(ctx, hashToken(tokenValue))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	if token == nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+	return token, nil
+}
+
+// splitListName splits a text such as "eggs to the groceries list" into the item text and the list it names
+// The last preposition followed by a known list wins, so item names may contain prepositions themselves
+func splitListName(text string, lists []models.List, userID string) (string, *models.List) {
+	tokens := strings.Fields(text)
+	for i := len(tokens) - 2; i > 0; i-- {
+		if !listPrepositions[strings.ToLower(tokens[i])] {
+			continue
+		}
+
+		name := tokens[i+1:]
+		if len(name) > 1 && slices.Contains([]string{"the", "my", "our"}, strings.ToLower(name[0])) {
+			name = name[1:]
+		}
+		if list := findList(lists, strings.Join(name, " "), userID); list != nil {
+			return strings.Join(tokens[:i], " "), list
+		}
+		if len(name) > 1 && strings.EqualFold(name[len(name)-1], "list") {
+			if list := findList(lists, strings.Join(name[:len(name)-1], " "), userID); list != nil {
+				return strings.Join(tokens[:i], " "), list
+			}
+		}
+	}
+	return text, nil
+}
+
+// findList finds a list by name or alias, ignoring case
+// When several lists match, one the user owns or is a member of is preferred
+func findList(lists []models.List, name string, userID string) *models.List {
+	name = strings.TrimSpace(name)
+	var found *models.List
+	for i := range lists {
+		list := &lists[i]
+		matches := strings.EqualFold(list.Name, name) || slices.ContainsFunc(list.Aliases, func(alias string) bool {
+			return strings.EqualFold(alias, name)
+		})
+		if !matches {
+			continue
+		}
+		if list.UserID == userID || slices.Contains(list.Members, userID) {
+			return list
+		}
+		if found == nil {
+			found = list
+		}
+	}
+	return found
+}
+
+// findListByID finds a list by ID
+func findListByID(lists []models.List, listID string) *models.List {
+	for i := range lists {
+		if lists[i].UUID == listID {
+			return &lists[i]
+		}
+	}
+	return nil
+}
+
+// describeParsedItem describes a parsed item for a confirmation, e.g. "2 kg tomatoes" or "3 eggs"
+func describeParsedItem(parsed quickadd.Result) string {
+	return describeQuantity(parsed.Name, parsed.Quantity, parsed.QuantityType)
+}
+
+// describeQuantity writes a name with its quantity, leaving out the "pcs" unit
+func describeQuantity(name string, quantity *float64, unit string) string {
+	if quantity == nil {
+		return name
+	}
+	value := strconv.FormatFloat(*quantity, 'f', -1, 64)
+	if unit == "" || unit == "pcs" {
+		return value + " " + name
+	}
+	return value + " " + unit + " " + name
+}
+
+// hashToken returns the stored form of an inbound token
+func hashToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// mapInboundTokenToResponse converts an InboundToken model to a response, without the token itself
+func mapInboundTokenToResponse(token *models.InboundToken) *models.InboundTokenResponse {
+	return &models.InboundTokenResponse{
+		ID:         token.UUID,
+		Name:       token.Name,
+		ListID:     token.ListID,
+		Senders:    token.Senders,
+		CreatedAt:  token.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		LastUsedAt: formatOptionalTime(token.LastUsedAt),
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+)
+
+func TestSplitListName(t *testing.T) {
+	lists := []models.List{
+		{UUID: "groceries", Name: "Groceries", UserID: "me", Aliases: []string{"shopping"}},
+		{UUID: "hardware", Name: "Hardware store", UserID: "me"},
+		{UUID: "theirs", Name: "Pharmacy", UserID: "someone"},
+		{UUID: "mine", Name: "Pharmacy", UserID: "someone", Members: []string{"me"}},
+	}
+
+	tests := []struct {
+		text   string
+		item   string
+		listID string // Empty when no list is named
+	}{
+		{"eggs to groceries", "eggs", "groceries"},
+		{"2 kg tomatoes to the shopping list", "2 kg tomatoes", "groceries"},
+		{"nails x20 onto my Hardware Store", "nails x20", "hardware"},
+		{"bread in the oven to groceries", "bread in the oven", "groceries"},
+		{"aspirin to pharmacy", "aspirin", "mine"},
+		{"bread in the oven", "bread in the oven", ""},
+		{"to groceries", "to groceries", ""},
+		{"eggs", "eggs", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			item, list := splitListName(tt.text, lists, "me")
+			listID := ""
+			if list != nil {
+				listID = list.UUID
+			}
+			if item != tt.item || listID != tt.listID {
+				t.Errorf("Expected %q on %q, got %q on %q", tt.item, tt.listID, item, listID)
+			}
+		})
+	}
+}
+
+func TestDescribeQuantity(t *testing.T) {
+	two, half := 2.0, 0.5
+	tests := []struct {
+		quantity *float64
+		unit     string
+		want     string
+	}{
+		{nil, "", "eggs"},
+		{&two, "", "2 eggs"},
+		{&two, "pcs", "2 eggs"},
+		{&half, "kg", "0.5 kg eggs"},
+	}
+
+	for _, tt := range tests {
+		if got := describeQuantity("eggs", tt.quantity, tt.unit); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
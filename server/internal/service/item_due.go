@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/repository"
+)
+
+const (
+	// maxReminderOffset is the earliest a reminder can be sent before the due time, in minutes (30 days)
+	maxReminderOffset = 30 * 24 * 60
+
+	// maxDueSoonWindow is the longest look-ahead of the due-soon view
+	maxDueSoonWindow = 30 * 24 * time.Hour
+
+	// maxDueItems is the largest number of items returned by a due view
+	maxDueItems = 500
+)
+
+// SetDueDate sets the due date and optional reminder of an item, replacing any previous one
+func (s *ItemService) SetDueDate(ctx context.Context, listID string, itemID string, req *models.SetDueDateRequest, userID string) (*models.ItemResponse, error) {
+	item, err := s.repo.Item.GetByID(ctx, listID, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+	if item == nil {
+		return nil, fmt.Errorf("item not found")
+	}
+	if item.Type != "item" {
+		return nil, fmt.Errorf("validation_error: nested lists cannot have a due date")
+	}
+	if item.Version != req.Version {
+		log.Printf("[SERVICE_SET_DUE_DATE] Version conflict: itemID=%s, requested=%d, current=%d", itemID, req.Version, item.Version)
+		return nil, fmt.Errorf("version_conflict")
+	}
+
+	list, err := s.rootList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyDueDate(ctx, item, req.Due, req.ReminderOffset, userID); err != nil {
+		return nil, err
+	}
+	item.UpdatedBy = userID
+
+	log.Printf("[SERVICE_SET_DUE_DATE] Setting due date: itemID=%s, dueAt=%v, allDay=%t", itemID, item.DueAt, item.AllDay)
+	if err := s.repo.Item.Update(ctx, item); err != nil {
+		return nil, fmt.Errorf("failed to update item: %w", err)
+	}
+	s.publishItemEvent(ctx, models.WebhookEventItemUpdated, item, userID)
+	return s.mapItemForUser(item, list, userID), nil
+}
+
+// DeleteDueDate removes the due date and any pending reminder of an item
+func (s *ItemService) DeleteDueDate(ctx context.Context, listID string, itemID string, version int32, userID string) (*models.ItemResponse, error) {
+	item, err := s.repo.Item.GetByID(ctx, listID, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+	if item == nil {
+		return nil, fmt.Errorf("item not found")
+	}
+	if item.Version != version {
+		log.Printf("[SERVICE_DELETE_DUE_DATE] Version conflict: itemID=%s, requested=%d, current=%d", itemID, version, item.Version)
+		return nil, fmt.Errorf("version_conflict")
+	}
+
+	list, err := s.rootList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if item.DueAt == nil {
+		return s.mapItemForUser(item, list, userID), nil
+	}
+
+	item.DueAt = nil
+	item.AllDay = false
+	item.ReminderOffset = nil
+	item.RemindAt = nil
+	item.UpdatedBy = userID
+
+	log.Printf("[SERVICE_DELETE_DUE_DATE] Removing due date: itemID=%s", itemID)
+	if err := s.repo.Item.Update(ctx, item); err != nil {
+		return nil, fmt.Errorf("failed to update item: %w", err)
+	}
+	s.publishItemEvent(ctx, models.WebhookEventItemUpdated, item, userID)
+	return s.mapItemForUser(item, list, userID), nil
+}
+
+// GetOverdueItems retrieves the open items whose due date has passed, oldest first
+// An empty listIDs searches every list
+func (s *ItemService) GetOverdueItems(ctx context.Context, listIDs []string, userID string) ([]models.DueItem, error) {
+	return s.getDueItems(ctx, listIDs, repository.DueFilter{Before: time.Now()}, true, userID)
+}
+
+// GetDueSoonItems retrieves the open items that come due within the given window, soonest first
+// An empty listIDs searches every list
+func (s *ItemService) GetDueSoonItems(ctx context.Context, listIDs []string, within time.Duration, userID string) ([]models.DueItem, error) {
+	if within <= 0 || within > maxDueSoonWindow {
+		return nil, fmt.Errorf("validation_error: the window must be between 1 hour and %d days", int(maxDueSoonWindow.Hours()/24))
+	}
+
+	now := time.Now()
+	return s.getDueItems(ctx, listIDs, repository.DueFilter{After: now, Before: now.Add(within)}, false, userID)
+}
+
+// getDueItems retrieves the items matching a due filter that are open for the caller
+func (s *ItemService) getDueItems(ctx context.Context, listIDs []string, filter repository.DueFilter, overdue bool, userID string) ([]models.DueItem, error) {
+	if len(listIDs) > 0 {
+		expanded, err := s.expandListIDs(ctx, listIDs)
+		if err != nil {
+			return nil, err
+		}
+		filter.ListIDs = expanded
+	}
+	filter.Limit = maxDueItems
+
+	items, err := s.repo.Item.GetDue(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due items: %w", err)
+	}
+
+	containers := map[string]*itemContainer{}
+	dueItems := []models.DueItem{}
+	for i := range items {
+		item := &items[i]
+		container, err := s.containerOf(ctx, item.ListID, containers)
+		if err != nil {
+			return nil, err
+		}
+		if container.list == nil || container.list.Archived || isCompletedBy(item, container.list, userID) {
+			continue
+		}
+
+		dueItems = append(dueItems, models.DueItem{
+			Item:     s.mapItemForUser(item, container.list, userID),
+			ListName: container.name,
+			Overdue:  overdue,
+		})
+	}
+	return dueItems, nil
+}
+
+// itemContainer is the list or nested list holding an item, with the top-level list that governs it
+type itemContainer struct {
+	list *models.List
+	name string
+}
+
+// containerOf resolves the container of a list ID, caching it for the rest of the operation
+func (s *ItemService) containerOf(ctx context.Context, listID string, cache map[string]*itemContainer) (*itemContainer, error) {
+	if container, ok := cache[listID]; ok {
+		return container, nil
+	}
+
+	list, err := s.rootList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	container := &itemContainer{list: list}
+	if list != nil {
+		container.name = list.Name
+		if list.UUID != listID {
+			nestedList, err := s.repo.Item.GetNestedList(ctx, listID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get list: %w", err)
+			}
+			if nestedList != nil {
+				container.name = nestedList.Name
+			}
+		}
+	}
+	cache[listID] = container
+	return container, nil
+}
+
+// expandListIDs adds the nested lists of the given top-level lists, failing if a list does not exist
+func (s *ItemService) expandListIDs(ctx context.Context, listIDs []string) ([]string, error) {
+	expanded := []string{}
+	for _, listID := range listIDs {
+		list, err := s.repo.List.GetByID(ctx, listID, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get list: %w", err)
+		}
+		if list == nil {
+			return nil, fmt.Errorf("list not found")
+		}
+		expanded = append(expanded, listID)
+
+		children, _, err := s.repo.Item.GetByListID(ctx, listID, models.ItemQuery{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get items: %w", err)
+		}
+		for _, child := range children {
+			if child.Type == "list" {
+				expanded = append(expanded, child.UUID)
+			}
+		}
+	}
+	return expanded, nil
+}
+
+// applyDueDate parses a due date in the caller's time zone and sets it on an item, scheduling its reminder
+func (s *ItemService) applyDueDate(ctx context.Context, item *models.Item, due string, reminderOffset *int32, userID string) error {
+	if reminderOffset != nil && (*reminderOffset < 0 || *reminderOffset > maxReminderOffset) {
+		return fmt.Errorf("validation_error: reminderOffset must be between 0 and %d minutes", maxReminderOffset)
+	}
+
+	loc, err := s.userLocation(ctx, userID)
+	if err != nil {
+		return err
+	}
+	dueAt, allDay, err := parseDue(due, loc)
+	if err != nil {
+		return err
+	}
+
+	item.DueAt = &dueAt
+	item.AllDay = allDay
+	item.ReminderOffset = reminderOffset
+	scheduleReminder(item, time.Now())
+	return nil
+}
+
+// userLocation returns the time zone from a user's preferences, defaulting to UTC
+func (s *ItemService) userLocation(ctx context.Context, userID string) (*time.Location, error) {
+	user, err := s.repo.User.GetByUsername(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return preferredLocation(user), nil
+}
+
+// preferredLocation returns the time zone of a user, defaulting to UTC for unknown users and invalid zones
+func preferredLocation(user *models.User) *time.Location {
+	if user == nil || user.Preferences.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(user.Preferences.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// parseDue parses an RFC 3339 due time, or a YYYY-MM-DD date that is due all day in the given location
+func parseDue(due string, loc *time.Location) (time.Time, bool, error) {
+	if day, err := time.ParseInLocation("2006-01-02", due, loc); err == nil {
+		return day.UTC(), true, nil
+	}
+	dueAt, err := time.Parse(time.RFC3339, due)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("validation_error: due must be an RFC 3339 time or a YYYY-MM-DD date")
+	}
+	return dueAt.UTC(), false, nil
+}
+
+// scheduleReminder sets when the reminder of an item is sent, clearing it if there is none or the item is no longer coming due
+// A reminder whose time has already passed is sent on the next run, as long as the item is not yet due
+func scheduleReminder(item *models.Item, now time.Time) {
+	item.RemindAt = nil
+	if item.DueAt == nil || item.ReminderOffset == nil || item.Completed {
+		return
+	}
+	if !dueDeadline(item).After(now) {
+		return
+	}
+
+	remindAt := item.DueAt.Add(-time.Duration(*item.ReminderOffset) * time.Minute)
+	item.RemindAt = &remindAt
+}
+
+// dueDeadline returns when an item becomes overdue: its due time, or the end of its due day
+func dueDeadline(item *models.Item) time.Time {
+	if item.AllDay {
+		return item.DueAt.Add(24 * time.Hour)
+	}
+	return *item.DueAt
+}
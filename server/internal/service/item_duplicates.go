@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/units"
+)
+
+// GetDuplicateItems groups the items of a list that share the same normalized name
+func (s *ItemService) GetDuplicateItems(ctx context.Context, listID string, foldUnicode bool, userID string) ([]models.DuplicateGroup, error) {
+	items, _, err := s.repo.Item.GetByListID(ctx, listID, models.ItemQuery{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items: %w", err)
+	}
+
+	list, err := s.rootList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	groups := map[string][]models.ItemResponse{}
+	for _, item := range items {
+		if item.Type != "item" {
+			continue
+		}
+		key := normalizeName(item.Name, foldUnicode)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], *s.mapItemForUser(&item, list, userID))
+	}
+
+	duplicates := []models.DuplicateGroup{}
+	for _, key := range keys {
+		if len(groups[key]) > 1 {
+			duplicates = append(duplicates, models.DuplicateGroup{Name: key, Items: groups[key]})
+		}
+	}
+
+	return duplicates, nil
+}
+
+// MergeItems merges duplicate items into the kept item, summing compatible quantities and deleting the rest
+func (s *ItemService) MergeItems(ctx context.Context, listID string, req *models.MergeItemsRequest, userID string) (*models.MergeItemsResponse, error) {
+	log.Printf("[SERVICE_MERGE_ITEMS] Merging items: listID=%s, keepID=%s, count=%d", listID, req.KeepID, len(req.Items))
+	if req.KeepID == "" {
+		return nil, fmt.Errorf("validation_error: keepId is required")
+	}
+	if len(req.Items) < 2 {
+		return nil, fmt.Errorf("validation_error: at least two items are required to merge")
+	}
+
+	var keep *models.Item
+	merged := []models.Item{}
+	seen := map[string]bool{}
+	for _, ref := range req.Items {
+		if seen[ref.ID] {
+			return nil, fmt.Errorf("validation_error: item %s is listed more than once", ref.ID)
+		}
+		seen[ref.ID] = true
+
+		item, err := s.repo.Item.GetByID(ctx, listID, ref.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get item: %w", err)
+		}
+		if item == nil {
+			return nil, fmt.Errorf("item not found")
+		}
+		if ref.Version != nil && *ref.Version != item.Version {
+			log.Printf("[SERVICE_MERGE_ITEMS] Version conflict: itemID=%s, requested=%d, current=%d", ref.ID, *ref.Version, item.Version)
+			return nil, fmt.Errorf("version_conflict")
+		}
+		if item.Type != "item" {
+			return nil, fmt.Errorf("validation_error: nested lists cannot be merged")
+		}
+
+		if item.UUID == req.KeepID {
+			keep = item
+		} else {
+			merged = append(merged, *item)
+		}
+	}
+
+	if keep == nil {
+		return nil, fmt.Errorf("validation_error: keepId must be one of the merged items")
+	}
+
+	if err := mergeQuantities(keep, merged); err != nil {
+		return nil, err
+	}
+	keep.UpdatedBy = userID
+
+	if err := s.repo.Item.Merge(ctx, keep, merged); err != nil {
+		log.Printf("[SERVICE_MERGE_ITEMS] Failed to merge items: keepID=%s, error=%v", req.KeepID, err)
+		return nil, fmt.Errorf("failed to merge items: %w", err)
+	}
+
+	deletedIDs := make([]string, len(merged))
+	for i := range merged {
+		deletedIDs[i] = merged[i].UUID
+		s.publishItemEvent(ctx, models.WebhookEventItemDeleted, &merged[i], userID)
+	}
+	s.publishItemEvent(ctx, models.WebhookEventItemUpdated, keep, userID)
+
+	list, err := s.rootList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[SERVICE_MERGE_ITEMS] Successfully merged items: keepID=%s, deleted=%v", req.KeepID, deletedIDs)
+	s.refreshItemCounts(ctx, listID)
+	return &models.MergeItemsResponse{
+		Data:       *s.mapItemForUser(keep, list, userID),
+		DeletedIDs: deletedIDs,
+	}, nil
+}
+
+// findDuplicates returns the existing items in the same list whose name matches the item's name
+func (s *ItemService) findDuplicates(ctx context.Context, item *models.Item, foldUnicode bool) ([]models.DuplicateItem, error) {
+	existingItems, _, err := s.repo.Item.GetByListID(ctx, item.ListID, models.ItemQuery{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items: %w", err)
+	}
+
+	key := normalizeName(item.Name, foldUnicode)
+	var duplicates []models.DuplicateItem
+	for _, existing := range existingItems {
+		if existing.Type != item.Type || existing.UUID == item.UUID {
+			continue
+		}
+		if normalizeName(existing.Name, foldUnicode) == key {
+			duplicates = append(duplicates, models.DuplicateItem{
+				ID:        existing.UUID,
+				Name:      existing.Name,
+				Completed: existing.Completed,
+				Version:   existing.Version,
+			})
+		}
+	}
+
+	if len(duplicates) > 0 {
+		log.Printf("[SERVICE_CREATE_ITEM] Found %d likely duplicates for name=%s in listID=%s", len(duplicates), item.Name, item.ListID)
+	}
+	return duplicates, nil
+}
+
+// mergeQuantities sums the quantities of the merged items into the kept item
+// Quantities are converted into the kept item's unit and must have compatible units
+func mergeQuantities(keep *models.Item, merged []models.Item) error {
+	for _, item := range merged {
+		if item.Quantity == nil {
+			continue
+		}
+		if keep.Quantity == nil {
+			quantity := *item.Quantity
+			keep.Quantity = &quantity
+			keep.QuantityType = item.QuantityType
+			continue
+		}
+
+		total, err := units.Add(*keep.Quantity, keep.QuantityType, *item.Quantity, item.QuantityType)
+		if err != nil {
+			return fmt.Errorf("validation_error: cannot merge quantities of %q and %q", keep.QuantityType, item.QuantityType)
+		}
+		keep.Quantity = &total
+	}
+	return nil
+}
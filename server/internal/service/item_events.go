@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yair12/lists-viewer/server/internal/models"
+)
+
+// SetWebhooks makes the service queue item changes for the webhooks of their lists
+// Without a webhook service no events are published
+func (s *ItemService) SetWebhooks(webhooks *WebhookService) {
+	s.webhooks = webhooks
+}
+
+// publishItemEvent queues an item change for the webhooks of its list
+// Failures are logged rather than returned, so a broken outbox never fails the change itself
+func (s *ItemService) publishItemEvent(ctx context.Context, eventType string, item *models.Item, actor string) {
+	if s.webhooks == nil {
+		return
+	}
+
+	container, err := s.containerOf(ctx, item.ListID, map[string]*itemContainer{})
+	if err != nil || container.list == nil {
+		return
+	}
+	s.publishListItemEvent(ctx, eventType, item, container.list, actor)
+}
+
+// publishListItemEvent queues an item change for the webhooks of the given top-level list
+// It is used where the list is already known, or no longer exists
+func (s *ItemService) publishListItemEvent(ctx context.Context, eventType string, item *models.Item, list *models.List, actor string) {
+	if s.webhooks == nil {
+		return
+	}
+
+	event := &models.WebhookEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC().Format(time.RFC3339),
+		Actor:      actor,
+		List:       models.WebhookEventList{ID: list.UUID, Name: list.Name},
+		Item:       s.mapItemToResponse(item),
+	}
+	if err := s.webhooks.Enqueue(ctx, list, event); err != nil {
+		log.Printf("[SERVICE_ITEM_EVENTS] Failed to queue %s event: itemID=%s, error=%v", eventType, item.UUID, err)
+	}
+}
+
+// publishItemChange queues the event describing an update, telling completion changes apart from other edits
+func (s *ItemService) publishItemChange(ctx context.Context, wasCompleted bool, item *models.Item, actor string) {
+	switch {
+	case item.Completed && !wasCompleted:
+		s.publishItemEvent(ctx, models.WebhookEventItemCompleted, item, actor)
+	case !item.Completed && wasCompleted:
+		s.publishItemEvent(ctx, models.WebhookEventItemUncompleted, item, actor)
+	default:
+		s.publishItemEvent(ctx, models.WebhookEventItemUpdated, item, actor)
+	}
+}
+
+// publishItemMove queues the events describing a move out of sourceListID
+// Within one top-level list a move is an update; across lists the source sees a deletion and the target a creation
+func (s *ItemService) publishItemMove(ctx context.Context, sourceListID string, item *models.Item, actor string) {
+	if s.webhooks == nil {
+		return
+	}
+
+	source, err := s.rootList(ctx, sourceListID)
+	if err != nil || source == nil {
+		return
+	}
+	target, err := s.rootList(ctx, item.ListID)
+	if err != nil || target == nil {
+		return
+	}
+
+	if source.UUID == target.UUID {
+		s.publishItemEvent(ctx, models.WebhookEventItemUpdated, item, actor)
+		return
+	}
+	removed := *item
+	removed.ListID = sourceListID
+	s.publishItemEvent(ctx, models.WebhookEventItemDeleted, &removed, actor)
+	s.publishItemEvent(ctx, models.WebhookEventItemCreated, item, actor)
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/notify"
+)
+
+// notifyTimeout bounds the delivery of the notifications of one change
+const notifyTimeout = 30 * time.Second
+
+// SetNotifier makes the service notify users of changes made by others
+// Without a notifier no notifications are sent
+func (s *ItemService) SetNotifier(notifier notify.Notifier) {
+	s.notifier = notifier
+}
+
+// notifyItemAdded tells the other users of a shared list that an item was added
+// A list is shared once it has members; its owner and members are notified
+func (s *ItemService) notifyItemAdded(ctx context.Context, item *models.Item, actor string) {
+	if s.notifier == nil {
+		return
+	}
+
+	container, err := s.containerOf(ctx, item.ListID, map[string]*itemContainer{})
+	if err != nil || container.list == nil || len(container.list.Members) == 0 {
+		return
+	}
+
+	recipients := []string{}
+	for _, userID := range append([]string{container.list.UserID}, container.list.Members...) {
+		if userID != "" && userID != actor && !slices.Contains(recipients, userID) {
+			recipients = append(recipients, userID)
+		}
+	}
+	s.dispatch(newItemNotification(notify.EventItemAdded, item, container.name, actor), recipients)
+}
+
+// dispatch delivers a notification to each recipient in the background, so slow push services do not delay requests
+func (s *ItemService) dispatch(notification notify.Notification, recipients []string) {
+	if len(recipients) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		defer cancel()
+		for _, userID := range recipients {
+			notification.UserID = userID
+			if err := s.notifier.Notify(ctx, notification); err != nil {
+				log.Printf("[SERVICE_NOTIFY] Failed to deliver notification: type=%s, itemID=%s, userID=%s, error=%v", notification.Type, notification.ItemID, userID, err)
+			}
+		}
+	}()
+}
+
+// newItemNotification builds a notification about a change to an item, without its recipient
+func newItemNotification(eventType string, item *models.Item, listName string, actor string) notify.Notification {
+	return notify.Notification{
+		Type:     eventType,
+		ListID:   item.ListID,
+		ListName: listName,
+		ItemID:   item.UUID,
+		ItemName: item.Name,
+		Actor:    actor,
+	}
+}
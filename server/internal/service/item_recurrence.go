@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/schedule"
+)
+
+// recurrenceActor is recorded as the last editor of items reopened by the recurrence job
+const recurrenceActor = "scheduler"
+
+// SetRecurrence makes an item recur, replacing any previous rule
+// An item that is already completed is scheduled to reopen at the rule's next occurrence after its completion
+func (s *ItemService) SetRecurrence(ctx context.Context, listID string, itemID string, req *models.SetRecurrenceRequest, userID string) (*models.ItemResponse, error) {
+	recurrence, err := newRecurrence(req)
+	if err != nil {
+		return nil, err
+	}
+
+	item, list, err := s.getRecurringItem(ctx, listID, itemID, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	item.Recurrence = recurrence
+	if item.Completed && item.CompletedAt != nil {
+		scheduleRecurrence(item, *item.CompletedAt)
+	}
+	item.UpdatedBy = userID
+
+	log.Printf("[SERVICE_SET_RECURRENCE] Setting recurrence: itemID=%s, rule=%s, timeZone=%s", itemID, recurrence.Rule, recurrence.TimeZone)
+	if err := s.repo.Item.Update(ctx, item); err != nil {
+		return nil, fmt.Errorf("failed to update item: %w", err)
+	}
+	s.publishItemEvent(ctx, models.WebhookEventItemUpdated, item, userID)
+	return s.mapItemForUser(item, list, userID), nil
+}
+
+// DeleteRecurrence stops an item from recurring
+func (s *ItemService) DeleteRecurrence(ctx context.Context, listID string, itemID string, version int32, userID string) (*models.ItemResponse, error) {
+	item, list, err := s.getRecurringItem(ctx, listID, itemID, version)
+	if err != nil {
+		return nil, err
+	}
+	if item.Recurrence == nil {
+		return s.mapItemForUser(item, list, userID), nil
+	}
+
+	item.Recurrence = nil
+	item.UpdatedBy = userID
+	log.Printf("[SERVICE_DELETE_RECURRENCE] Removing recurrence: itemID=%s", itemID)
+	if err := s.repo.Item.Update(ctx, item); err != nil {
+		return nil, fmt.Errorf("failed to update item: %w", err)
+	}
+	s.publishItemEvent(ctx, models.WebhookEventItemUpdated, item, userID)
+	return s.mapItemForUser(item, list, userID), nil
+}
+
+// ReopenRecurringItems reopens every completed recurring item whose next occurrence has come
+// Reopening only matches the pending occurrence, so repeated or concurrent runs reopen an item once per occurrence
+func (s *ItemService) ReopenRecurringItems(ctx context.Context, now time.Time) error {
+	items, err := s.repo.Item.GetDueRecurrences(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to get due recurrences: %w", err)
+	}
+
+	touched := []string{}
+	for i := range items {
+		item := &items[i]
+		// The due date moves to the reopened occurrence and its reminder is scheduled again
+		advanceDueDate(item, *item.Recurrence.NextAt)
+		item.Completed = false
+		scheduleReminder(item, now)
+
+		reopened, err := s.repo.Item.ReopenRecurring(ctx, item, recurrenceActor)
+		if err != nil {
+			log.Printf("[SERVICE_REOPEN_RECURRING] Failed to reopen item: itemID=%s, error=%v", item.UUID, err)
+			continue
+		}
+		if !reopened {
+			continue
+		}
+		markUncompleted(item, recurrenceActor)
+		s.publishItemEvent(ctx, models.WebhookEventItemUncompleted, item, recurrenceActor)
+		if !slices.Contains(touched, item.ListID) {
+			touched = append(touched, item.ListID)
+		}
+	}
+
+	if len(touched) > 0 {
+		log.Printf("[SERVICE_REOPEN_RECURRING] Reopened recurring items: lists=%v", touched)
+		s.refreshItemCounts(ctx, touched...)
+	}
+	return nil
+}
+
+// advanceDueDate moves the due date of a recurring item to the first occurrence of its rule on or after the given one
+// The time of day is kept; due dates already at or past the occurrence are left alone
+func advanceDueDate(item *models.Item, occurrence time.Time) {
+	if item.DueAt == nil || item.Recurrence == nil {
+		return
+	}
+	rule, err := schedule.ParseRule(item.Recurrence.Rule)
+	if err != nil {
+		return
+	}
+	rule.AnchorDay = item.Recurrence.AnchorDay
+	loc, err := time.LoadLocation(item.Recurrence.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	due := item.DueAt.In(loc)
+	occurrence = occurrence.In(loc)
+	target := time.Date(occurrence.Year(), occurrence.Month(), occurrence.Day(), 0, 0, 0, 0, loc)
+	day := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, loc)
+	for day.Before(target) {
+		if day = rule.Next(day); day.IsZero() {
+			return
+		}
+	}
+
+	dueAt := time.Date(day.Year(), day.Month(), day.Day(), due.Hour(), due.Minute(), due.Second(), 0, loc).UTC()
+	item.DueAt = &dueAt
+}
+
+// markUncompleted applies the changes of a completion reset to an item loaded before it, for the events describing it
+func markUncompleted(item *models.Item, updatedBy string) {
+	item.Completed = false
+	item.CompletedAt = nil
+	item.CompletedBy = ""
+	item.CompletedByUsers = nil
+	item.FulfilledQuantity = nil
+	if item.Recurrence != nil {
+		item.Recurrence.NextAt = nil
+	}
+	item.UpdatedAt = time.Now()
+	item.UpdatedBy = updatedBy
+	item.Version++
+}
+
+// getRecurringItem loads a plain item for a recurrence change, checking its version
+func (s *ItemService) getRecurringItem(ctx context.Context, listID string, itemID string, version int32) (*models.Item, *models.List, error) {
+	item, err := s.repo.Item.GetByID(ctx, listID, itemID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get item: %w", err)
+	}
+	if item == nil {
+		return nil, nil, fmt.Errorf("item not found")
+	}
+	if item.Type != "item" {
+		return nil, nil, fmt.Errorf("validation_error: nested lists cannot recur")
+	}
+	if item.Version != version {
+		log.Printf("[SERVICE_RECURRENCE] Version conflict: itemID=%s, requested=%d, current=%d", itemID, version, item.Version)
+		return nil, nil, fmt.Errorf("version_conflict")
+	}
+
+	list, err := s.rootList(ctx, listID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return item, list, nil
+}
+
+// newRecurrence validates a recurrence request, storing the rule in canonical form
+func newRecurrence(req *models.SetRecurrenceRequest) (*models.Recurrence, error) {
+	recurrence := &models.Recurrence{Rule: req.Rule, TimeZone: req.TimeZone}
+	if err := validateRecurrence(recurrence); err != nil {
+		return nil, err
+	}
+	return recurrence, nil
+}
+
+// validateRecurrence checks a recurrence rule and time zone, putting them in canonical form; the time zone defaults to UTC
+// Monthly rules without a day keep the day they were set on, rather than following each completion, so an anchor day
+// already set is kept and a missing one is today
+func validateRecurrence(recurrence *models.Recurrence) error {
+	rule, err := schedule.ParseRule(recurrence.Rule)
+	if err != nil {
+		return fmt.Errorf("validation_error: %v", err)
+	}
+	if rule.Next(time.Now()).IsZero() {
+		return fmt.Errorf("validation_error: the recurrence rule never repeats")
+	}
+	if recurrence.AnchorDay < 0 || recurrence.AnchorDay > 31 {
+		return fmt.Errorf("validation_error: anchorDay must be between 1 and 31")
+	}
+
+	if recurrence.TimeZone == "" {
+		recurrence.TimeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(recurrence.TimeZone)
+	if err != nil {
+		return fmt.Errorf("validation_error: unknown time zone: %s", recurrence.TimeZone)
+	}
+
+	recurrence.Rule = rule.String()
+	if rule.Freq == schedule.FreqMonthly && rule.ByMonthDay == 0 && recurrence.AnchorDay == 0 {
+		recurrence.AnchorDay = time.Now().In(loc).Day()
+	}
+	return nil
+}
+
+// scheduleRecurrence sets when a recurring item completed at the given time reopens
+func scheduleRecurrence(item *models.Item, completedAt time.Time) {
+	if item.Recurrence == nil {
+		return
+	}
+
+	rule, err := schedule.ParseRule(item.Recurrence.Rule)
+	if err != nil {
+		log.Printf("[SERVICE_RECURRENCE] Invalid stored rule: itemID=%s, rule=%s, error=%v", item.UUID, item.Recurrence.Rule, err)
+		return
+	}
+	loc, err := time.LoadLocation(item.Recurrence.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	rule.AnchorDay = item.Recurrence.AnchorDay
+
+	next := rule.Next(completedAt.In(loc))
+	if next.IsZero() {
+		item.Recurrence.NextAt = nil
+		return
+	}
+	next = next.UTC()
+	item.Recurrence.NextAt = &next
+}
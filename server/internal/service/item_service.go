@@ -2,17 +2,35 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/notify"
+	"github.com/yair12/lists-viewer/server/internal/quickadd"
 	"github.com/yair12/lists-viewer/server/internal/repository"
+	"github.com/yair12/lists-viewer/server/internal/units"
+)
+
+const (
+	// maxItemPageSize is the largest page of items returned at once
+	maxItemPageSize = 500
+
+	// maxNestingDepth is the deepest a list can be: top-level lists (1) contain nested lists (2), which contain items
+	maxNestingDepth = 2
 )
 
 // ItemService handles business logic for items
 type ItemService struct {
-	repo *repository.Repositories
+	repo     *repository.Repositories
+	notifier notify.Notifier
+	webhooks *WebhookService
 }
 
 // NewItemService creates a new item service
@@ -22,37 +40,71 @@ func NewItemService(repo *repository.Repositories) *ItemService {
 
 // CreateItem creates a new item
 func (s *ItemService) CreateItem(ctx context.Context, listID string, req *models.CreateItemRequest, userID string) (*models.ItemResponse, error) {
+	if req.Type != "item" && req.Type != "list" {
+		return nil, fmt.Errorf("validation_error: type must be item or list")
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, fmt.Errorf("validation_error: name is required")
+	}
+
+	depth, err := s.listDepth(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if depth == 0 {
+		return nil, fmt.Errorf("list not found")
+	}
+	if depth > maxNestingDepth {
+		return nil, fmt.Errorf("validation_error: lists cannot be nested more than %d levels deep", maxNestingDepth)
+	}
+	if req.Type == "list" && depth >= maxNestingDepth {
+		return nil, fmt.Errorf("validation_error: nested lists cannot contain other lists")
+	}
+	list, err := s.rootList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
 	item := &models.Item{
-		UUID:       uuid.New().String(),
-		ListID:     listID,
-		Type:       req.Type,
-		Name:       req.Name,
-		CreatedBy:  userID,
-		UpdatedBy:  userID,
-		UserIconID: req.UserIconID,
+		UUID:        uuid.New().String(),
+		ListID:      listID,
+		Type:        req.Type,
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+		UserIconID:  req.UserIconID,
 	}
 
 	if req.Type == "item" {
 		item.Completed = false
 		item.Quantity = req.Quantity
-		item.QuantityType = req.QuantityType
-	} else if req.Type == "list" {
-		item.Description = req.Description
+		item.QuantityType = units.Normalize(req.QuantityType)
+		if req.Recurrence != nil {
+			if item.Recurrence, err = newRecurrence(req.Recurrence); err != nil {
+				return nil, err
+			}
+		}
+		if req.Due != "" {
+			if err := s.applyDueDate(ctx, item, req.Due, req.ReminderOffset, userID); err != nil {
+				return nil, err
+			}
+		} else if req.ReminderOffset != nil {
+			return nil, fmt.Errorf("validation_error: a reminder requires a due date")
+		}
 	}
 
 	// Get next order value
-	existingItems, err := s.repo.Item.GetByListID(ctx, listID, true)
+	order, err := s.nextOrder(ctx, listID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get items: %w", err)
+		return nil, err
 	}
+	item.Order = order
 
-	var maxOrder int32 = 0
-	for _, existing := range existingItems {
-		if existing.Order > maxOrder {
-			maxOrder = existing.Order
-		}
+	duplicates, err := s.findDuplicates(ctx, item, req.FoldUnicode)
+	if err != nil {
+		return nil, err
 	}
-	item.Order = maxOrder + 1
 
 	log.Printf("[SERVICE_CREATE_ITEM] Creating item: uuid=%s, listID=%s, name=%s, type=%s, order=%d", item.UUID, listID, item.Name, item.Type, item.Order)
 	if err := s.repo.Item.Create(ctx, item); err != nil {
@@ -61,11 +113,45 @@ func (s *ItemService) CreateItem(ctx context.Context, listID string, req *models
 	}
 
 	log.Printf("[SERVICE_CREATE_ITEM] Successfully created item: uuid=%s", item.UUID)
-	return s.mapItemToResponse(item), nil
+	s.refreshItemCounts(ctx, listID)
+	s.notifyItemAdded(ctx, item, userID)
+	s.publishItemEvent(ctx, models.WebhookEventItemCreated, item, userID)
+	response := s.mapItemForUser(item, list, userID)
+	response.Duplicates = duplicates
+	return response, nil
+}
+
+// QuickAddItem parses free text such as "2kg tomatoes" or "tomatoes x3" and creates the resulting item
+// With Preview set, only the parsed result is returned
+func (s *ItemService) QuickAddItem(ctx context.Context, listID string, req *models.QuickAddItemRequest, userID string) (*models.QuickAddItemResponse, error) {
+	parsed, err := quickadd.Parse(req.Text)
+	if err != nil {
+		return nil, fmt.Errorf("validation_error: %v", err)
+	}
+
+	response := &models.QuickAddItemResponse{Parsed: parsed}
+	if req.Preview {
+		return response, nil
+	}
+
+	item, err := s.CreateItem(ctx, listID, &models.CreateItemRequest{
+		Type:         "item",
+		Name:         parsed.Name,
+		Quantity:     parsed.Quantity,
+		QuantityType: parsed.QuantityType,
+		UserIconID:   req.UserIconID,
+		FoldUnicode:  req.FoldUnicode,
+	}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Item = item
+	return response, nil
 }
 
 // GetItem retrieves an item by ID
-func (s *ItemService) GetItem(ctx context.Context, listID string, itemID string) (*models.ItemResponse, error) {
+func (s *ItemService) GetItem(ctx context.Context, listID string, itemID string, userID string) (*models.ItemResponse, error) {
 	item, err := s.repo.Item.GetByID(ctx, listID, itemID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get item: %w", err)
@@ -75,24 +161,79 @@ func (s *ItemService) GetItem(ctx context.Context, listID string, itemID string)
 		return nil, fmt.Errorf("item not found")
 	}
 
-	return s.mapItemToResponse(item), nil
+	list, err := s.rootList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapItemForUser(item, list, userID), nil
 }
 
-// GetItemsByList retrieves all items in a list
-func (s *ItemService) GetItemsByList(ctx context.Context, listID string, includeArchived bool) ([]models.ItemResponse, error) {
-	items, err := s.repo.Item.GetByListID(ctx, listID, includeArchived)
+// GetRecentlyCompletedItems retrieves items in a list completed within the last given number of days
+func (s *ItemService) GetRecentlyCompletedItems(ctx context.Context, listID string, days int, userID string) ([]models.ItemResponse, error) {
+	if days < 1 || days > 365 {
+		return nil, fmt.Errorf("validation_error: days must be between 1 and 365")
+	}
+
+	list, err := s.rootList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	items, err := s.repo.Item.GetCompletedSince(ctx, listID, since)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get items: %w", err)
 	}
 
 	responses := make([]models.ItemResponse, len(items))
 	for i, item := range items {
-		responses[i] = *s.mapItemToResponse(&item)
+		responses[i] = *s.mapItemForUser(&item, list, userID)
 	}
 
 	return responses, nil
 }
 
+// GetItemsByList retrieves the items in a list, filtered, sorted and paged by the query
+// The returned cursor fetches the next page and is empty on the last page
+func (s *ItemService) GetItemsByList(ctx context.Context, listID string, query models.ItemQuery, userID string) ([]models.ItemResponse, string, error) {
+	if query.Type != "" && query.Type != "item" && query.Type != "list" {
+		return nil, "", fmt.Errorf("validation_error: type must be item or list")
+	}
+	if query.Limit < 0 || query.Limit > maxItemPageSize {
+		return nil, "", fmt.Errorf("validation_error: limit must be between 1 and %d", maxItemPageSize)
+	}
+
+	list, err := s.rootList(ctx, listID)
+	if err != nil {
+		return nil, "", err
+	}
+	if list == nil {
+		return nil, "", fmt.Errorf("list not found")
+	}
+
+	// In per_member lists the completed filter applies to the caller's own state
+	query.CompletedBy = ""
+	if isPerMember(list) {
+		query.CompletedBy = userID
+	}
+
+	items, next, err := s.repo.Item.GetByListID(ctx, listID, query)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidSort) || errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, "", fmt.Errorf("validation_error: %v", err)
+		}
+		return nil, "", fmt.Errorf("failed to get items: %w", err)
+	}
+
+	responses := make([]models.ItemResponse, len(items))
+	for i, item := range items {
+		responses[i] = *s.mapItemForUser(&item, list, userID)
+	}
+
+	return responses, next, nil
+}
+
 // UpdateItem updates an item
 func (s *ItemService) UpdateItem(ctx context.Context, listID string, itemID string, req *models.UpdateItemRequest, userID string) (*models.ItemResponse, error) {
 	log.Printf("[SERVICE_UPDATE_ITEM] Updating item: itemID=%s, listID=%s, version=%d", itemID, listID, req.Version)
@@ -114,20 +255,29 @@ func (s *ItemService) UpdateItem(ctx context.Context, listID string, itemID stri
 		return nil, fmt.Errorf("version_conflict")
 	}
 
+	list, err := s.rootList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Update fields
+	wasCompleted := existingItem.Completed
 	existingItem.Name = req.Name
 	existingItem.Order = req.Order
 	existingItem.UpdatedBy = userID
 
+	if req.Completed != nil && *req.Completed && existingItem.Type == "list" {
+		return nil, fmt.Errorf("validation_error: nested lists cannot be completed")
+	}
 	if req.Completed != nil && existingItem.Type == "item" {
-		existingItem.Completed = *req.Completed
+		setCompletedBy(existingItem, list, *req.Completed, userID)
 	}
 
+	existingItem.Description = req.Description
 	if existingItem.Type == "item" {
 		existingItem.Quantity = req.Quantity
-		existingItem.QuantityType = req.QuantityType
-	} else {
-		existingItem.Description = req.Description
+		existingItem.QuantityType = units.Normalize(req.QuantityType)
+		fitFulfilledQuantity(existingItem, list, req.Completed == nil, userID)
 	}
 
 	if err := s.repo.Item.Update(ctx, existingItem); err != nil {
@@ -136,31 +286,172 @@ func (s *ItemService) UpdateItem(ctx context.Context, listID string, itemID stri
 	}
 
 	log.Printf("[SERVICE_UPDATE_ITEM] Successfully updated item: itemID=%s, new_version=%d", itemID, existingItem.Version)
-	return s.mapItemToResponse(existingItem), nil
+	s.refreshItemCounts(ctx, listID)
+	s.publishItemChange(ctx, wasCompleted, existingItem, userID)
+	return s.mapItemForUser(existingItem, list, userID), nil
+}
+
+// FulfillItem records a partially bought quantity of an item
+// The item is completed automatically once the fulfilled quantity reaches the requested quantity
+func (s *ItemService) FulfillItem(ctx context.Context, listID string, itemID string, req *models.FulfillItemRequest, userID string) (*models.ItemResponse, error) {
+	log.Printf("[SERVICE_FULFILL_ITEM] Fulfilling item: itemID=%s, listID=%s, quantity=%v, version=%d", itemID, listID, req.Quantity, req.Version)
+	if req.Quantity <= 0 {
+		return nil, fmt.Errorf("validation_error: quantity must be greater than 0")
+	}
+
+	item, err := s.repo.Item.GetByID(ctx, listID, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+
+	if item == nil {
+		return nil, fmt.Errorf("item not found")
+	}
+
+	if item.Version != req.Version {
+		log.Printf("[SERVICE_FULFILL_ITEM] Version conflict: itemID=%s, requested=%d, current=%d", itemID, req.Version, item.Version)
+		return nil, fmt.Errorf("version_conflict")
+	}
+
+	if item.Type != "item" || item.Quantity == nil {
+		return nil, fmt.Errorf("validation_error: only items with a quantity can be partially fulfilled")
+	}
+
+	list, err := s.rootList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	if isCompletedBy(item, list, userID) {
+		return nil, fmt.Errorf("validation_error: item is already completed")
+	}
+
+	wasCompleted := item.Completed
+	fulfilled := req.Quantity
+	if item.FulfilledQuantity != nil {
+		fulfilled += *item.FulfilledQuantity
+	}
+	item.FulfilledQuantity = &fulfilled
+	item.UpdatedBy = userID
+
+	if fulfilled >= *item.Quantity {
+		setCompletedBy(item, list, true, userID)
+	}
+
+	if err := s.repo.Item.Update(ctx, item); err != nil {
+		log.Printf("[SERVICE_FULFILL_ITEM] Failed to update item: itemID=%s, error=%v", itemID, err)
+		return nil, fmt.Errorf("failed to fulfill item: %w", err)
+	}
+
+	log.Printf("[SERVICE_FULFILL_ITEM] Fulfilled item: itemID=%s, fulfilled=%v/%v, completed=%t", itemID, fulfilled, *item.Quantity, item.Completed)
+	s.refreshItemCounts(ctx, listID)
+	s.publishItemChange(ctx, wasCompleted, item, userID)
+	return s.mapItemForUser(item, list, userID), nil
+}
+
+// ConvertItem converts a plain item into a nested list or an empty nested list into a plain item, keeping its UUID and order
+// A quantity becomes the nested list's description and the description stays with the item converted back;
+// completion state and bought amounts are dropped since nested lists cannot be completed
+func (s *ItemService) ConvertItem(ctx context.Context, listID string, itemID string, req *models.ConvertItemRequest, userID string) (*models.ItemResponse, error) {
+	log.Printf("[SERVICE_CONVERT_ITEM] Converting item: itemID=%s, listID=%s, type=%s, version=%d", itemID, listID, req.Type, req.Version)
+	if req.Type != "item" && req.Type != "list" {
+		return nil, fmt.Errorf("validation_error: type must be item or list")
+	}
+
+	item, err := s.repo.Item.GetByID(ctx, listID, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+	if item == nil {
+		return nil, fmt.Errorf("item not found")
+	}
+	if item.Version != req.Version {
+		log.Printf("[SERVICE_CONVERT_ITEM] Version conflict: itemID=%s, requested=%d, current=%d", itemID, req.Version, item.Version)
+		return nil, fmt.Errorf("version_conflict")
+	}
+
+	list, err := s.rootList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if item.Type == req.Type {
+		return s.mapItemForUser(item, list, userID), nil
+	}
+
+	if req.Type == "list" {
+		depth, err := s.listDepth(ctx, listID)
+		if err != nil {
+			return nil, err
+		}
+		if depth >= maxNestingDepth {
+			return nil, fmt.Errorf("validation_error: nested lists cannot contain other lists")
+		}
+
+		if item.Description == "" && item.Quantity != nil {
+			item.Description = strings.TrimSpace(strconv.FormatFloat(*item.Quantity, 'f', -1, 64) + " " + item.QuantityType)
+		}
+		setCompleted(item, false, userID)
+		item.CompletedByUsers = nil
+		item.Recurrence = nil
+		item.Quantity = nil
+		item.QuantityType = ""
+		item.FulfilledQuantity = nil
+	} else {
+		children, _, err := s.repo.Item.GetByListID(ctx, item.UUID, models.ItemQuery{IncludeArchived: true, Limit: 1})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get items: %w", err)
+		}
+		if len(children) > 0 {
+			return nil, fmt.Errorf("validation_error: only empty nested lists can be converted to items")
+		}
+	}
+
+	item.Type = req.Type
+	item.UpdatedBy = userID
+	if err := s.repo.Item.Update(ctx, item); err != nil {
+		log.Printf("[SERVICE_CONVERT_ITEM] Failed to update item: itemID=%s, error=%v", itemID, err)
+		return nil, fmt.Errorf("failed to convert item: %w", err)
+	}
+
+	log.Printf("[SERVICE_CONVERT_ITEM] Converted item: itemID=%s, type=%s, new_version=%d", itemID, item.Type, item.Version)
+	s.refreshItemCounts(ctx, item.UUID, listID)
+	s.publishItemEvent(ctx, models.WebhookEventItemUpdated, item, userID)
+	return s.mapItemForUser(item, list, userID), nil
 }
 
 // DeleteItem deletes an item
 func (s *ItemService) DeleteItem(ctx context.Context, listID string, itemID string, userID string, version int32) error {
 	log.Printf("[SERVICE_DELETE_ITEM] Deleting item: itemID=%s, listID=%s, version=%d", itemID, listID, version)
+	item, err := s.repo.Item.GetByID(ctx, listID, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to get item: %w", err)
+	}
+
 	if err := s.repo.Item.Delete(ctx, listID, itemID, userID, version); err != nil {
 		log.Printf("[SERVICE_DELETE_ITEM] Failed to delete item: itemID=%s, error=%v", itemID, err)
 		return fmt.Errorf("failed to delete item: %w", err)
 	}
 
 	log.Printf("[SERVICE_DELETE_ITEM] Successfully deleted item: itemID=%s", itemID)
+	s.refreshItemCounts(ctx, listID)
+	if item != nil {
+		s.publishItemEvent(ctx, models.WebhookEventItemDeleted, item, userID)
+	}
 	return nil
 }
 
 // DeleteCompletedItems deletes all completed items in a list
-func (s *ItemService) DeleteCompletedItems(ctx context.Context, listID string) (int32, error) {
-	items, err := s.repo.Item.GetByListID(ctx, listID, false)
+func (s *ItemService) DeleteCompletedItems(ctx context.Context, listID string, userID string) (int32, error) {
+	items, _, err := s.repo.Item.GetByListID(ctx, listID, models.ItemQuery{})
 	if err != nil {
 		return 0, fmt.Errorf("failed to get items: %w", err)
 	}
 
+	completed := []models.Item{}
 	completedIDs := []string{}
 	for _, item := range items {
 		if item.Type == "item" && item.Completed {
+			completed = append(completed, item)
 			completedIDs = append(completedIDs, item.UUID)
 		}
 	}
@@ -173,31 +464,184 @@ func (s *ItemService) DeleteCompletedItems(ctx context.Context, listID string) (
 		return 0, fmt.Errorf("failed to delete items: %w", err)
 	}
 
+	s.refreshItemCounts(ctx, listID)
+	for i := range completed {
+		s.publishItemEvent(ctx, models.WebhookEventItemDeleted, &completed[i], userID)
+	}
 	return int32(len(completedIDs)), nil
 }
 
-// BulkCompleteItems completes multiple items
-func (s *ItemService) BulkCompleteItems(ctx context.Context, listID string, itemIDs []string, userID string) ([]models.ItemResponse, error) {
-	items, err := s.repo.Item.BulkComplete(ctx, listID, itemIDs, userID)
+// BulkCompleteItems completes multiple items and reports the outcome for each one
+// Items that are already completed are reported as done without bumping their version
+func (s *ItemService) BulkCompleteItems(ctx context.Context, listID string, req *models.BulkCompleteRequest, userID string) ([]models.BulkItemResult, error) {
+	refs := bulkItemRefs(req.ItemIDs, req.Items)
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("validation_error: itemIds or items is required")
+	}
+
+	list, err := s.rootList(ctx, listID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to complete items: %w", err)
+		return nil, err
 	}
 
-	responses := make([]models.ItemResponse, len(items))
-	for i, item := range items {
-		responses[i] = *s.mapItemToResponse(&item)
+	// In per_member lists only the caller's completion state changes
+	results := make([]models.BulkItemResult, 0, len(refs))
+	touched := []string{listID}
+	for _, ref := range refs {
+		nestedList, err := s.repo.Item.GetByID(ctx, listID, ref.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get item: %w", err)
+		}
+		if nestedList != nil && nestedList.Type == "list" {
+			result, err := s.completeNestedList(ctx, list, nestedList, ref, userID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to complete items: %w", err)
+			}
+			results = append(results, result)
+			touched = append(touched, nestedList.UUID)
+			continue
+		}
+
+		result, err := s.applyBulkUpdate(ctx, list, listID, ref, userID, completeForUser(list, userID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to complete items: %w", err)
+		}
+		results = append(results, result)
 	}
 
-	return responses, nil
+	s.refreshItemCounts(ctx, touched...)
+	return results, nil
 }
 
-// BulkDeleteItems deletes multiple items
-func (s *ItemService) BulkDeleteItems(ctx context.Context, listID string, itemIDs []string) (int32, error) {
-	if err := s.repo.Item.BulkDelete(ctx, listID, itemIDs); err != nil {
-		return 0, fmt.Errorf("failed to delete items: %w", err)
+// completeNestedList completes every item of a nested list for a bulk completion
+// Items changed concurrently are left as they are; the nested list itself has no completion state
+func (s *ItemService) completeNestedList(ctx context.Context, list *models.List, nestedList *models.Item, ref models.BulkItemRef, userID string) (models.BulkItemResult, error) {
+	if ref.Version != nil && *ref.Version != nestedList.Version {
+		log.Printf("[SERVICE_BULK_COMPLETE] Version conflict: itemID=%s, requested=%d, current=%d", ref.ID, *ref.Version, nestedList.Version)
+		return models.BulkItemResult{ID: ref.ID, Status: models.BulkStatusVersionConflict, Item: s.mapItemForUser(nestedList, list, userID)}, nil
+	}
+
+	items, _, err := s.repo.Item.GetByListID(ctx, nestedList.UUID, models.ItemQuery{Type: "item"})
+	if err != nil {
+		return models.BulkItemResult{}, fmt.Errorf("failed to get items: %w", err)
+	}
+	for _, item := range items {
+		if _, err := s.applyBulkUpdate(ctx, list, nestedList.UUID, models.BulkItemRef{ID: item.UUID}, userID, completeForUser(list, userID)); err != nil {
+			return models.BulkItemResult{}, err
+		}
+	}
+	return models.BulkItemResult{ID: ref.ID, Status: models.BulkStatusDone, Item: s.mapItemForUser(nestedList, list, userID)}, nil
+}
+
+// BulkDeleteItems deletes multiple items and reports the outcome for each one
+func (s *ItemService) BulkDeleteItems(ctx context.Context, listID string, req *models.BulkDeleteRequest, userID string) ([]models.BulkItemResult, error) {
+	refs := bulkItemRefs(req.ItemIDs, req.Items)
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("validation_error: itemIds or items is required")
+	}
+
+	results := make([]models.BulkItemResult, 0, len(refs))
+	for _, ref := range refs {
+		item, err := s.repo.Item.GetByID(ctx, listID, ref.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get item: %w", err)
+		}
+		if item == nil {
+			results = append(results, models.BulkItemResult{ID: ref.ID, Status: models.BulkStatusNotFound})
+			continue
+		}
+
+		version := item.Version
+		if ref.Version != nil {
+			version = *ref.Version
+		}
+
+		if err := s.repo.Item.Delete(ctx, listID, ref.ID, userID, version); err != nil {
+			if !strings.Contains(err.Error(), "version_conflict") {
+				return nil, fmt.Errorf("failed to delete items: %w", err)
+			}
+			result, err := s.bulkConflictResult(ctx, nil, listID, ref.ID, userID)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+			continue
+		}
+
+		results = append(results, models.BulkItemResult{ID: ref.ID, Status: models.BulkStatusDone})
+		s.publishItemEvent(ctx, models.WebhookEventItemDeleted, item, userID)
+	}
+
+	log.Printf("[SERVICE_BULK_DELETE] Processed %d items in listID=%s", len(results), listID)
+	s.refreshItemCounts(ctx, listID)
+	return results, nil
+}
+
+// applyBulkUpdate applies a change to a single item of a bulk operation with optimistic locking.
+// mutate returns false when the item already has the requested state, in which case nothing is written.
+func (s *ItemService) applyBulkUpdate(ctx context.Context, list *models.List, listID string, ref models.BulkItemRef, userID string, mutate func(item *models.Item) bool) (models.BulkItemResult, error) {
+	item, err := s.repo.Item.GetByID(ctx, listID, ref.ID)
+	if err != nil {
+		return models.BulkItemResult{}, fmt.Errorf("failed to get item: %w", err)
 	}
 
-	return int32(len(itemIDs)), nil
+	// Nested lists have no state of their own; callers that support them handle them before this
+	if item == nil || item.Type != "item" {
+		return models.BulkItemResult{ID: ref.ID, Status: models.BulkStatusNotFound}, nil
+	}
+
+	if ref.Version != nil && *ref.Version != item.Version {
+		log.Printf("[SERVICE_BULK_UPDATE] Version conflict: itemID=%s, requested=%d, current=%d", ref.ID, *ref.Version, item.Version)
+		return models.BulkItemResult{ID: ref.ID, Status: models.BulkStatusVersionConflict, Item: s.mapItemForUser(item, list, userID)}, nil
+	}
+
+	wasCompleted := item.Completed
+	if !mutate(item) {
+		return models.BulkItemResult{ID: ref.ID, Status: models.BulkStatusDone, Item: s.mapItemForUser(item, list, userID)}, nil
+	}
+
+	item.UpdatedBy = userID
+	if err := s.repo.Item.Update(ctx, item); err != nil {
+		if strings.Contains(err.Error(), "version_conflict") {
+			return s.bulkConflictResult(ctx, list, listID, ref.ID, userID)
+		}
+		return models.BulkItemResult{}, err
+	}
+
+	s.publishItemChange(ctx, wasCompleted, item, userID)
+	return models.BulkItemResult{ID: ref.ID, Status: models.BulkStatusDone, Item: s.mapItemForUser(item, list, userID)}, nil
+}
+
+// bulkConflictResult builds the result for an item that changed concurrently during a bulk operation
+func (s *ItemService) bulkConflictResult(ctx context.Context, list *models.List, listID string, itemID string, userID string) (models.BulkItemResult, error) {
+	current, err := s.repo.Item.GetByID(ctx, listID, itemID)
+	if err != nil {
+		return models.BulkItemResult{}, fmt.Errorf("failed to get item: %w", err)
+	}
+	if current == nil {
+		return models.BulkItemResult{ID: itemID, Status: models.BulkStatusNotFound}, nil
+	}
+	return models.BulkItemResult{ID: itemID, Status: models.BulkStatusVersionConflict, Item: s.mapItemForUser(current, list, userID)}, nil
+}
+
+// completeForUser returns a bulk mutation that completes an item from the caller's point of view
+func completeForUser(list *models.List, userID string) func(item *models.Item) bool {
+	return func(item *models.Item) bool {
+		if isCompletedBy(item, list, userID) {
+			return false
+		}
+		setCompletedBy(item, list, true, userID)
+		return true
+	}
+}
+
+// bulkItemRefs merges plain item IDs and versioned item references into a single list
+func bulkItemRefs(itemIDs []string, items []models.BulkItemRef) []models.BulkItemRef {
+	refs := make([]models.BulkItemRef, 0, len(itemIDs)+len(items))
+	for _, id := range itemIDs {
+		refs = append(refs, models.BulkItemRef{ID: id})
+	}
+	return append(refs, items...)
 }
 
 // ReorderItems updates the order of items
@@ -218,13 +662,291 @@ func (s *ItemService) ReorderItems(ctx context.Context, listID string, reorderRe
 }
 
 // MoveItem moves an item to a different list
-func (s *ItemService) MoveItem(ctx context.Context, sourceListID string, itemID string, targetListID string, newOrder int32, userID string) (*models.ItemResponse, error) {
-	movedItem, err := s.repo.Item.Move(ctx, sourceListID, targetListID, itemID, newOrder)
+func (s *ItemService) MoveItem(ctx context.Context, sourceListID string, itemID string, targetListID string, newOrder int32, version int32, userID string) (*models.ItemResponse, error) {
+	if err := s.validateMoveTarget(ctx, sourceListID, targetListID, itemID); err != nil {
+		return nil, err
+	}
+
+	targetList, err := s.rootList(ctx, targetListID)
+	if err != nil {
+		return nil, err
+	}
+
+	movedItem, err := s.repo.Item.Move(ctx, sourceListID, targetListID, itemID, newOrder, version, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to move item: %w", err)
 	}
 
-	return s.mapItemToResponse(movedItem), nil
+	s.refreshItemCounts(ctx, sourceListID, targetListID)
+	s.publishItemMove(ctx, sourceListID, movedItem, userID)
+	return s.mapItemForUser(movedItem, targetList, userID), nil
+}
+
+// BulkUncompleteItems returns multiple completed items to open and reports the outcome for each one
+func (s *ItemService) BulkUncompleteItems(ctx context.Context, listID string, req *models.BulkUncompleteRequest, userID string) ([]models.BulkItemResult, error) {
+	refs := bulkItemRefs(req.ItemIDs, req.Items)
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("validation_error: itemIds or items is required")
+	}
+
+	list, err := s.rootList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	// In per_member lists only the caller's completion state changes
+	results := make([]models.BulkItemResult, 0, len(refs))
+	for _, ref := range refs {
+		result, err := s.applyBulkUpdate(ctx, list, listID, ref, userID, func(item *models.Item) bool {
+			if !isCompletedBy(item, list, userID) {
+				return false
+			}
+			setCompletedBy(item, list, false, userID)
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to uncomplete items: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	s.refreshItemCounts(ctx, listID)
+	return results, nil
+}
+
+// BulkMoveItems moves multiple items to the end of another list and reports the outcome for each one
+func (s *ItemService) BulkMoveItems(ctx context.Context, sourceListID string, req *models.BulkMoveRequest, userID string) ([]models.BulkItemResult, error) {
+	refs := bulkItemRefs(req.ItemIDs, req.Items)
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("validation_error: itemIds or items is required")
+	}
+	if req.TargetListID == sourceListID {
+		return nil, fmt.Errorf("validation_error: target list must differ from source list")
+	}
+	itemIDs := make([]string, len(refs))
+	for i, ref := range refs {
+		itemIDs[i] = ref.ID
+	}
+	if err := s.validateMoveTarget(ctx, sourceListID, req.TargetListID, itemIDs...); err != nil {
+		return nil, err
+	}
+
+	order, err := s.nextOrder(ctx, req.TargetListID)
+	if err != nil {
+		return nil, err
+	}
+
+	targetList, err := s.rootList(ctx, req.TargetListID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.BulkItemResult, 0, len(refs))
+	for _, ref := range refs {
+		item, err := s.repo.Item.GetByID(ctx, sourceListID, ref.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get item: %w", err)
+		}
+		if item == nil {
+			results = append(results, models.BulkItemResult{ID: ref.ID, Status: models.BulkStatusNotFound})
+			continue
+		}
+
+		version := item.Version
+		if ref.Version != nil {
+			version = *ref.Version
+		}
+
+		movedItem, err := s.repo.Item.Move(ctx, sourceListID, req.TargetListID, ref.ID, order, version, userID)
+		if err != nil {
+			if !strings.Contains(err.Error(), "version_conflict") && !strings.Contains(err.Error(), "item not found") {
+				return nil, fmt.Errorf("failed to move items: %w", err)
+			}
+			result, err := s.bulkConflictResult(ctx, targetList, sourceListID, ref.ID, userID)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+			continue
+		}
+
+		order++
+		s.publishItemMove(ctx, sourceListID, movedItem, userID)
+		results = append(results, models.BulkItemResult{ID: ref.ID, Status: models.BulkStatusDone, Item: s.mapItemForUser(movedItem, targetList, userID)})
+	}
+
+	log.Printf("[SERVICE_BULK_MOVE] Processed %d items from listID=%s to listID=%s", len(results), sourceListID, req.TargetListID)
+	s.refreshItemCounts(ctx, sourceListID, req.TargetListID)
+	return results, nil
+}
+
+// BulkUpdateItems sets the given fields on multiple items and reports the outcome for each one
+func (s *ItemService) BulkUpdateItems(ctx context.Context, listID string, req *models.BulkUpdateRequest, userID string) ([]models.BulkItemResult, error) {
+	refs := bulkItemRefs(req.ItemIDs, req.Items)
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("validation_error: itemIds or items is required")
+	}
+	if req.Quantity == nil && req.QuantityType == nil {
+		return nil, fmt.Errorf("validation_error: at least one field to update is required")
+	}
+	if req.Quantity != nil && *req.Quantity <= 0 {
+		return nil, fmt.Errorf("validation_error: quantity must be greater than 0")
+	}
+	if req.QuantityType != nil && len(*req.QuantityType) > 50 {
+		return nil, fmt.Errorf("validation_error: quantityType must be at most 50 characters")
+	}
+	if req.QuantityType != nil {
+		quantityType := units.Normalize(*req.QuantityType)
+		req.QuantityType = &quantityType
+	}
+
+	list, err := s.rootList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.BulkItemResult, 0, len(refs))
+	for _, ref := range refs {
+		result, err := s.applyBulkUpdate(ctx, list, listID, ref, userID, func(item *models.Item) bool {
+			changed := false
+			if req.Quantity != nil && (item.Quantity == nil || *item.Quantity != *req.Quantity) {
+				quantity := *req.Quantity
+				item.Quantity = &quantity
+				changed = true
+			}
+			if req.QuantityType != nil && item.QuantityType != *req.QuantityType {
+				item.QuantityType = *req.QuantityType
+				changed = true
+			}
+			return changed
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update items: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// refreshItemCounts updates the denormalized item counts of the given lists (top-level or nested)
+// Counts are best effort, so failures are logged rather than returned
+func (s *ItemService) refreshItemCounts(ctx context.Context, listIDs ...string) {
+	for _, listID := range listIDs {
+		if err := s.repo.Item.UpdateItemCounts(ctx, listID); err != nil {
+			log.Printf("[SERVICE_ITEM_COUNTS] Failed to update nested list counts: listID=%s, error=%v", listID, err)
+		}
+		if err := s.repo.List.UpdateItemCounts(ctx, listID); err != nil {
+			log.Printf("[SERVICE_ITEM_COUNTS] Failed to update list counts: listID=%s, error=%v", listID, err)
+		}
+	}
+}
+
+// validateMoveTarget checks that items can be moved into the target list without breaking the nesting rules
+func (s *ItemService) validateMoveTarget(ctx context.Context, sourceListID string, targetListID string, itemIDs ...string) error {
+	if targetListID == "" {
+		return fmt.Errorf("validation_error: targetListId is required")
+	}
+	for _, itemID := range itemIDs {
+		if targetListID == itemID {
+			return fmt.Errorf("validation_error: an item cannot be moved into itself")
+		}
+	}
+
+	depth, err := s.listDepth(ctx, targetListID)
+	if err != nil {
+		return err
+	}
+	if depth == 0 {
+		return fmt.Errorf("target list not found")
+	}
+	if depth < maxNestingDepth {
+		return nil
+	}
+
+	// Nested lists can only live in top-level lists
+	for _, itemID := range itemIDs {
+		item, err := s.repo.Item.GetByID(ctx, sourceListID, itemID)
+		if err != nil {
+			return fmt.Errorf("failed to get item: %w", err)
+		}
+		if item != nil && item.Type == "list" {
+			return fmt.Errorf("validation_error: nested lists cannot be moved into another nested list")
+		}
+	}
+	return nil
+}
+
+// listExists checks whether the ID refers to a top-level list or a nested list
+func (s *ItemService) listExists(ctx context.Context, listID string) (bool, error) {
+	depth, err := s.listDepth(ctx, listID)
+	return depth > 0, err
+}
+
+// listDepth returns how deep a list ID is nested: 1 for top-level lists, 2 for nested lists and 0 if it does not exist
+// Lists nested deeper than allowed report maxNestingDepth+1
+func (s *ItemService) listDepth(ctx context.Context, listID string) (int, error) {
+	id := listID
+	for depth := 1; depth <= maxNestingDepth; depth++ {
+		list, err := s.repo.List.GetByID(ctx, id, "")
+		if err != nil {
+			return 0, fmt.Errorf("failed to get list: %w", err)
+		}
+		if list != nil {
+			return depth, nil
+		}
+
+		nestedList, err := s.repo.Item.GetNestedList(ctx, id)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get list: %w", err)
+		}
+		if nestedList == nil {
+			return 0, nil
+		}
+		id = nestedList.ListID
+	}
+	return maxNestingDepth + 1, nil
+}
+
+// rootList returns the top-level list of a list or nested list ID, or nil if it does not exist
+func (s *ItemService) rootList(ctx context.Context, listID string) (*models.List, error) {
+	list, err := s.repo.List.GetByID(ctx, listID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+	if list != nil {
+		return list, nil
+	}
+
+	nestedList, err := s.repo.Item.GetNestedList(ctx, listID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+	if nestedList == nil {
+		return nil, nil
+	}
+
+	list, err = s.repo.List.GetByID(ctx, nestedList.ListID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+	return list, nil
+}
+
+// nextOrder returns the order value for an item appended to the end of a list
+func (s *ItemService) nextOrder(ctx context.Context, listID string) (int32, error) {
+	existingItems, _, err := s.repo.Item.GetByListID(ctx, listID, models.ItemQuery{IncludeArchived: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get items: %w", err)
+	}
+
+	var maxOrder int32 = 0
+	for _, existing := range existingItems {
+		if existing.Order > maxOrder {
+			maxOrder = existing.Order
+		}
+	}
+	return maxOrder + 1, nil
 }
 
 // mapItemToResponse converts an Item model to an ItemResponse
@@ -235,6 +957,8 @@ func (s *ItemService) mapItemToResponse(item *models.Item) *models.ItemResponse
 		Type:               item.Type,
 		Name:               item.Name,
 		Completed:          item.Completed,
+		CompletedAt:        formatOptionalTime(item.CompletedAt),
+		CompletedBy:        item.CompletedBy,
 		CreatedAt:          item.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:          item.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 		CreatedBy:          item.CreatedBy,
@@ -243,9 +967,132 @@ func (s *ItemService) mapItemToResponse(item *models.Item) *models.ItemResponse
 		Order:              item.Order,
 		Quantity:           item.Quantity,
 		QuantityType:       item.QuantityType,
+		FulfilledQuantity:  item.FulfilledQuantity,
 		UserIconID:         item.UserIconID,
 		Description:        item.Description,
 		ItemCount:          item.ItemCount,
 		CompletedItemCount: item.CompletedItemCount,
+		PartialItemCount:   item.PartialItemCount,
+		Recurrence:         item.Recurrence,
+		DueAt:              formatOptionalTime(item.DueAt),
+		AllDay:             item.AllDay,
+		ReminderOffset:     item.ReminderOffset,
+		RemindAt:           formatOptionalTime(item.RemindAt),
+		AssignedTo:         item.AssignedTo,
+	}
+}
+
+// mapItemForUser converts an Item model to an ItemResponse from the caller's point of view
+// For per_member lists Completed reflects the caller's own state and aggregate progress is included
+func (s *ItemService) mapItemForUser(item *models.Item, list *models.List, userID string) *models.ItemResponse {
+	response := s.mapItemToResponse(item)
+	if !isPerMember(list) || item.Type != "item" {
+		return response
+	}
+
+	response.Completed = slices.Contains(item.CompletedByUsers, userID)
+	response.CompletedByUsers = item.CompletedByUsers
+	response.CompletedUserCount = len(item.CompletedByUsers)
+	response.MemberCount = len(list.Members)
+	return response
+}
+
+// isPerMember reports whether items of the list are completed per member
+func isPerMember(list *models.List) bool {
+	return list != nil && list.CompletionMode == models.CompletionModePerMember
+}
+
+// isCompletedBy reports whether the item is completed from the caller's point of view
+func isCompletedBy(item *models.Item, list *models.List, userID string) bool {
+	if isPerMember(list) {
+		return slices.Contains(item.CompletedByUsers, userID)
+	}
+	return item.Completed
+}
+
+// setCompletedBy changes the completion state of an item from the caller's point of view
+// In per_member lists only the caller's state changes, and the item itself is completed once every member completed it
+func setCompletedBy(item *models.Item, list *models.List, completed bool, userID string) {
+	if !isPerMember(list) {
+		setCompleted(item, completed, userID)
+		return
+	}
+
+	done := slices.Contains(item.CompletedByUsers, userID)
+	if completed && !done {
+		item.CompletedByUsers = append(item.CompletedByUsers, userID)
+	}
+	if !completed && done {
+		item.CompletedByUsers = slices.DeleteFunc(item.CompletedByUsers, func(user string) bool {
+			return user == userID
+		})
+	}
+
+	allDone := len(list.Members) > 0
+	for _, member := range list.Members {
+		if !slices.Contains(item.CompletedByUsers, member) {
+			allDone = false
+			break
+		}
+	}
+	setCompleted(item, allDone, userID)
+}
+
+// fitFulfilledQuantity keeps the bought amount of an item within its quantity after the quantity changed
+// Without a quantity the amount is dropped; once it covers the quantity the item is completed unless complete is false
+func fitFulfilledQuantity(item *models.Item, list *models.List, complete bool, userID string) {
+	if item.FulfilledQuantity == nil {
+		return
+	}
+	if item.Quantity == nil {
+		item.FulfilledQuantity = nil
+		return
+	}
+	if *item.FulfilledQuantity < *item.Quantity {
+		return
+	}
+
+	fulfilled := *item.Quantity
+	item.FulfilledQuantity = &fulfilled
+	if complete {
+		setCompletedBy(item, list, true, userID)
+	}
+}
+
+// setCompleted changes the completion state of an item and records who completed it and when
+func setCompleted(item *models.Item, completed bool, userID string) {
+	if item.Completed == completed {
+		return
+	}
+
+	item.Completed = completed
+	if completed {
+		now := time.Now()
+		item.CompletedAt = &now
+		item.CompletedBy = userID
+		item.RemindAt = nil
+		scheduleRecurrence(item, now)
+	} else {
+		// Returning an item to open also resets any partial fulfillment
+		item.CompletedAt = nil
+		item.CompletedBy = ""
+		item.FulfilledQuantity = nil
+		if item.Recurrence != nil {
+			item.Recurrence.NextAt = nil
+		}
+		// Reminders that would have been sent while the item was completed are skipped
+		now := time.Now()
+		scheduleReminder(item, now)
+		if item.RemindAt != nil && item.RemindAt.Before(now) {
+			item.RemindAt = nil
+		}
+	}
+}
+
+// formatOptionalTime formats a timestamp for responses, returning an empty string when unset
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
 	}
+	return t.Format("2006-01-02T15:04:05Z")
 }
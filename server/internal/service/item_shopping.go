@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/units"
+)
+
+// shoppingItem is an open item collected for the shopping view, with the list it was found in
+type shoppingItem struct {
+	item     models.Item
+	list     *models.List // Top-level list, which decides the completion mode
+	listID   string
+	listName string
+}
+
+// GetShoppingView combines the open items of several lists and their nested lists, grouping items with the same name
+func (s *ItemService) GetShoppingView(ctx context.Context, listIDs []string, userID string) ([]models.ShoppingEntry, error) {
+	items, err := s.collectShoppingItems(ctx, listIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupShoppingItems(items), nil
+}
+
+// CompleteShoppingEntry completes every open source item of a shopping view entry
+func (s *ItemService) CompleteShoppingEntry(ctx context.Context, req *models.CompleteShoppingEntryRequest, userID string) ([]models.BulkItemResult, error) {
+	key := normalizeName(req.Key, false)
+	if key == "" {
+		return nil, fmt.Errorf("validation_error: key is required")
+	}
+
+	items, err := s.collectShoppingItems(ctx, req.Lists, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[SERVICE_COMPLETE_SHOPPING_ENTRY] Completing entry: key=%s, lists=%v, userID=%s", key, req.Lists, userID)
+	results := []models.BulkItemResult{}
+	touched := []string{}
+	for _, collected := range items {
+		if normalizeName(collected.item.Name, false) != key {
+			continue
+		}
+
+		ref := models.BulkItemRef{ID: collected.item.UUID, Version: &collected.item.Version}
+		result, err := s.applyBulkUpdate(ctx, collected.list, collected.listID, ref, userID, completeForUser(collected.list, userID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to complete items: %w", err)
+		}
+		results = append(results, result)
+		if !slices.Contains(touched, collected.listID) {
+			touched = append(touched, collected.listID)
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("item not found")
+	}
+
+	s.refreshItemCounts(ctx, touched...)
+	return results, nil
+}
+
+// collectShoppingItems returns the open items of the given top-level lists and their nested lists
+func (s *ItemService) collectShoppingItems(ctx context.Context, listIDs []string, userID string) ([]shoppingItem, error) {
+	if len(listIDs) == 0 {
+		return nil, fmt.Errorf("validation_error: lists is required")
+	}
+
+	collected := []shoppingItem{}
+	seen := map[string]bool{}
+	for _, listID := range listIDs {
+		if seen[listID] {
+			continue
+		}
+
+		list, err := s.repo.List.GetByID(ctx, listID, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get list: %w", err)
+		}
+		if list == nil {
+			return nil, fmt.Errorf("list not found")
+		}
+
+		collected, err = s.collectOpenItems(ctx, list, listID, list.Name, userID, seen, collected)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return collected, nil
+}
+
+// collectOpenItems appends the items of a list that are open for the caller, descending into nested lists
+func (s *ItemService) collectOpenItems(ctx context.Context, list *models.List, listID string, listName string, userID string, seen map[string]bool, collected []shoppingItem) ([]shoppingItem, error) {
+	seen[listID] = true
+	items, _, err := s.repo.Item.GetByListID(ctx, listID, models.ItemQuery{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items: %w", err)
+	}
+
+	for _, item := range items {
+		switch {
+		case item.Type == "list" && !seen[item.UUID]:
+			collected, err = s.collectOpenItems(ctx, list, item.UUID, item.Name, userID, seen, collected)
+			if err != nil {
+				return nil, err
+			}
+		case item.Type == "item" && !isCompletedBy(&item, list, userID):
+			collected = append(collected, shoppingItem{item: item, list: list, listID: listID, listName: listName})
+		}
+	}
+
+	return collected, nil
+}
+
+// groupShoppingItems groups items by normalized name and sums their remaining quantities
+func groupShoppingItems(items []shoppingItem) []models.ShoppingEntry {
+	entries := []models.ShoppingEntry{}
+	index := map[string]int{}
+	for _, collected := range items {
+		item := collected.item
+		key := normalizeName(item.Name, false)
+		i, ok := index[key]
+		if !ok {
+			i = len(entries)
+			index[key] = i
+			entries = append(entries, models.ShoppingEntry{
+				Key:        key,
+				Name:       item.Name,
+				Quantities: []models.ShoppingQuantity{},
+				Sources:    []models.ShoppingSource{},
+			})
+		}
+
+		entry := &entries[i]
+		remaining := remainingQuantity(&item)
+		entry.Sources = append(entry.Sources, models.ShoppingSource{
+			ID:           item.UUID,
+			ListID:       collected.listID,
+			ListName:     collected.listName,
+			Quantity:     remaining,
+			QuantityType: item.QuantityType,
+			Version:      item.Version,
+		})
+		if remaining != nil {
+			entry.Quantities = addShoppingQuantity(entry.Quantities, *remaining, item.QuantityType)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	return entries
+}
+
+// addShoppingQuantity adds a quantity to the first sum with a convertible unit, or starts a new sum
+func addShoppingQuantity(quantities []models.ShoppingQuantity, value float64, unit string) []models.ShoppingQuantity {
+	for i := range quantities {
+		sum, err := units.Add(quantities[i].Quantity, quantities[i].QuantityType, value, unit)
+		if err == nil {
+			quantities[i].Quantity = sum
+			return quantities
+		}
+	}
+	return append(quantities, models.ShoppingQuantity{Quantity: value, QuantityType: unit})
+}
+
+// remainingQuantity returns the quantity of an item that is still to be bought, or nil when it has no quantity
+func remainingQuantity(item *models.Item) *float64 {
+	if item.Quantity == nil {
+		return nil
+	}
+	remaining := *item.Quantity
+	if item.FulfilledQuantity != nil {
+		remaining -= *item.FulfilledQuantity
+	}
+	return &remaining
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/repository"
+)
+
+// CloneList deep-copies a list, its items and its nested lists with their children under new UUIDs
+func (s *ListService) CloneList(ctx context.Context, listID string, req *models.CloneListRequest, userID string) (*models.ListResponse, error) {
+	source, err := s.repo.List.GetByID(ctx, listID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+	if source == nil {
+		return nil, fmt.Errorf("list not found")
+	}
+
+	name := req.Name
+	if name == "" {
+		name = source.Name + " (copy)"
+	}
+	if req.TargetListID != "" {
+		if req.OwnerID != "" {
+			return nil, fmt.Errorf("validation_error: a copy nested in another list takes that list's owner, so ownerId cannot be set")
+		}
+		return s.cloneIntoList(ctx, source, name, req, userID)
+	}
+
+	owner := req.OwnerID
+	if owner == "" {
+		owner = userID
+	} else if owner != userID {
+		user, err := s.repo.User.GetByUsername(ctx, owner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if user == nil {
+			return nil, fmt.Errorf("validation_error: owner %s is not a known user", owner)
+		}
+	}
+
+	list := &models.List{
+		UUID:           uuid.New().String(),
+		Name:           name,
+		Description:    source.Description,
+		Color:          source.Color,
+		CompletionMode: source.CompletionMode,
+		Members:        source.Members,
+		UserID:         owner,
+		CreatedBy:      userID,
+		UpdatedBy:      userID,
+	}
+
+	items, nestedListIDs, err := s.cloneItems(ctx, listID, list.UUID, req, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[SERVICE_CLONE_LIST] Cloning list: source=%s, uuid=%s, items=%d", listID, list.UUID, len(items))
+	if err := s.repo.List.CreateWithItems(ctx, list, items); err != nil {
+		log.Printf("[SERVICE_CLONE_LIST] Failed to clone list: source=%s, error=%v", listID, err)
+		return nil, fmt.Errorf("failed to clone list: %w", err)
+	}
+
+	itemService := s.itemService()
+	itemService.refreshItemCounts(ctx, append(nestedListIDs, list.UUID)...)
+	for i := range items {
+		itemService.publishListItemEvent(ctx, models.WebhookEventItemCreated, &items[i], list, userID)
+	}
+	cloned, err := s.repo.List.GetByID(ctx, list.UUID, userID)
+	if err != nil || cloned == nil {
+		cloned = list
+	}
+
+	log.Printf("[SERVICE_CLONE_LIST] Successfully cloned list: source=%s, uuid=%s", listID, list.UUID)
+	return s.mapListToResponse(cloned), nil
+}
+
+// cloneIntoList copies a list into a top-level list as a nested list, with the items written in one transaction
+// Nested lists cannot contain lists, so lists with nested lists can only be copied as top-level lists
+// The response describes the copy, which takes the settings of the list it is nested in
+func (s *ListService) cloneIntoList(ctx context.Context, source *models.List, name string, req *models.CloneListRequest, userID string) (*models.ListResponse, error) {
+	target, err := s.repo.List.GetByID(ctx, req.TargetListID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+	if target == nil {
+		return nil, fmt.Errorf("target list not found")
+	}
+
+	items := s.itemService()
+	order, err := items.nextOrder(ctx, target.UUID)
+	if err != nil {
+		return nil, err
+	}
+	nested := models.Item{
+		UUID:        uuid.New().String(),
+		ListID:      target.UUID,
+		Type:        "list",
+		Name:        name,
+		Description: source.Description,
+		Order:       order,
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+	}
+
+	children, nestedListIDs, err := s.cloneItems(ctx, source.UUID, nested.UUID, req, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(nestedListIDs) > 0 {
+		return nil, fmt.Errorf("validation_error: lists containing nested lists cannot be copied into another list")
+	}
+	copies := append([]models.Item{nested}, children...)
+
+	log.Printf("[SERVICE_CLONE_LIST] Cloning list into list: source=%s, target=%s, uuid=%s, items=%d", source.UUID, target.UUID, nested.UUID, len(children))
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		return s.repo.Item.CreateMany(ctx, copies)
+	})
+	if errors.Is(err, repository.ErrTransactionsUnsupported) {
+		// A single insert, which removes what it inserted when it fails
+		err = s.repo.Item.CreateMany(ctx, copies)
+	}
+	if err != nil {
+		log.Printf("[SERVICE_CLONE_LIST] Failed to clone list: source=%s, error=%v", source.UUID, err)
+		return nil, fmt.Errorf("failed to clone list: %w", err)
+	}
+
+	items.refreshItemCounts(ctx, nested.UUID, target.UUID)
+	for i := range copies {
+		items.publishListItemEvent(ctx, models.WebhookEventItemCreated, &copies[i], target, userID)
+	}
+	if current, err := s.repo.Item.GetNestedList(ctx, nested.UUID); err == nil && current != nil {
+		nested = *current
+	}
+
+	log.Printf("[SERVICE_CLONE_LIST] Successfully cloned list: source=%s, uuid=%s", source.UUID, nested.UUID)
+	return s.mapListToResponse(&models.List{
+		UUID:               nested.UUID,
+		Name:               nested.Name,
+		Description:        nested.Description,
+		Color:              target.Color,
+		CompletionMode:     target.CompletionMode,
+		Members:            target.Members,
+		UserID:             target.UserID,
+		ItemCount:          nested.ItemCount,
+		CompletedItemCount: nested.CompletedItemCount,
+		Version:            nested.Version,
+		CreatedAt:          nested.CreatedAt,
+		UpdatedAt:          nested.UpdatedAt,
+		CreatedBy:          nested.CreatedBy,
+		UpdatedBy:          nested.UpdatedBy,
+	}), nil
+}
+
+// cloneItems copies the items of a list into a new parent, descending into nested lists
+// It returns the copies and the new UUIDs of copied nested lists
+func (s *ListService) cloneItems(ctx context.Context, sourceListID string, targetListID string, req *models.CloneListRequest, userID string) ([]models.Item, []string, error) {
+	items, _, err := s.repo.Item.GetByListID(ctx, sourceListID, models.ItemQuery{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get items: %w", err)
+	}
+
+	copies := []models.Item{}
+	nestedListIDs := []string{}
+	for _, item := range items {
+		sourceID := item.UUID
+		item.ID = [12]byte{}
+		item.UUID = uuid.New().String()
+		item.ListID = targetListID
+		item.CreatedBy = userID
+		item.UpdatedBy = userID
+		item.SyncStatus = ""
+
+		if req.ResetCompletion {
+			item.Completed = false
+			item.CompletedAt = nil
+			item.CompletedBy = ""
+			item.CompletedByUsers = nil
+			item.FulfilledQuantity = nil
+			if item.Recurrence != nil {
+				item.Recurrence.NextAt = nil
+			}
+			scheduleReminder(&item, time.Now())
+		}
+		if req.DropQuantities {
+			item.Quantity = nil
+			item.QuantityType = ""
+			item.FulfilledQuantity = nil
+		}
+		copies = append(copies, item)
+
+		if item.Type == "list" {
+			children, _, err := s.cloneItems(ctx, sourceID, item.UUID, req, userID)
+			if err != nil {
+				return nil, nil, err
+			}
+			copies = append(copies, children...)
+			nestedListIDs = append(nestedListIDs, item.UUID)
+		}
+	}
+
+	return copies, nestedListIDs, nil
+}
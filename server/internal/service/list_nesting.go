@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/repository"
+)
+
+// PromoteNestedList turns a nested list into a top-level list with the same UUID
+// Its children already reference the UUID, so they move along; a list demoted before gets its own settings back,
+// other nested lists take them from the parent list
+func (s *ListService) PromoteNestedList(ctx context.Context, parentListID string, itemID string, req *models.PromoteNestedListRequest, userID string) (*models.ListResponse, error) {
+	log.Printf("[SERVICE_PROMOTE_LIST] Promoting nested list: itemID=%s, parentListID=%s, version=%d", itemID, parentListID, req.Version)
+	parent, err := s.repo.List.GetByID(ctx, parentListID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+	if parent == nil {
+		return nil, fmt.Errorf("list not found")
+	}
+
+	item, err := s.repo.Item.GetByID(ctx, parentListID, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+	if item == nil {
+		return nil, fmt.Errorf("item not found")
+	}
+	if item.Type != "list" {
+		return nil, fmt.Errorf("validation_error: only nested lists can be promoted")
+	}
+	if item.Version != req.Version {
+		log.Printf("[SERVICE_PROMOTE_LIST] Version conflict: itemID=%s, requested=%d, current=%d", itemID, req.Version, item.Version)
+		return nil, fmt.Errorf("version_conflict")
+	}
+
+	settings := item.ListSettings
+	if settings == nil {
+		settings = &models.ListSettings{Color: parent.Color, CompletionMode: parent.CompletionMode, Members: parent.Members}
+	}
+	list := &models.List{
+		UUID:           item.UUID,
+		Name:           item.Name,
+		Description:    item.Description,
+		Color:          settings.Color,
+		CompletionMode: settings.CompletionMode,
+		Members:        settings.Members,
+		Aliases:        settings.Aliases,
+		ResetSchedule:  settings.ResetSchedule,
+		UserID:         parent.UserID,
+		CreatedAt:      item.CreatedAt,
+		CreatedBy:      item.CreatedBy,
+		UpdatedBy:      userID,
+	}
+	if list.ResetSchedule != nil {
+		// Resets missed while the list was nested are skipped
+		if list.ResetSchedule.NextRunAt, err = nextReset(list.ResetSchedule, time.Now()); err != nil {
+			log.Printf("[SERVICE_PROMOTE_LIST] Dropping invalid reset schedule: itemID=%s, error=%v", itemID, err)
+			list.ResetSchedule = nil
+		}
+	}
+
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.List.Create(ctx, list); err != nil {
+			return fmt.Errorf("failed to create list: %w", err)
+		}
+		return s.repo.Item.Delete(ctx, parentListID, itemID, userID, req.Version)
+	})
+	if errors.Is(err, repository.ErrTransactionsUnsupported) {
+		err = s.promoteWithoutTransaction(ctx, list, parentListID, itemID, req.Version, userID)
+	}
+	if err != nil {
+		log.Printf("[SERVICE_PROMOTE_LIST] Failed to promote nested list: itemID=%s, error=%v", itemID, err)
+		return nil, fmt.Errorf("failed to promote list: %w", err)
+	}
+
+	items := s.itemService()
+	items.refreshItemCounts(ctx, list.UUID, parentListID)
+	items.publishListItemEvent(ctx, models.WebhookEventItemDeleted, item, parent, userID)
+	promoted, err := s.repo.List.GetByID(ctx, list.UUID, userID)
+	if err != nil || promoted == nil {
+		promoted = list
+	}
+
+	log.Printf("[SERVICE_PROMOTE_LIST] Promoted nested list: uuid=%s", list.UUID)
+	return s.mapListToResponse(promoted), nil
+}
+
+// promoteWithoutTransaction creates the promoted list and then removes the nested list, removing the new list again on failure
+func (s *ListService) promoteWithoutTransaction(ctx context.Context, list *models.List, parentListID string, itemID string, version int32, userID string) error {
+	if err := s.repo.List.Create(ctx, list); err != nil {
+		return fmt.Errorf("failed to create list: %w", err)
+	}
+
+	if err := s.repo.Item.Delete(ctx, parentListID, itemID, userID, version); err != nil {
+		log.Printf("[SERVICE_PROMOTE_LIST] Failed to remove nested list, reverting: itemID=%s, error=%v", itemID, err)
+		if revertErr := s.repo.List.Delete(context.WithoutCancel(ctx), list.UUID, userID, list.Version); revertErr != nil {
+			log.Printf("[SERVICE_PROMOTE_LIST] Failed to revert promoted list: uuid=%s, error=%v", list.UUID, revertErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// DemoteList turns a top-level list into a nested list of another top-level list, keeping its UUID and items
+// The list's settings are kept on the nested list and come back when it is promoted again
+func (s *ListService) DemoteList(ctx context.Context, listID string, req *models.DemoteListRequest, userID string) (*models.ItemResponse, error) {
+	log.Printf("[SERVICE_DEMOTE_LIST] Demoting list: listID=%s, targetListID=%s, version=%d", listID, req.TargetListID, req.Version)
+	if req.TargetListID == "" {
+		return nil, fmt.Errorf("validation_error: targetListId is required")
+	}
+	if req.TargetListID == listID {
+		return nil, fmt.Errorf("validation_error: a list cannot be demoted into itself")
+	}
+
+	list, err := s.repo.List.GetByID(ctx, listID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+	if list == nil {
+		return nil, fmt.Errorf("list not found")
+	}
+	if list.Version != req.Version {
+		log.Printf("[SERVICE_DEMOTE_LIST] Version conflict: listID=%s, requested=%d, current=%d", listID, req.Version, list.Version)
+		return nil, fmt.Errorf("version_conflict")
+	}
+
+	items := s.itemService()
+	depth, err := items.listDepth(ctx, req.TargetListID)
+	if err != nil {
+		return nil, err
+	}
+	if depth == 0 {
+		return nil, fmt.Errorf("target list not found")
+	}
+	if depth >= maxNestingDepth {
+		return nil, fmt.Errorf("validation_error: lists can only be demoted into a top-level list")
+	}
+
+	nestedLists, _, err := s.repo.Item.GetByListID(ctx, listID, models.ItemQuery{IncludeArchived: true, Type: "list", Limit: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items: %w", err)
+	}
+	if len(nestedLists) > 0 {
+		return nil, fmt.Errorf("validation_error: lists containing nested lists cannot be demoted")
+	}
+
+	order, err := items.nextOrder(ctx, req.TargetListID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &models.Item{
+		UUID:        list.UUID,
+		ListID:      req.TargetListID,
+		Type:        "list",
+		Name:        list.Name,
+		Description: list.Description,
+		CreatedAt:   list.CreatedAt,
+		CreatedBy:   list.CreatedBy,
+		UpdatedBy:   userID,
+		Order:       order,
+		ListSettings: &models.ListSettings{
+			Color:          list.Color,
+			CompletionMode: list.CompletionMode,
+			Members:        list.Members,
+			Aliases:        list.Aliases,
+			ResetSchedule:  list.ResetSchedule,
+		},
+	}
+
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Item.Create(ctx, item); err != nil {
+			return fmt.Errorf("failed to create nested list: %w", err)
+		}
+		return s.repo.List.Delete(ctx, listID, userID, req.Version)
+	})
+	if errors.Is(err, repository.ErrTransactionsUnsupported) {
+		err = s.demoteWithoutTransaction(ctx, item, listID, req.Version, userID)
+	}
+	if err != nil {
+		log.Printf("[SERVICE_DEMOTE_LIST] Failed to demote list: listID=%s, error=%v", listID, err)
+		return nil, fmt.Errorf("failed to demote list: %w", err)
+	}
+
+	items.refreshItemCounts(ctx, listID, req.TargetListID)
+	demoted, err := s.repo.Item.GetByID(ctx, req.TargetListID, item.UUID)
+	if err != nil || demoted == nil {
+		demoted = item
+	}
+
+	items.publishItemEvent(ctx, models.WebhookEventItemCreated, demoted, userID)
+	log.Printf("[SERVICE_DEMOTE_LIST] Demoted list: uuid=%s, into=%s", listID, req.TargetListID)
+	return items.mapItemToResponse(demoted), nil
+}
+
+// demoteWithoutTransaction creates the nested list and then removes the top-level list, removing the nested list again on failure
+func (s *ListService) demoteWithoutTransaction(ctx context.Context, item *models.Item, listID string, version int32, userID string) error {
+	if err := s.repo.Item.Create(ctx, item); err != nil {
+		return fmt.Errorf("failed to create nested list: %w", err)
+	}
+
+	if err := s.repo.List.Delete(ctx, listID, userID, version); err != nil {
+		log.Printf("[SERVICE_DEMOTE_LIST] Failed to remove list, reverting: listID=%s, error=%v", listID, err)
+		if revertErr := s.repo.Item.Delete(context.WithoutCancel(ctx), item.ListID, item.UUID, userID, item.Version); revertErr != nil {
+			log.Printf("[SERVICE_DEMOTE_LIST] Failed to revert nested list: uuid=%s, error=%v", item.UUID, revertErr)
+		}
+		return err
+	}
+	return nil
+}
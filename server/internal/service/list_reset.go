@@ -0,0 +1,423 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/repository"
+	"github.com/yair12/lists-viewer/server/internal/schedule"
+)
+
+const (
+	// resetActor is recorded as the author of scheduled resets
+	resetActor = "scheduler"
+
+	// resetHistoryLimit is the number of resets returned by the history
+	resetHistoryLimit = 50
+)
+
+// SetResetSchedule schedules recurring resets of a list
+func (s *ListService) SetResetSchedule(ctx context.Context, listID string, req *models.SetResetScheduleRequest, userID string) (*models.ListResponse, error) {
+	list, err := s.repo.List.GetByID(ctx, listID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+	if list == nil {
+		return nil, fmt.Errorf("list not found")
+	}
+
+	resetSchedule, err := newResetSchedule(req)
+	if err != nil {
+		return nil, err
+	}
+	if resetSchedule.Action == models.ResetActionTemplate {
+		if _, err := NewTemplateService(s.repo).getTemplate(ctx, resetSchedule.TemplateID); err != nil {
+			return nil, err
+		}
+	}
+
+	resetSchedule.NextRunAt, err = nextReset(resetSchedule, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[SERVICE_SET_RESET_SCHEDULE] Scheduling resets: listID=%s, cron=%s, timeZone=%s, action=%s, next=%s", listID, resetSchedule.Cron, resetSchedule.TimeZone, resetSchedule.Action, resetSchedule.NextRunAt)
+	if err := s.repo.List.SetResetSchedule(ctx, listID, resetSchedule); err != nil {
+		return nil, fmt.Errorf("failed to set reset schedule: %w", err)
+	}
+
+	list.ResetSchedule = resetSchedule
+	return s.mapListToResponse(list), nil
+}
+
+// DeleteResetSchedule stops the recurring resets of a list
+func (s *ListService) DeleteResetSchedule(ctx context.Context, listID string, userID string) error {
+	log.Printf("[SERVICE_DELETE_RESET_SCHEDULE] Removing reset schedule: listID=%s, userID=%s", listID, userID)
+	if err := s.repo.List.SetResetSchedule(ctx, listID, nil); err != nil {
+		return fmt.Errorf("failed to delete reset schedule: %w", err)
+	}
+	return nil
+}
+
+// ResetList resets a list right away, recording the reset in its history
+func (s *ListService) ResetList(ctx context.Context, listID string, req *models.ResetListRequest, userID string) (*models.ListResetResponse, error) {
+	list, err := s.repo.List.GetByID(ctx, listID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+	if list == nil {
+		return nil, fmt.Errorf("list not found")
+	}
+
+	action, templateID := req.Action, req.TemplateID
+	if action == "" && list.ResetSchedule != nil {
+		action, templateID = list.ResetSchedule.Action, list.ResetSchedule.TemplateID
+	}
+	if action == "" {
+		action = models.ResetActionUncomplete
+	}
+	if action == models.ResetActionTemplate && templateID == "" {
+		return nil, fmt.Errorf("validation_error: templateId is required for the template action")
+	}
+
+	reset, err := s.resetList(ctx, list, action, templateID, models.ResetTriggerManual, nil, userID)
+	if err != nil {
+		return nil, err
+	}
+	return mapListResetToResponse(reset), nil
+}
+
+// GetResetHistory retrieves the most recent resets of a list, newest first
+func (s *ListService) GetResetHistory(ctx context.Context, listID string, userID string) ([]models.ListResetResponse, error) {
+	list, err := s.repo.List.GetByID(ctx, listID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+	if list == nil {
+		return nil, fmt.Errorf("list not found")
+	}
+
+	resets, err := s.repo.Reset.GetByListID(ctx, listID, resetHistoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reset history: %w", err)
+	}
+
+	responses := make([]models.ListResetResponse, len(resets))
+	for i := range resets {
+		responses[i] = *mapListResetToResponse(&resets[i])
+	}
+	return responses, nil
+}
+
+// RunDueResets resets every list whose scheduled reset is due
+// Each run is claimed before it is performed, so repeated or concurrent calls reset a list once per scheduled run;
+// runs missed while the server was down are collapsed into a single reset, and a failed run is released to be retried
+func (s *ListService) RunDueResets(ctx context.Context, now time.Time) error {
+	lists, err := s.repo.List.GetDueResets(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to get due resets: %w", err)
+	}
+
+	for i := range lists {
+		list := &lists[i]
+		resetSchedule := list.ResetSchedule
+		dueAt := resetSchedule.NextRunAt
+
+		next, err := nextReset(resetSchedule, now)
+		if err != nil {
+			log.Printf("[SERVICE_RUN_RESETS] Invalid schedule, skipping: listID=%s, error=%v", list.UUID, err)
+			continue
+		}
+
+		claimed, err := s.repo.List.ClaimReset(ctx, list.UUID, dueAt, next, now)
+		if err != nil {
+			log.Printf("[SERVICE_RUN_RESETS] Failed to claim reset: listID=%s, error=%v", list.UUID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if _, err := s.resetList(ctx, list, resetSchedule.Action, resetSchedule.TemplateID, models.ResetTriggerSchedule, &dueAt, resetActor); err != nil {
+			log.Printf("[SERVICE_RUN_RESETS] Failed to reset list, releasing the run: listID=%s, error=%v", list.UUID, err)
+			if err := s.repo.List.ReleaseReset(ctx, list.UUID, dueAt, next, resetSchedule.LastRunAt); err != nil {
+				log.Printf("[SERVICE_RUN_RESETS] Failed to release reset: listID=%s, error=%v", list.UUID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// resetList performs a reset and records it in the list's history
+func (s *ListService) resetList(ctx context.Context, list *models.List, action string, templateID string, trigger string, scheduledFor *time.Time, userID string) (*models.ListReset, error) {
+	log.Printf("[SERVICE_RESET_LIST] Resetting list: listID=%s, action=%s, trigger=%s", list.UUID, action, trigger)
+
+	var count int32
+	var err error
+	switch action {
+	case models.ResetActionUncomplete:
+		count, err = s.uncompleteList(ctx, list.UUID, userID)
+	case models.ResetActionTemplate:
+		date := time.Now()
+		if scheduledFor != nil {
+			date = *scheduledFor
+		}
+		if list.ResetSchedule != nil {
+			if loc, err := time.LoadLocation(list.ResetSchedule.TimeZone); err == nil {
+				date = date.In(loc)
+			}
+		}
+		count, err = s.restoreFromTemplate(ctx, list.UUID, templateID, date, userID)
+	default:
+		err = fmt.Errorf("validation_error: action must be uncomplete or template")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reset := &models.ListReset{
+		UUID:         uuid.New().String(),
+		ListID:       list.UUID,
+		Action:       action,
+		TemplateID:   templateID,
+		Trigger:      trigger,
+		ScheduledFor: scheduledFor,
+		ItemCount:    count,
+		CreatedAt:    time.Now(),
+		CreatedBy:    userID,
+	}
+	if err := s.repo.Reset.Create(ctx, reset); err != nil {
+		log.Printf("[SERVICE_RESET_LIST] Failed to record reset: listID=%s, error=%v", list.UUID, err)
+	}
+	return reset, nil
+}
+
+// uncompleteList uncompletes the items of a list and its nested lists
+func (s *ListService) uncompleteList(ctx context.Context, listID string, userID string) (int32, error) {
+	listIDs := []string{listID}
+	nestedLists, _, err := s.repo.Item.GetByListID(ctx, listID, models.ItemQuery{IncludeArchived: true, Type: "list"})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get items: %w", err)
+	}
+	for _, nestedList := range nestedLists {
+		listIDs = append(listIDs, nestedList.UUID)
+	}
+
+	// The items about to change are loaded first, to schedule their reminders and describe the reset in events
+	var completed []models.Item
+	for _, id := range listIDs {
+		items, _, err := s.repo.Item.GetByListID(ctx, id, models.ItemQuery{IncludeArchived: true, Type: "item"})
+		if err != nil {
+			return 0, fmt.Errorf("failed to get items: %w", err)
+		}
+		for _, item := range items {
+			if item.Completed || len(item.CompletedByUsers) > 0 || item.FulfilledQuantity != nil {
+				completed = append(completed, item)
+			}
+		}
+	}
+
+	count, err := s.repo.Item.ResetCompletion(ctx, listIDs, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset items: %w", err)
+	}
+
+	items := s.itemService()
+	now := time.Now()
+	for i := range completed {
+		item := &completed[i]
+		markUncompleted(item, userID)
+		// Like reopening a single item, reminders whose time passed while it was completed are skipped
+		if item.DueAt != nil && item.ReminderOffset != nil {
+			scheduleReminder(item, now)
+			if item.RemindAt != nil && item.RemindAt.Before(now) {
+				item.RemindAt = nil
+			}
+			if err := s.repo.Item.ScheduleReminder(ctx, item); err != nil {
+				log.Printf("[SERVICE_RESET_LIST] Failed to schedule reminder: itemID=%s, error=%v", item.UUID, err)
+			}
+		}
+		items.publishItemEvent(ctx, models.WebhookEventItemUncompleted, item, userID)
+	}
+	items.refreshItemCounts(ctx, listIDs...)
+	return int32(count), nil
+}
+
+// restoreFromTemplate replaces the items of a list and its nested lists with the items of a template
+// The replacement runs in a transaction; without transactions the new items are inserted before the old ones are removed
+// and taken out again when that fails, so a failure leaves neither an empty list nor duplicates
+func (s *ListService) restoreFromTemplate(ctx context.Context, listID string, templateID string, date time.Time, userID string) (int32, error) {
+	template, err := NewTemplateService(s.repo).getTemplate(ctx, templateID)
+	if err != nil {
+		return 0, err
+	}
+
+	oldItems, _, err := s.repo.Item.GetByListID(ctx, listID, models.ItemQuery{IncludeArchived: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get items: %w", err)
+	}
+
+	items, nestedListIDs := templateListItems(template, listID, templateVariables(date, nil), userID)
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		return s.replaceItems(ctx, listID, items, oldItems)
+	})
+	if errors.Is(err, repository.ErrTransactionsUnsupported) {
+		err = s.replaceItemsWithoutTransaction(ctx, listID, items, oldItems)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	itemService := s.itemService()
+	for i := range items {
+		itemService.publishItemEvent(ctx, models.WebhookEventItemCreated, &items[i], userID)
+	}
+	for i := range oldItems {
+		itemService.publishItemEvent(ctx, models.WebhookEventItemDeleted, &oldItems[i], userID)
+	}
+	itemService.refreshItemCounts(ctx, append(nestedListIDs, listID)...)
+	return int32(len(items)), nil
+}
+
+// replaceItems inserts the new items of a list, then removes its old items and the items of its old nested lists
+func (s *ListService) replaceItems(ctx context.Context, listID string, items []models.Item, oldItems []models.Item) error {
+	if err := s.repo.Item.CreateMany(ctx, items); err != nil {
+		return fmt.Errorf("failed to restore items: %w", err)
+	}
+	if err := s.removeItems(ctx, listID, oldItems); err != nil {
+		return fmt.Errorf("failed to remove previous items: %w", err)
+	}
+	return nil
+}
+
+// replaceItemsWithoutTransaction replaces the items of a list like replaceItems, removing the new items again
+// when the old ones cannot be removed
+func (s *ListService) replaceItemsWithoutTransaction(ctx context.Context, listID string, items []models.Item, oldItems []models.Item) error {
+	if err := s.repo.Item.CreateMany(ctx, items); err != nil {
+		return fmt.Errorf("failed to restore items: %w", err)
+	}
+	if err := s.removeItems(ctx, listID, oldItems); err != nil {
+		if undoErr := s.removeItems(context.WithoutCancel(ctx), listID, items); undoErr != nil {
+			log.Printf("[SERVICE_RESET_LIST] Failed to remove restored items: listID=%s, error=%v", listID, undoErr)
+		}
+		return fmt.Errorf("failed to remove previous items: %w", err)
+	}
+	return nil
+}
+
+// removeItems deletes items of a list together with the items of those that are nested lists
+func (s *ListService) removeItems(ctx context.Context, listID string, items []models.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.UUID
+		if item.Type == "list" {
+			if err := s.repo.Item.DeleteByListID(ctx, item.UUID); err != nil {
+				return err
+			}
+		}
+	}
+	return s.repo.Item.BulkDelete(ctx, listID, ids)
+}
+
+// newResetSchedule validates a schedule request and converts presets to cron expressions
+func newResetSchedule(req *models.SetResetScheduleRequest) (*models.ResetSchedule, error) {
+	resetSchedule := &models.ResetSchedule{
+		Preset:     req.Preset,
+		Cron:       req.Cron,
+		TimeZone:   req.TimeZone,
+		Action:     req.Action,
+		TemplateID: req.TemplateID,
+	}
+	if resetSchedule.TimeZone == "" {
+		resetSchedule.TimeZone = "UTC"
+	}
+	if resetSchedule.Action == "" {
+		resetSchedule.Action = models.ResetActionUncomplete
+	}
+
+	switch resetSchedule.Action {
+	case models.ResetActionUncomplete:
+		resetSchedule.TemplateID = ""
+	case models.ResetActionTemplate:
+		if resetSchedule.TemplateID == "" {
+			return nil, fmt.Errorf("validation_error: templateId is required for the template action")
+		}
+	default:
+		return nil, fmt.Errorf("validation_error: action must be uncomplete or template")
+	}
+
+	switch resetSchedule.Preset {
+	case "":
+		if resetSchedule.Cron == "" {
+			return nil, fmt.Errorf("validation_error: preset or cron is required")
+		}
+	case "daily", "weekly":
+		hour, minute, err := schedule.ParseClock(req.Time)
+		if err != nil {
+			return nil, fmt.Errorf("validation_error: %v", err)
+		}
+		if resetSchedule.Preset == "daily" {
+			resetSchedule.Cron = schedule.Daily(hour, minute)
+			break
+		}
+		weekday, err := schedule.ParseWeekday(req.Weekday)
+		if err != nil {
+			return nil, fmt.Errorf("validation_error: %v", err)
+		}
+		resetSchedule.Cron = schedule.Weekly(weekday, hour, minute)
+	default:
+		return nil, fmt.Errorf("validation_error: preset must be daily or weekly")
+	}
+
+	if _, err := schedule.ParseCron(resetSchedule.Cron); err != nil {
+		return nil, fmt.Errorf("validation_error: %v", err)
+	}
+	if _, err := time.LoadLocation(resetSchedule.TimeZone); err != nil {
+		return nil, fmt.Errorf("validation_error: unknown time zone: %s", resetSchedule.TimeZone)
+	}
+	return resetSchedule, nil
+}
+
+// nextReset returns the first run of a schedule after the given time
+func nextReset(resetSchedule *models.ResetSchedule, after time.Time) (time.Time, error) {
+	cron, err := schedule.ParseCron(resetSchedule.Cron)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("validation_error: %v", err)
+	}
+	loc, err := time.LoadLocation(resetSchedule.TimeZone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("validation_error: unknown time zone: %s", resetSchedule.TimeZone)
+	}
+
+	next := cron.Next(after.In(loc))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("validation_error: the schedule never runs")
+	}
+	return next.UTC(), nil
+}
+
+// mapListResetToResponse converts a list reset to a response
+func mapListResetToResponse(reset *models.ListReset) *models.ListResetResponse {
+	response := &models.ListResetResponse{
+		ID:         reset.UUID,
+		ListID:     reset.ListID,
+		Action:     reset.Action,
+		TemplateID: reset.TemplateID,
+		Trigger:    reset.Trigger,
+		ItemCount:  reset.ItemCount,
+		CreatedAt:  reset.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		CreatedBy:  reset.CreatedBy,
+	}
+	if reset.ScheduledFor != nil {
+		response.ScheduledFor = reset.ScheduledFor.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	return response
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/yair12/lists-viewer/server/internal/models"
@@ -12,7 +14,8 @@ import (
 
 // ListService handles business logic for lists
 type ListService struct {
-	repo *repository.Repositories
+	repo     *repository.Repositories
+	webhooks *WebhookService
 }
 
 // NewListService creates a new list service
@@ -20,16 +23,41 @@ func NewListService(repo *repository.Repositories) *ListService {
 	return &ListService{repo: repo}
 }
 
+// SetWebhooks makes the service queue the item changes of list operations for the webhooks of the lists
+// Without a webhook service no events are published
+func (s *ListService) SetWebhooks(webhooks *WebhookService) {
+	s.webhooks = webhooks
+}
+
+// itemService returns an item service publishing to the list service's webhooks
+func (s *ListService) itemService() *ItemService {
+	items := NewItemService(s.repo)
+	items.SetWebhooks(s.webhooks)
+	return items
+}
+
 // CreateList creates a new list
 func (s *ListService) CreateList(ctx context.Context, req *models.CreateListRequest, userID string) (*models.ListResponse, error) {
+	mode, err := validateCompletionMode(req.CompletionMode)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.validateMembers(ctx, req.Members)
+	if err != nil {
+		return nil, err
+	}
+
 	list := &models.List{
-		UUID:        uuid.New().String(),
-		Name:        req.Name,
-		Description: req.Description,
-		Color:       req.Color,
-		UserID:      userID,
-		CreatedBy:   userID,
-		UpdatedBy:   userID,
+		UUID:           uuid.New().String(),
+		Name:           req.Name,
+		Description:    req.Description,
+		Color:          req.Color,
+		CompletionMode: mode,
+		Members:        members,
+		Aliases:        normalizeAliases(req.Aliases),
+		UserID:         userID,
+		CreatedBy:      userID,
+		UpdatedBy:      userID,
 	}
 
 	log.Printf("[SERVICE_CREATE_LIST] Creating list: uuid=%s, name=%s, color=%s, userID=%s", list.UUID, list.Name, list.Color, userID)
@@ -58,6 +86,47 @@ func (s *ListService) GetList(ctx context.Context, listID string, userID string)
 	return s.mapListToResponse(list), nil
 }
 
+// GetListExpanded retrieves a list together with its items ("items") and the items of its nested lists ("children")
+func (s *ListService) GetListExpanded(ctx context.Context, listID string, expand []string, userID string) (*models.ListResponse, error) {
+	includeChildren := false
+	for _, field := range expand {
+		switch field {
+		case "items":
+		case "children":
+			includeChildren = true
+		default:
+			return nil, fmt.Errorf("validation_error: expand must be items or children")
+		}
+	}
+
+	tree, err := s.repo.List.GetTree(ctx, listID, includeChildren)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+
+	if tree == nil {
+		log.Printf("[SERVICE_GET_LIST] List not found: listID=%s, userID=%s", listID, userID)
+		return nil, fmt.Errorf("list not found")
+	}
+
+	// Items are mapped from the caller's point of view, like GetItemsByList
+	items := s.itemService()
+	response := s.mapListToResponse(&tree.List)
+	response.Items = make([]models.ItemResponse, len(tree.Items))
+	for i, item := range tree.Items {
+		response.Items[i] = *items.mapItemForUser(&item.Item, &tree.List, userID)
+		if includeChildren && item.Type == "list" {
+			children := make([]models.ItemResponse, len(item.Children))
+			for j, child := range item.Children {
+				children[j] = *items.mapItemForUser(&child, &tree.List, userID)
+			}
+			response.Items[i].Children = children
+		}
+	}
+
+	return response, nil
+}
+
 // GetAllLists retrieves all lists for a user
 func (s *ListService) GetAllLists(ctx context.Context, userID string) ([]models.ListResponse, error) {
 	lists, err := s.repo.List.GetAll(ctx, userID)
@@ -100,6 +169,23 @@ func (s *ListService) UpdateList(ctx context.Context, listID string, req *models
 	existingList.Description = req.Description
 	existingList.Color = req.Color
 	existingList.UpdatedBy = userID
+	if req.CompletionMode != "" {
+		mode, err := validateCompletionMode(req.CompletionMode)
+		if err != nil {
+			return nil, err
+		}
+		existingList.CompletionMode = mode
+	}
+	if req.Members != nil {
+		members, err := s.validateMembers(ctx, req.Members)
+		if err != nil {
+			return nil, err
+		}
+		existingList.Members = members
+	}
+	if req.Aliases != nil {
+		existingList.Aliases = normalizeAliases(req.Aliases)
+	}
 	log.Printf("[SERVICE_UPDATE_LIST] After update - Name: %s, Color: %s", existingList.Name, existingList.Color)
 
 	if err := s.repo.List.Update(ctx, existingList); err != nil {
@@ -114,11 +200,30 @@ func (s *ListService) UpdateList(ctx context.Context, listID string, req *models
 // DeleteList deletes a list
 func (s *ListService) DeleteList(ctx context.Context, listID string, userID string, version int32) error {
 	log.Printf("[SERVICE_DELETE_LIST] Deleting list: listID=%s, userID=%s, version=%d", listID, userID, version)
+	// The list and its items are loaded first for the events describing the deletion
+	var list *models.List
+	var deleted []models.Item
+	if s.webhooks != nil {
+		var err error
+		if list, err = s.repo.List.GetByID(ctx, listID, userID); err != nil {
+			return fmt.Errorf("failed to get list: %w", err)
+		}
+		if list != nil {
+			if deleted, _, err = s.repo.Item.GetByListID(ctx, listID, models.ItemQuery{IncludeArchived: true}); err != nil {
+				return fmt.Errorf("failed to get items: %w", err)
+			}
+		}
+	}
+
 	// Delete all items in the list first
 	if err := s.repo.Item.DeleteByListID(ctx, listID); err != nil {
 		log.Printf("[SERVICE_DELETE_LIST] Failed to delete list items: listID=%s, error=%v", listID, err)
 		return fmt.Errorf("failed to delete list items: %w", err)
 	}
+	items := s.itemService()
+	for i := range deleted {
+		items.publishListItemEvent(ctx, models.WebhookEventItemDeleted, &deleted[i], list, userID)
+	}
 
 	// Delete the list
 	if err := s.repo.List.Delete(ctx, listID, userID, version); err != nil {
@@ -132,6 +237,11 @@ func (s *ListService) DeleteList(ctx context.Context, listID string, userID stri
 
 // mapListToResponse converts a List model to a ListResponse
 func (s *ListService) mapListToResponse(list *models.List) *models.ListResponse {
+	completionMode := list.CompletionMode
+	if completionMode == "" {
+		completionMode = models.CompletionModeShared
+	}
+
 	return &models.ListResponse{
 		ID:                 list.UUID,
 		Name:               list.Name,
@@ -142,7 +252,63 @@ func (s *ListService) mapListToResponse(list *models.List) *models.ListResponse
 		CreatedBy:          list.CreatedBy,
 		UpdatedBy:          list.UpdatedBy,
 		Version:            list.Version,
+		CompletionMode:     completionMode,
+		Members:            list.Members,
+		Aliases:            list.Aliases,
 		ItemCount:          list.ItemCount,
 		CompletedItemCount: list.CompletedItemCount,
+		PartialItemCount:   list.PartialItemCount,
+		ResetSchedule:      list.ResetSchedule,
+	}
+}
+
+// normalizeAliases trims list aliases and drops empty and repeated ones, ignoring case
+func normalizeAliases(aliases []string) []string {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		key := strings.ToLower(alias)
+		if alias == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, alias)
+	}
+	return normalized
+}
+
+// validateMembers trims list members, drops empty and repeated ones and checks that each one is a known user
+func (s *ListService) validateMembers(ctx context.Context, members []string) ([]string, error) {
+	if members == nil {
+		return nil, nil
+	}
+	validated := []string{}
+	for _, username := range members {
+		username = strings.TrimSpace(username)
+		if username == "" || slices.Contains(validated, username) {
+			continue
+		}
+		user, err := s.repo.User.GetByUsername(ctx, username)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if user == nil {
+			return nil, fmt.Errorf("validation_error: there is no user called %s", username)
+		}
+		validated = append(validated, username)
+	}
+	return validated, nil
+}
+
+// validateCompletionMode validates a list completion mode, defaulting to shared completion
+func validateCompletionMode(mode string) (string, error) {
+	switch mode {
+	case "", models.CompletionModeShared:
+		return models.CompletionModeShared, nil
+	case models.CompletionModePerMember:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("validation_error: completionMode must be %q or %q", models.CompletionModeShared, models.CompletionModePerMember)
 	}
 }
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+const (
+	// maxMIMEDepth bounds how deeply nested multipart messages are searched for a body
+	maxMIMEDepth = 5
+
+	// maxMailLineLength is the longest line taken as an item; longer lines are prose
+	maxMailLineLength = 255
+)
+
+var (
+	// bulletPattern matches list markers before an item ("- ", "* ", "• ", "1. ", "2) ", "[ ] ")
+	bulletPattern = regexp.MustCompile(`^(?:[-*•·‣◦–]|\d{1,3}[.)]|\[[ xX]?\])\s+`)
+
+	// forwardPattern matches the separators mail clients put above forwarded and quoted messages
+	forwardPattern = regexp.MustCompile(`(?i)^(?:-{2,}\s*(?:forwarded|original) message\s*-{2,}|begin forwarded message:|on .+ wrote:)$`)
+
+	// headerLinePattern matches the header lines mail clients repeat at the top of a forwarded message
+	headerLinePattern = regexp.MustCompile(`(?i)^(?:from|sent|date|subject|to|cc):\s`)
+)
+
+// messageLines extracts the item lines of a message from its plain-text body, or from the bullets of its HTML body
+func messageLines(msg *mail.Message) ([]string, error) {
+	plain, htmlBody, err := messageBodies(mail.Header(msg.Header), msg.Body, 0)
+	if err != nil {
+		return nil, err
+	}
+	if plain == "" && htmlBody != "" {
+		return htmlLines(htmlBody)
+	}
+	return textLines(plain), nil
+}
+
+// messageBodies finds the first plain-text and the first HTML body of a message or part, decoded to UTF-8
+// Attachments are skipped
+func messageBodies(header mail.Header, body io.Reader, depth int) (string, string, error) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	if disposition, _, _ := mime.ParseMediaType(header.Get("Content-Disposition")); disposition == "attachment" {
+		return "", "", nil
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if depth >= maxMIMEDepth {
+			return "", "", nil
+		}
+		var plain, htmlBody string
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", "", fmt.Errorf("malformed multipart message: %w", err)
+			}
+			partPlain, partHTML, err := messageBodies(mail.Header(part.Header), part, depth+1)
+			if err != nil {
+				return "", "", err
+			}
+			if plain == "" {
+				plain = partPlain
+			}
+			if htmlBody == "" {
+				htmlBody = partHTML
+			}
+		}
+		return plain, htmlBody, nil
+	}
+
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", "", nil
+	}
+	text, err := decodeText(body, header.Get("Content-Transfer-Encoding"), params["charset"])
+	if err != nil {
+		return "", "", err
+	}
+	if mediaType == "text/html" {
+		return "", text, nil
+	}
+	return text, "", nil
+}
+
+// decodeText undoes the transfer encoding of a text part and converts it from its charset to UTF-8
+func decodeText(body io.Reader, transferEncoding string, charset string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(transferEncoding)) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+
+	if charset != "" && !strings.EqualFold(charset, "utf-8") && !strings.EqualFold(charset, "us-ascii") {
+		encoding, err := htmlindex.Get(charset)
+		if err != nil {
+			return "", fmt.Errorf("unsupported charset %s", charset)
+		}
+		body = encoding.NewDecoder().Reader(body)
+	}
+
+	text, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("malformed message body: %w", err)
+	}
+	if !utf8.Valid(text) {
+		return "", fmt.Errorf("message body is not valid UTF-8")
+	}
+	return string(text), nil
+}
+
+// textLines turns a plain-text body into item lines
+// Bullets are stripped, and signatures, quoted replies and forwarding headers are left out
+// Of other text only lines ending in a comma or colon and overlong lines are dropped; any other sentence becomes an item
+func textLines(text string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if line == "-- " {
+			break
+		}
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, ">") || forwardPattern.MatchString(line) || headerLinePattern.MatchString(line) {
+			continue
+		}
+		for bulletPattern.MatchString(line) {
+			line = bulletPattern.ReplaceAllString(line, "")
+		}
+		// Greetings ("Hi,") and headings ("Ingredients:") are not items
+		if line == "" || strings.HasSuffix(line, ",") || strings.HasSuffix(line, ":") || utf8.RuneCountInString(line) > maxMailLineLength {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// htmlLines turns an HTML body into item lines: the text of each bullet, or each line of text when there are no bullets
+func htmlLines(body string) ([]string, error) {
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("malformed HTML body: %w", err)
+	}
+
+	var items []string
+	var collect func(node *html.Node)
+	collect = func(node *html.Node) {
+		if node.Type == html.ElementNode && node.DataAtom == atom.Li {
+			text := new(bytes.Buffer)
+			writeText(text, node, false)
+			items = append(items, text.String())
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			collect(child)
+		}
+	}
+	collect(doc)
+
+	if len(items) > 0 {
+		return textLines(strings.Join(items, "\n")), nil
+	}
+	text := new(bytes.Buffer)
+	writeText(text, doc, true)
+	return textLines(text.String()), nil
+}
+
+// writeText writes the text of an HTML node, starting a new line at block elements
+// Nested lists are left out of a bullet's text since their bullets are items of their own
+func writeText(w *bytes.Buffer, node *html.Node, blocks bool) {
+	switch {
+	case node.Type == html.TextNode:
+		w.WriteString(strings.Join(strings.Fields(node.Data), " "))
+		if strings.HasSuffix(node.Data, " ") || strings.HasSuffix(node.Data, "\n") {
+			w.WriteString(" ")
+		}
+		return
+	case node.Type != html.ElementNode && node.Type != html.DocumentNode:
+		return
+	}
+
+	switch node.DataAtom {
+	case atom.Script, atom.Style, atom.Head, atom.Blockquote:
+		return
+	case atom.Ul, atom.Ol:
+		if !blocks {
+			return
+		}
+	case atom.Br, atom.P, atom.Div, atom.Li, atom.Tr, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		if blocks {
+			w.WriteString("\n")
+		}
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeText(w, child, blocks)
+	}
+}
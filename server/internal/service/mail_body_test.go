@@ -0,0 +1,101 @@
+package service
+
+import (
+	"net/mail"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTextLines(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"Bullets are stripped", "- milk\n* eggs\n• bread\n1. flour\n2) sugar\n[ ] salt\n[x] pepper", []string{"milk", "eggs", "bread", "flour", "sugar", "salt", "pepper"}},
+		{"Nested bullets are stripped", "- - milk", []string{"milk"}},
+		{"Blank lines and CRLF endings", "milk\r\n\r\n  eggs  \r\n", []string{"milk", "eggs"}},
+		{"Greetings and headings are dropped", "Hi,\nIngredients:\nmilk", []string{"milk"}},
+		{"Quoted replies are dropped", "milk\n> eggs\n>> bread", []string{"milk"}},
+		{"The signature ends the items", "milk\n-- \nSent from my phone", []string{"milk"}},
+		{"Forwarding headers are dropped", "---------- Forwarded message ---------\nFrom: Me <me@example.com>\nSubject: list\nmilk", []string{"milk"}},
+		{"Reply attributions are dropped", "milk\nOn Mon, Jan 5, 2026 at 10:00 Me wrote:", []string{"milk"}},
+		{"Overlong lines are dropped", strings.Repeat("a", maxMailLineLength+1) + "\nmilk", []string{"milk"}},
+		{"Other sentences are kept", "Please also get some milk", []string{"Please also get some milk"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := textLines(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHTMLLines(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"Bullets become items", "<p>Shopping:</p><ul><li>Milk</li><li>Eggs <b>x12</b></li></ul>", []string{"Milk", "Eggs x12"}},
+		{"Nested bullets are items of their own", "<ul><li>Dairy<ul><li>Milk</li></ul></li></ul>", []string{"Dairy", "Milk"}},
+		{"Without bullets each line is an item", "<div>Milk</div><div>Eggs<br>Bread</div>", []string{"Milk", "Eggs", "Bread"}},
+		{"Scripts, styles and quotes are skipped", "<style>p{}</style><p>Milk</p><blockquote>Eggs</blockquote><script>x()</script>", []string{"Milk"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := htmlLines(tt.body)
+			if err != nil {
+				t.Fatalf("Failed to read %q: %v", tt.body, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMessageLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+		wantErr bool
+	}{
+		{"Plain text", "Subject: list\n\nmilk\neggs\n", []string{"milk", "eggs"}, false},
+		{"Quoted-printable", "Content-Type: text/plain; charset=utf-8\nContent-Transfer-Encoding: quoted-printable\n\nwood =\nglue\n", []string{"wood glue"}, false},
+		{"Base64", "Content-Type: text/plain\nContent-Transfer-Encoding: base64\n\nbWlsawplZ2dzCg==\n", []string{"milk", "eggs"}, false},
+		{"Other charsets are converted", "Content-Type: text/plain; charset=iso-8859-1\nContent-Transfer-Encoding: quoted-printable\n\ncr=E8me\n", []string{"crème"}, false},
+		{"Plain text is preferred over HTML", "Content-Type: multipart/alternative; boundary=b\n\n--b\nContent-Type: text/html\n\n<ul><li>html</li></ul>\n--b\nContent-Type: text/plain\n\nplain\n--b--\n", []string{"plain"}, false},
+		{"HTML is used without plain text", "Content-Type: multipart/alternative; boundary=b\n\n--b\nContent-Type: text/html\n\n<ul><li>milk</li></ul>\n--b--\n", []string{"milk"}, false},
+		{"Attachments are skipped", "Content-Type: multipart/mixed; boundary=b\n\n--b\nContent-Type: text/plain\nContent-Disposition: attachment\n\nsecret\n--b\nContent-Type: text/plain\n\nmilk\n--b--\n", []string{"milk"}, false},
+		{"Unknown charsets are rejected", "Content-Type: text/plain; charset=x-unknown\n\nmilk\n", nil, true},
+		{"Invalid UTF-8 is rejected", "Content-Type: text/plain\nContent-Transfer-Encoding: base64\n\n/w==\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := mail.ReadMessage(strings.NewReader(strings.ReplaceAll(tt.message, "\n", "\r\n")))
+			if err != nil {
+				t.Fatalf("Failed to read message: %v", err)
+			}
+			got, err := messageLines(msg)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to get lines: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
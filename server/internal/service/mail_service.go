@@ -0,0 +1,168 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/quickadd"
+	"github.com/yair12/lists-viewer/server/internal/repository"
+	"github.com/yair12/lists-viewer/server/internal/smtpd"
+)
+
+// maxMailItems is the largest number of items added from one message
+const maxMailItems = 100
+
+// mailboxSeparators are written instead of spaces in the list part of an address ("hardware-store+<token>@host")
+var mailboxSeparators = strings.NewReplacer("-", " ", "_", " ", ".", " ")
+
+// mailTarget is a list a message was addressed to, with the token of its address
+type mailTarget struct {
+	token *models.InboundToken
+	list  *models.List
+}
+
+// MailService adds items from email sent to list addresses such as groceries+<token>@host
+// The token is an inbound token, and the list part is a list name or alias; without it the token's default list is used
+// It is the backend of the SMTP listener
+type MailService struct {
+	repo  *repository.Repositories
+	items *ItemService
+}
+
+// NewMailService creates a new mail service adding items through the given item service
+func NewMailService(repo *repository.Repositories, items *ItemService) *MailService {
+	return &MailService{repo: repo, items: items}
+}
+
+// Recipient accepts a list address when its token is valid, the envelope sender may use the token and the list exists
+func (s *MailService) Recipient(ctx context.Context, from string, to string) error {
+	_, _, err := s.resolveAddress(ctx, from, to)
+	return err
+}
+
+// Deliver adds an item for each line of the message to the lists it was addressed to
+// The senders in the From header must be allowed to use the tokens as well as the envelope sender
+func (s *MailService) Deliver(ctx context.Context, from string, to []string, data []byte) error {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return smtpd.Reject("malformed message")
+	}
+	authors, err := msg.Header.AddressList("From")
+	if err != nil || len(authors) == 0 {
+		return smtpd.Reject("message has no valid From header")
+	}
+	lines, err := messageLines(msg)
+	if err != nil {
+		return smtpd.Reject("%v", err)
+	}
+	if len(lines) > maxMailItems {
+		lines = lines[:maxMailItems]
+	}
+
+	// Recipients were checked at RCPT, but tokens can be revoked since; resolve them all before adding anything
+	// Addresses naming the same list add its items once
+	targets := make([]mailTarget, 0, len(to))
+	for _, address := range to {
+		token, list, err := s.resolveAddress(ctx, from, address)
+		if err != nil {
+			return err
+		}
+		for _, author := range authors {
+			if !allowedSender(token, author.Address) {
+				log.Printf("[SERVICE_MAIL_DELIVER] Rejected author: from=%s, token=%s", author.Address, token.UUID)
+				return smtpd.Reject("sender %s may not add to this list", author.Address)
+			}
+		}
+		if !slices.ContainsFunc(targets, func(target mailTarget) bool { return target.list.UUID == list.UUID }) {
+			targets = append(targets, mailTarget{token: token, list: list})
+		}
+	}
+
+	// A failure before any item is added is temporary, so the sender retries the message
+	// After that a retry would add the same items again, so the message is rejected permanently, telling the sender what was added
+	total := 0
+	for _, target := range targets {
+		added := 0
+		for _, line := range lines {
+			parsed, err := quickadd.Parse(line)
+			if err != nil {
+				continue
+			}
+			_, err = s.items.CreateItem(ctx, target.list.UUID, &models.CreateItemRequest{
+				Type:         "item",
+				Name:         parsed.Name,
+				Quantity:     parsed.Quantity,
+				QuantityType: parsed.QuantityType,
+			}, target.token.UserID)
+			if err != nil {
+				if total == 0 {
+					return fmt.Errorf("failed to add item: %w", err)
+				}
+				log.Printf("[SERVICE_MAIL_DELIVER] Failed to add item, dropping the rest of the message: listID=%s, added=%d, error=%v", target.list.UUID, total, err)
+				return smtpd.Reject("only the first %d items were added; send the rest again", total)
+			}
+			added++
+			total++
+		}
+
+		if err := s.repo.Inbound.MarkUsed(ctx, target.token.UUID, time.Now()); err != nil {
+			log.Printf("[SERVICE_MAIL_DELIVER] Failed to record token use: uuid=%s, error=%v", target.token.UUID, err)
+		}
+		log.Printf("[SERVICE_MAIL_DELIVER] Added items from mail: listID=%s, token=%s, count=%d", target.list.UUID, target.token.UUID, added)
+	}
+	return nil
+}
+
+// resolveAddress finds the token and list of a list address, rejecting unknown addresses and unauthorized senders
+func (s *MailService) resolveAddress(ctx context.Context, from string, to string) (*models.InboundToken, *models.List, error) {
+	at := strings.LastIndex(to, "@")
+	if at <= 0 {
+		return nil, nil, smtpd.Reject("no such mailbox")
+	}
+	listName, tokenValue := "", to[:at]
+	if plus := strings.LastIndex(tokenValue, "+"); plus >= 0 {
+		listName, tokenValue = tokenValue[:plus], tokenValue[plus+1:]
+	}
+
+	token, err := s.repo.Inbound.GetByHash(ctx, hashToken(strings.ToLower(tokenValue)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	if token == nil {
+		return nil, nil, smtpd.Reject("no such mailbox")
+	}
+	if !allowedSender(token, from) {
+		log.Printf("[SERVICE_MAIL_RECIPIENT] Rejected sender: from=%s, token=%s", from, token.UUID)
+		return nil, nil, smtpd.Reject("sender %s may not add to this list", from)
+	}
+
+	lists, err := s.repo.List.GetAll(ctx, token.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get lists: %w", err)
+	}
+	var list *models.List
+	switch {
+	case listName != "":
+		if list = findList(lists, listName, token.UserID); list == nil {
+			list = findList(lists, mailboxSeparators.Replace(listName), token.UserID)
+		}
+	case token.ListID != "":
+		list = findListByID(lists, token.ListID)
+	}
+	if list == nil {
+		return nil, nil, smtpd.Reject("no such list")
+	}
+	return token, list, nil
+}
+
+// allowedSender reports whether a sender address may add items with a token
+func allowedSender(token *models.InboundToken, address string) bool {
+	return slices.Contains(token.Senders, strings.ToLower(address))
+}
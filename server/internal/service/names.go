@@ -0,0 +1,26 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizeName returns the key used to compare item names: lower-cased with collapsed whitespace
+// With foldUnicode, accents and other combining marks (e.g. Hebrew niqqud) are removed as well
+func normalizeName(name string, foldUnicode bool) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	if !foldUnicode {
+		return normalized
+	}
+
+	folder := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(folder, normalized)
+	if err != nil {
+		return normalized
+	}
+	return folded
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/repository"
+	"github.com/yair12/lists-viewer/server/internal/webpush"
+)
+
+// PushService handles business logic for Web Push subscriptions
+type PushService struct {
+	repo *repository.Repositories
+	keys *webpush.VAPIDKeys
+}
+
+// NewPushService creates a new push service for the server's VAPID keys
+func NewPushService(repo *repository.Repositories, keys *webpush.VAPIDKeys) *PushService {
+	return &PushService{repo: repo, keys: keys}
+}
+
+// PublicKey returns the VAPID public key browsers subscribe with
+func (s *PushService) PublicKey() (string, error) {
+	if s.keys == nil {
+		return "", fmt.Errorf("push_unavailable")
+	}
+	return s.keys.PublicKey, nil
+}
+
+// RegisterSubscription registers a browser of the user for push notifications
+// Registering an endpoint again updates its keys and device name
+func (s *PushService) RegisterSubscription(ctx context.Context, req *models.RegisterPushSubscriptionRequest, userID string) (*models.PushSubscriptionResponse, error) {
+	if s.keys == nil {
+		return nil, fmt.Errorf("push_unavailable")
+	}
+	sub := &models.PushSubscription{
+		UserID:     userID,
+		Endpoint:   req.Endpoint,
+		P256dh:     req.Keys.P256dh,
+		Auth:       req.Keys.Auth,
+		DeviceName: req.DeviceName,
+	}
+	if err := (webpush.Subscription{Endpoint: sub.Endpoint, P256dh: sub.P256dh, Auth: sub.Auth}).Validate(); err != nil {
+		return nil, fmt.Errorf("validation_error: %v", err)
+	}
+	if len(sub.DeviceName) > 100 {
+		return nil, fmt.Errorf("validation_error: deviceName must be at most 100 characters")
+	}
+
+	if err := s.repo.Push.Upsert(ctx, sub); err != nil {
+		return nil, fmt.Errorf("failed to register push subscription: %w", err)
+	}
+	log.Printf("[SERVICE_REGISTER_PUSH] Registered push subscription: userID=%s, uuid=%s", userID, sub.UUID)
+	return mapPushSubscriptionToResponse(sub), nil
+}
+
+// GetSubscriptions retrieves the push subscriptions of the user
+func (s *PushService) GetSubscriptions(ctx context.Context, userID string) ([]models.PushSubscriptionResponse, error) {
+	subs, err := s.repo.Push.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get push subscriptions: %w", err)
+	}
+
+	responses := make([]models.PushSubscriptionResponse, len(subs))
+	for i := range subs {
+		responses[i] = *mapPushSubscriptionToResponse(&subs[i])
+	}
+	return responses, nil
+}
+
+// DeleteSubscription unregisters a push subscription of the user
+func (s *PushService) DeleteSubscription(ctx context.Context, subscriptionID string, userID string) error {
+	deleted, err := s.repo.Push.Delete(ctx, userID, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete push subscription: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("subscription not found")
+	}
+	log.Printf("[SERVICE_DELETE_PUSH] Deleted push subscription: userID=%s, uuid=%s", userID, subscriptionID)
+	return nil
+}
+
+// mapPushSubscriptionToResponse converts a PushSubscription model to a response, leaving out its keys
+func mapPushSubscriptionToResponse(sub *models.PushSubscription) *models.PushSubscriptionResponse {
+	return &models.PushSubscriptionResponse{
+		ID:         sub.UUID,
+		Endpoint:   sub.Endpoint,
+		DeviceName: sub.DeviceName,
+		CreatedAt:  sub.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:  sub.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/notify"
+	"github.com/yair12/lists-viewer/server/internal/repository"
+)
+
+// ReminderService sends the reminders of items that are coming due
+type ReminderService struct {
+	repo     *repository.Repositories
+	items    *ItemService
+	notifier notify.Notifier
+}
+
+// NewReminderService creates a new reminder service delivering through the given notifier
+func NewReminderService(repo *repository.Repositories, notifier notify.Notifier) *ReminderService {
+	return &ReminderService{repo: repo, items: NewItemService(repo), notifier: notifier}
+}
+
+// SendDueReminders sends every reminder whose time has come
+// A reminder is claimed before it is sent, so repeated or concurrent runs send it once; failed deliveries are logged and not retried
+func (s *ReminderService) SendDueReminders(ctx context.Context, now time.Time) error {
+	items, err := s.repo.Item.GetDueReminders(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to get due reminders: %w", err)
+	}
+
+	containers := map[string]*itemContainer{}
+	for i := range items {
+		item := &items[i]
+		container, err := s.items.containerOf(ctx, item.ListID, containers)
+		if err != nil {
+			log.Printf("[SERVICE_SEND_REMINDERS] Failed to get list: itemID=%s, error=%v", item.UUID, err)
+			continue
+		}
+
+		claimed, err := s.repo.Item.ClaimReminder(ctx, item)
+		if err != nil || !claimed {
+			continue
+		}
+		if container.list == nil || container.list.Archived {
+			continue
+		}
+
+		for _, userID := range reminderRecipients(item, container.list) {
+			if isCompletedBy(item, container.list, userID) {
+				continue
+			}
+			reminder, err := s.newReminder(ctx, item, container.name, userID)
+			if err != nil {
+				log.Printf("[SERVICE_SEND_REMINDERS] Failed to build reminder: itemID=%s, userID=%s, error=%v", item.UUID, userID, err)
+				continue
+			}
+			if err := s.notifier.Notify(ctx, reminder); err != nil {
+				log.Printf("[SERVICE_SEND_REMINDERS] Failed to deliver reminder: itemID=%s, userID=%s, error=%v", item.UUID, userID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// newReminder builds the reminder for one recipient, with the due time written in their time zone
+func (s *ReminderService) newReminder(ctx context.Context, item *models.Item, listName string, userID string) (notify.Notification, error) {
+	user, err := s.repo.User.GetByUsername(ctx, userID)
+	if err != nil {
+		return notify.Notification{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	loc := preferredLocation(user)
+	layout := "Mon Jan 2 15:04"
+	if item.AllDay {
+		layout = "Mon Jan 2"
+	}
+
+	return notify.Notification{
+		Type:     notify.EventReminder,
+		UserID:   userID,
+		ListID:   item.ListID,
+		ListName: listName,
+		ItemID:   item.UUID,
+		ItemName: item.Name,
+		DueAt:    item.DueAt,
+		AllDay:   item.AllDay,
+		TimeZone: loc.String(),
+		DueText:  item.DueAt.In(loc).Format(layout),
+	}, nil
+}
+
+// reminderRecipients returns the users reminded of an item: its assignee, its creator, the list owner and the list members
+func reminderRecipients(item *models.Item, list *models.List) []string {
+	recipients := []string{}
+	for _, userID := range append([]string{item.AssignedTo, item.CreatedBy, list.UserID}, list.Members...) {
+		if userID != "" && !slices.Contains(recipients, userID) {
+			recipients = append(recipients, userID)
+		}
+	}
+	return recipients
+}
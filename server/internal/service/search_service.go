@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/repository"
+)
+
+// Search modes reported in responses
+const (
+	searchModeText      = "text"
+	searchModeSubstring = "substring"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+)
+
+// SearchService handles searches across lists and items
+type SearchService struct {
+	repo  *repository.Repositories
+	items *ItemService
+	lists *ListService
+}
+
+// NewSearchService creates a new search service
+func NewSearchService(repo *repository.Repositories) *SearchService {
+	return &SearchService{
+		repo:  repo,
+		items: NewItemService(repo),
+		lists: NewListService(repo),
+	}
+}
+
+// searchParent is the list containing a matched item
+type searchParent struct {
+	name string
+	root *models.List // Top-level list, nil for orphaned items
+}
+
+// Search finds lists, nested lists and items by name or description
+// The text index is used for Latin-script queries; other scripts such as Hebrew, and text queries without
+// any match, fall back to a case-insensitive substring match
+func (s *SearchService) Search(ctx context.Context, req *models.SearchRequest, userID string) (*models.SearchResponse, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, fmt.Errorf("validation_error: q is required")
+	}
+	if req.Type != "" && req.Type != "item" && req.Type != "list" {
+		return nil, fmt.Errorf("validation_error: type must be item or list")
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	if limit < 1 || limit > maxSearchLimit {
+		return nil, fmt.Errorf("validation_error: limit must be between 1 and %d", maxSearchLimit)
+	}
+
+	filter := repository.SearchFilter{Query: query, Regex: !isLatinText(query), Type: req.Type, Limit: maxSearchLimit}
+	if req.ListID != "" {
+		listIDs, err := s.listScope(ctx, req.ListID)
+		if err != nil {
+			return nil, err
+		}
+		filter.ListIDs = listIDs
+	}
+
+	// Top-level lists have no completion state and contain, rather than belong to, the list filter
+	includeLists := req.Type != "item" && req.Completed == nil && req.ListID == ""
+
+	lists, items, err := s.find(ctx, filter, includeLists)
+	if err != nil && !filter.Regex {
+		log.Printf("[SERVICE_SEARCH] Text search failed, falling back to substring search: query=%s, error=%v", query, err)
+	}
+	if !filter.Regex && (err != nil || len(lists)+len(items) == 0) {
+		filter.Regex = true
+		lists, items, err = s.find(ctx, filter, includeLists)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+
+	terms := strings.Fields(query)
+	results := []models.SearchResult{}
+	for _, list := range lists {
+		results = append(results, models.SearchResult{
+			Kind:       models.SearchKindList,
+			List:       s.lists.mapListToResponse(&list),
+			Highlights: searchHighlights(list.Name, list.Description, terms),
+		})
+	}
+
+	parents := map[string]searchParent{}
+	for _, item := range items {
+		parent, err := s.searchParent(ctx, item.ListID, parents)
+		if err != nil {
+			return nil, err
+		}
+		if parent.root == nil || parent.root.Archived {
+			continue
+		}
+
+		if req.Completed != nil {
+			if item.Type != "item" || isCompletedBy(&item, parent.root, userID) != *req.Completed {
+				continue
+			}
+		}
+
+		results = append(results, models.SearchResult{
+			Kind:       models.SearchKindItem,
+			Item:       s.items.mapItemForUser(&item, parent.root, userID),
+			ListName:   parent.name,
+			Highlights: searchHighlights(item.Name, item.Description, terms),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return searchScore(&results[i], query) > searchScore(&results[j], query)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	mode := searchModeText
+	if filter.Regex {
+		mode = searchModeSubstring
+	}
+	log.Printf("[SERVICE_SEARCH] Search complete: query=%s, mode=%s, results=%d", query, mode, len(results))
+	return &models.SearchResponse{Data: results, Mode: mode}, nil
+}
+
+// find runs the search against lists and items
+func (s *SearchService) find(ctx context.Context, filter repository.SearchFilter, includeLists bool) ([]models.List, []models.Item, error) {
+	lists := []models.List{}
+	if includeLists {
+		var err error
+		lists, err = s.repo.List.Search(ctx, filter)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	items, err := s.repo.Item.Search(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return lists, items, nil
+}
+
+// listScope returns the IDs of a top-level list and its nested lists
+func (s *SearchService) listScope(ctx context.Context, listID string) ([]string, error) {
+	exists, err := s.items.listExists(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("list not found")
+	}
+
+	items, _, err := s.repo.Item.GetByListID(ctx, listID, models.ItemQuery{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items: %w", err)
+	}
+
+	listIDs := []string{listID}
+	for _, item := range items {
+		if item.Type == "list" {
+			listIDs = append(listIDs, item.UUID)
+		}
+	}
+	return listIDs, nil
+}
+
+// searchParent resolves the list or nested list containing an item, caching lookups by list ID
+func (s *SearchService) searchParent(ctx context.Context, listID string, cache map[string]searchParent) (searchParent, error) {
+	if parent, ok := cache[listID]; ok {
+		return parent, nil
+	}
+
+	parent := searchParent{}
+	root, err := s.items.rootList(ctx, listID)
+	if err != nil {
+		return parent, err
+	}
+	parent.root = root
+
+	if root != nil {
+		parent.name = root.Name
+		if root.UUID != listID {
+			nestedList, err := s.repo.Item.GetNestedList(ctx, listID)
+			if err != nil {
+				return parent, fmt.Errorf("failed to get list: %w", err)
+			}
+			if nestedList != nil {
+				parent.name = nestedList.Name
+			}
+		}
+	}
+
+	cache[listID] = parent
+	return parent, nil
+}
+
+// isLatinText reports whether every letter of the text is in the Latin script, which the text index can stem
+func isLatinText(text string) bool {
+	for _, r := range text {
+		if unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// searchHighlights returns the matched parts of the name and description
+func searchHighlights(name string, description string, terms []string) []models.SearchHighlight {
+	highlights := []models.SearchHighlight{}
+	if ranges := highlightRanges(name, terms); len(ranges) > 0 {
+		highlights = append(highlights, models.SearchHighlight{Field: "name", Ranges: ranges})
+	}
+	if ranges := highlightRanges(description, terms); len(ranges) > 0 {
+		highlights = append(highlights, models.SearchHighlight{Field: "description", Ranges: ranges})
+	}
+	return highlights
+}
+
+// highlightRanges returns the character ranges of the text matching any of the terms, case-insensitively
+// Overlapping and adjacent matches are merged
+func highlightRanges(text string, terms []string) []models.SearchRange {
+	lowered := []rune(strings.Map(unicode.ToLower, text))
+	marked := make([]bool, len(lowered))
+	for _, term := range terms {
+		needle := []rune(strings.Map(unicode.ToLower, term))
+		for start := 0; len(needle) > 0 && start+len(needle) <= len(lowered); start++ {
+			if string(lowered[start:start+len(needle)]) == string(needle) {
+				for i := start; i < start+len(needle); i++ {
+					marked[i] = true
+				}
+			}
+		}
+	}
+
+	ranges := []models.SearchRange{}
+	for i := 0; i < len(marked); i++ {
+		if !marked[i] {
+			continue
+		}
+		start := i
+		for i < len(marked) && marked[i] {
+			i++
+		}
+		ranges = append(ranges, models.SearchRange{Start: start, End: i})
+	}
+	return ranges
+}
+
+// searchScore ranks exact name matches first, then name matches, then description matches
+func searchScore(result *models.SearchResult, query string) int {
+	name := ""
+	if result.List != nil {
+		name = result.List.Name
+	} else if result.Item != nil {
+		name = result.Item.Name
+	}
+
+	score := 0
+	if normalizeName(name, false) == normalizeName(query, false) {
+		score += 3
+	}
+	for _, highlight := range result.Highlights {
+		if highlight.Field == "name" {
+			score += 2
+		} else {
+			score++
+		}
+	}
+	return score
+}
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/repository"
+)
+
+// templateVariable matches a variable placeholder such as {date}
+var templateVariable = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// TemplateService handles template business logic
+type TemplateService struct {
+	repo  *repository.Repositories
+	items *ItemService
+	lists *ListService
+}
+
+// NewTemplateService creates a new template service
+func NewTemplateService(repo *repository.Repositories) *TemplateService {
+	return &TemplateService{
+		repo:  repo,
+		items: NewItemService(repo),
+		lists: NewListService(repo),
+	}
+}
+
+// SetWebhooks makes lists created from templates queue their items for the webhooks of their owner
+func (s *TemplateService) SetWebhooks(webhooks *WebhookService) {
+	s.items.SetWebhooks(webhooks)
+	s.lists.SetWebhooks(webhooks)
+}
+
+// GetAllTemplates retrieves the templates of the user
+func (s *TemplateService) GetAllTemplates(ctx context.Context, userID string) ([]models.TemplateResponse, error) {
+	templates, err := s.repo.Template.GetAll(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get templates: %w", err)
+	}
+
+	responses := make([]models.TemplateResponse, len(templates))
+	for i := range templates {
+		responses[i] = *mapTemplateToResponse(&templates[i])
+	}
+	return responses, nil
+}
+
+// GetTemplate retrieves a template by ID
+func (s *TemplateService) GetTemplate(ctx context.Context, templateID string) (*models.TemplateResponse, error) {
+	template, err := s.getTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	return mapTemplateToResponse(template), nil
+}
+
+// CreateTemplate creates a template from the given items, or saves an existing list as a template
+func (s *TemplateService) CreateTemplate(ctx context.Context, req *models.CreateTemplateRequest, userID string) (*models.TemplateResponse, error) {
+	template := &models.Template{
+		UUID:           uuid.New().String(),
+		Name:           req.Name,
+		Description:    req.Description,
+		Color:          req.Color,
+		CompletionMode: req.CompletionMode,
+		Items:          req.Items,
+		UserID:         userID,
+		CreatedBy:      userID,
+		UpdatedBy:      userID,
+	}
+
+	if req.ListID != "" {
+		list, err := s.repo.List.GetByID(ctx, req.ListID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get list: %w", err)
+		}
+		if list == nil {
+			return nil, fmt.Errorf("list not found")
+		}
+
+		items, err := s.templateItems(ctx, list.UUID)
+		if err != nil {
+			return nil, err
+		}
+		template.Items = items
+		if template.Name == "" {
+			template.Name = list.Name
+		}
+		if template.Description == "" {
+			template.Description = list.Description
+		}
+		if template.Color == "" {
+			template.Color = list.Color
+		}
+		if template.CompletionMode == "" {
+			template.CompletionMode = list.CompletionMode
+		}
+	}
+
+	if err := validateTemplate(template); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[SERVICE_CREATE_TEMPLATE] Creating template: name=%s, fromList=%s, userID=%s", template.Name, req.ListID, userID)
+	if err := s.repo.Template.Create(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+	return mapTemplateToResponse(template), nil
+}
+
+// UpdateTemplate updates a template; templates are versioned independently of the lists they were saved from
+func (s *TemplateService) UpdateTemplate(ctx context.Context, templateID string, req *models.UpdateTemplateRequest, userID string) (*models.TemplateResponse, error) {
+	template, err := s.getTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template.Version != req.Version {
+		log.Printf("[SERVICE_UPDATE_TEMPLATE] Version conflict: templateID=%s, requested=%d, current=%d", templateID, req.Version, template.Version)
+		return nil, fmt.Errorf("version_conflict")
+	}
+
+	template.Name = req.Name
+	template.Description = req.Description
+	template.Color = req.Color
+	if req.CompletionMode != "" {
+		template.CompletionMode = req.CompletionMode
+	}
+	if req.Items != nil {
+		template.Items = *req.Items
+	}
+	template.UpdatedBy = userID
+
+	if err := validateTemplate(template); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Template.Update(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to update template: %w", err)
+	}
+	return mapTemplateToResponse(template), nil
+}
+
+// DeleteTemplate deletes a template
+func (s *TemplateService) DeleteTemplate(ctx context.Context, templateID string, version int32) error {
+	if err := s.repo.Template.Delete(ctx, templateID, version); err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	return nil
+}
+
+// InstantiateTemplate creates a new list from a template, substituting variables in the list and item names
+func (s *TemplateService) InstantiateTemplate(ctx context.Context, templateID string, req *models.InstantiateTemplateRequest, userID string) (*models.ListResponse, error) {
+	template, err := s.getTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a date the variables take today's date in the user's time zone
+	loc, err := s.items.userLocation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	date := time.Now().In(loc)
+	if req.Date != "" {
+		date, err = time.ParseInLocation("2006-01-02", req.Date, loc)
+		if err != nil {
+			return nil, fmt.Errorf("validation_error: date must be in YYYY-MM-DD format")
+		}
+	}
+	variables := templateVariables(date, req.Variables)
+
+	name := req.Name
+	if name == "" {
+		name = template.Name
+	}
+	completionMode, err := validateCompletionMode(template.CompletionMode)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.List{
+		UUID:           uuid.New().String(),
+		Name:           substituteVariables(name, variables),
+		Description:    substituteVariables(template.Description, variables),
+		Color:          template.Color,
+		CompletionMode: completionMode,
+		UserID:         userID,
+		CreatedBy:      userID,
+		UpdatedBy:      userID,
+	}
+
+	items, nestedListIDs := templateListItems(template, list.UUID, variables, userID)
+	log.Printf("[SERVICE_INSTANTIATE_TEMPLATE] Creating list from template: templateID=%s, name=%s, items=%d", templateID, list.Name, len(items))
+	if err := s.repo.List.CreateWithItems(ctx, list, items); err != nil {
+		return nil, fmt.Errorf("failed to create list: %w", err)
+	}
+
+	s.items.refreshItemCounts(ctx, append(nestedListIDs, list.UUID)...)
+	for i := range items {
+		s.items.publishListItemEvent(ctx, models.WebhookEventItemCreated, &items[i], list, userID)
+	}
+	created, err := s.repo.List.GetByID(ctx, list.UUID, userID)
+	if err != nil || created == nil {
+		created = list
+	}
+	return s.lists.mapListToResponse(created), nil
+}
+
+// getTemplate retrieves a template, failing when it does not exist
+func (s *TemplateService) getTemplate(ctx context.Context, templateID string) (*models.Template, error) {
+	template, err := s.repo.Template.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	if template == nil {
+		return nil, fmt.Errorf("template not found")
+	}
+	return template, nil
+}
+
+// templateItems converts the items of a list and its nested lists into template items
+func (s *TemplateService) templateItems(ctx context.Context, listID string) ([]models.TemplateItem, error) {
+	items, _, err := s.repo.Item.GetByListID(ctx, listID, models.ItemQuery{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items: %w", err)
+	}
+
+	templateItems := []models.TemplateItem{}
+	for _, item := range items {
+		templateItem := models.TemplateItem{
+			Type:         item.Type,
+			Name:         item.Name,
+			Description:  item.Description,
+			Quantity:     item.Quantity,
+			QuantityType: item.QuantityType,
+		}
+		if item.Recurrence != nil {
+			templateItem.Recurrence = &models.Recurrence{Rule: item.Recurrence.Rule, TimeZone: item.Recurrence.TimeZone, AnchorDay: item.Recurrence.AnchorDay}
+		}
+		if item.Type == "list" {
+			children, err := s.templateItems(ctx, item.UUID)
+			if err != nil {
+				return nil, err
+			}
+			templateItem.Children = children
+		}
+		templateItems = append(templateItems, templateItem)
+	}
+	return templateItems, nil
+}
+
+// validateTemplate checks the template name, completion mode and the nesting of its items
+func validateTemplate(template *models.Template) error {
+	if strings.TrimSpace(template.Name) == "" {
+		return fmt.Errorf("validation_error: name is required")
+	}
+	if _, err := validateCompletionMode(template.CompletionMode); err != nil {
+		return err
+	}
+	if template.Items == nil {
+		template.Items = []models.TemplateItem{}
+	}
+	return validateTemplateItems(template.Items, 1)
+}
+
+// validateTemplateItems checks template items at the given nesting depth
+func validateTemplateItems(items []models.TemplateItem, depth int) error {
+	for i := range items {
+		item := &items[i]
+		if strings.TrimSpace(item.Name) == "" {
+			return fmt.Errorf("validation_error: item name is required")
+		}
+		switch item.Type {
+		case "item":
+			if len(item.Children) > 0 {
+				return fmt.Errorf("validation_error: only nested lists can have children")
+			}
+			if item.Recurrence != nil {
+				if err := validateRecurrence(item.Recurrence); err != nil {
+					return err
+				}
+				item.Recurrence.NextAt = nil
+			}
+		case "list":
+			if depth >= maxNestingDepth {
+				return fmt.Errorf("validation_error: nested lists cannot contain other lists")
+			}
+			if err := validateTemplateItems(item.Children, depth+1); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("validation_error: type must be item or list")
+		}
+	}
+	return nil
+}
+
+// templateListItems creates the items of a template for a list, returning them with the UUIDs of the nested lists
+func templateListItems(template *models.Template, listID string, variables map[string]string, userID string) ([]models.Item, []string) {
+	items := []models.Item{}
+	nestedListIDs := []string{}
+	for i, templateItem := range template.Items {
+		item := newTemplateListItem(templateItem, listID, int32(i), variables, userID)
+		items = append(items, item)
+		if item.Type != "list" {
+			continue
+		}
+		nestedListIDs = append(nestedListIDs, item.UUID)
+		for j, child := range templateItem.Children {
+			items = append(items, newTemplateListItem(child, item.UUID, int32(j), variables, userID))
+		}
+	}
+	return items, nestedListIDs
+}
+
+// newTemplateListItem creates a list item from a template item
+func newTemplateListItem(templateItem models.TemplateItem, listID string, order int32, variables map[string]string, userID string) models.Item {
+	var recurrence *models.Recurrence
+	if templateItem.Recurrence != nil {
+		recurrence = &models.Recurrence{Rule: templateItem.Recurrence.Rule, TimeZone: templateItem.Recurrence.TimeZone, AnchorDay: templateItem.Recurrence.AnchorDay}
+	}
+
+	return models.Item{
+		UUID:         uuid.New().String(),
+		ListID:       listID,
+		Type:         templateItem.Type,
+		Name:         substituteVariables(templateItem.Name, variables),
+		Description:  substituteVariables(templateItem.Description, variables),
+		Quantity:     templateItem.Quantity,
+		QuantityType: templateItem.QuantityType,
+		Order:        order,
+		CreatedBy:    userID,
+		UpdatedBy:    userID,
+		Recurrence:   recurrence,
+	}
+}
+
+// templateVariables returns the built-in date variables merged with custom variables
+func templateVariables(date time.Time, custom map[string]string) map[string]string {
+	_, week := date.ISOWeek()
+	variables := map[string]string{
+		"date":    date.Format("2006-01-02"),
+		"day":     strconv.Itoa(date.Day()),
+		"weekday": date.Weekday().String(),
+		"week":    strconv.Itoa(week),
+		"month":   date.Month().String(),
+		"year":    strconv.Itoa(date.Year()),
+	}
+	for name, value := range custom {
+		variables[name] = value
+	}
+	return variables
+}
+
+// substituteVariables replaces known {variable} placeholders, leaving unknown ones as they are
+func substituteVariables(text string, variables map[string]string) string {
+	return templateVariable.ReplaceAllStringFunc(text, func(placeholder string) string {
+		if value, ok := variables[placeholder[1:len(placeholder)-1]]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// mapTemplateToResponse converts a template model to a response
+func mapTemplateToResponse(template *models.Template) *models.TemplateResponse {
+	completionMode := template.CompletionMode
+	if completionMode == "" {
+		completionMode = models.CompletionModeShared
+	}
+	items := template.Items
+	if items == nil {
+		items = []models.TemplateItem{}
+	}
+
+	return &models.TemplateResponse{
+		ID:             template.UUID,
+		Name:           template.Name,
+		Description:    template.Description,
+		Color:          template.Color,
+		CompletionMode: completionMode,
+		Items:          items,
+		ItemCount:      int32(len(items)),
+		CreatedAt:      template.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:      template.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		CreatedBy:      template.CreatedBy,
+		UpdatedBy:      template.UpdatedBy,
+		Version:        template.Version,
+	}
+}
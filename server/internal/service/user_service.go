@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"net/mail"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yair12/lists-viewer/server/internal/models"
@@ -89,6 +91,8 @@ func (s *UserService) mapUserToResponse(user *models.User) *models.UserResponse
 		Username: user.Username,
 		IconID:   user.IconID,
 		Color:    user.Color,
+
+		Preferences: user.Preferences,
 	}
 }
 
@@ -129,3 +133,69 @@ func (s *UserService) UpdateUserIcon(ctx context.Context, username string, iconI
 	log.Printf("[SERVICE_UPDATE_ICON] Successfully updated icon: username=%s", username)
 	return s.mapUserToResponse(user), nil
 }
+
+// UpdatePreferences updates a user's preferences, leaving omitted fields unchanged
+func (s *UserService) UpdatePreferences(ctx context.Context, username string, req *models.UpdatePreferencesRequest) (*models.UserResponse, error) {
+	if req.Theme != "" && req.Theme != "light" && req.Theme != "dark" {
+		return nil, fmt.Errorf("validation_error: theme must be light or dark")
+	}
+	if req.TimeZone != "" {
+		if _, err := time.LoadLocation(req.TimeZone); err != nil {
+			return nil, fmt.Errorf("validation_error: unknown time zone: %s", req.TimeZone)
+		}
+	}
+	if req.Digest != "" && req.Digest != "off" && req.Digest != "daily" && req.Digest != "weekly" {
+		return nil, fmt.Errorf("validation_error: digest must be off, daily or weekly")
+	}
+	if req.Email != "" {
+		address, err := mail.ParseAddress(req.Email)
+		if err != nil {
+			return nil, fmt.Errorf("validation_error: %s is not an email address", req.Email)
+		}
+		req.Email = address.Address
+	}
+
+	user, err := s.repo.User.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if req.Theme != "" {
+		user.Preferences.Theme = req.Theme
+	}
+	if req.Language != "" {
+		user.Preferences.Language = req.Language
+	}
+	if req.TimeZone != "" {
+		user.Preferences.TimeZone = req.TimeZone
+	}
+	if req.Email != "" {
+		user.Preferences.Email = req.Email
+	}
+	optIn := false
+	switch {
+	case req.Digest == "off":
+		user.Preferences.Digest = ""
+	case req.Digest != "":
+		if user.Preferences.Email == "" {
+			return nil, fmt.Errorf("validation_error: an email address is required for digests")
+		}
+		optIn = user.Preferences.Digest == ""
+		user.Preferences.Digest = req.Digest
+	}
+
+	log.Printf("[SERVICE_UPDATE_PREFERENCES] Updating preferences: username=%s, preferences=%+v", username, user.Preferences)
+	if err := s.repo.User.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	// The first digest goes out at the next scheduled time rather than right away
+	if optIn {
+		if _, err := s.repo.User.ClaimDigest(ctx, user, time.Now()); err != nil {
+			return nil, fmt.Errorf("failed to update user: %w", err)
+		}
+	}
+	return s.mapUserToResponse(user), nil
+}
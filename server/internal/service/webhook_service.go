@@ -0,0 +1,443 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/repository"
+)
+
+const (
+	// maxDeliveryAttempts is how often a delivery is attempted before it is marked failed
+	maxDeliveryAttempts = 8
+
+	// firstRetryDelay is the wait after the first failed attempt; it doubles after every further failure
+	firstRetryDelay = 30 * time.Second
+
+	// maxRetryDelay caps the wait between attempts
+	maxRetryDelay = time.Hour
+
+	// deliveryLease is how long a claimed attempt may take before another run retries it
+	deliveryLease = 2 * time.Minute
+
+	// deliveryBatchSize is the largest number of deliveries attempted per run
+	deliveryBatchSize = 100
+
+	// defaultDeliveryLogSize and maxDeliveryLogSize bound the delivery log returned at once
+	defaultDeliveryLogSize = 50
+	maxDeliveryLogSize     = 200
+)
+
+// WebhookService handles webhook subscriptions and delivers their events from the outbox
+type WebhookService struct {
+	repo   *repository.Repositories
+	client *http.Client
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(repo *repository.Repositories) *WebhookService {
+	return &WebhookService{repo: repo, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// CreateWebhook creates a webhook owned by the caller, returning its signing secret once
+func (s *WebhookService) CreateWebhook(ctx context.Context, req *models.CreateWebhookRequest, userID string) (*models.WebhookResponse, error) {
+	events, err := s.validateWebhook(ctx, req.URL, req.ListID, req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	webhook := &models.Webhook{
+		UUID:   uuid.New().String(),
+		UserID: userID,
+		ListID: req.ListID,
+		URL:    req.URL,
+		Secret: hex.EncodeToString(secret),
+		Events: events,
+		Active: true,
+	}
+	if err := s.repo.Webhook.Create(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	log.Printf("[SERVICE_CREATE_WEBHOOK] Created webhook: uuid=%s, userID=%s, listID=%s, events=%v", webhook.UUID, userID, webhook.ListID, webhook.Events)
+	response := mapWebhookToResponse(webhook)
+	response.Secret = webhook.Secret
+	return response, nil
+}
+
+// GetWebhooks retrieves the caller's webhooks
+func (s *WebhookService) GetWebhooks(ctx context.Context, userID string) ([]models.WebhookResponse, error) {
+	webhooks, err := s.repo.Webhook.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	responses := make([]models.WebhookResponse, len(webhooks))
+	for i := range webhooks {
+		responses[i] = *mapWebhookToResponse(&webhooks[i])
+	}
+	return responses, nil
+}
+
+// GetWebhook retrieves one of the caller's webhooks
+func (s *WebhookService) GetWebhook(ctx context.Context, webhookID string, userID string) (*models.WebhookResponse, error) {
+	webhook, err := s.getOwnedWebhook(ctx, webhookID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return mapWebhookToResponse(webhook), nil
+}
+
+// UpdateWebhook updates one of the caller's webhooks; inactive webhooks queue no deliveries
+func (s *WebhookService) UpdateWebhook(ctx context.Context, webhookID string, req *models.UpdateWebhookRequest, userID string) (*models.WebhookResponse, error) {
+	events, err := s.validateWebhook(ctx, req.URL, req.ListID, req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.getOwnedWebhook(ctx, webhookID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.Version != req.Version {
+		return nil, fmt.Errorf("version_conflict")
+	}
+
+	webhook.URL = req.URL
+	webhook.ListID = req.ListID
+	webhook.Events = events
+	webhook.Active = req.Active
+	if err := s.repo.Webhook.Update(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return mapWebhookToResponse(webhook), nil
+}
+
+// DeleteWebhook deletes one of the caller's webhooks together with its delivery log
+func (s *WebhookService) DeleteWebhook(ctx context.Context, webhookID string, version int32, userID string) error {
+	if _, err := s.getOwnedWebhook(ctx, webhookID, userID); err != nil {
+		return err
+	}
+	if err := s.repo.Webhook.Delete(ctx, webhookID, version); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if err := s.repo.Delivery.DeleteByWebhookID(ctx, webhookID); err != nil {
+		log.Printf("[SERVICE_DELETE_WEBHOOK] Failed to delete deliveries: webhookID=%s, error=%v", webhookID, err)
+	}
+	return nil
+}
+
+// GetDeliveries retrieves the delivery log of one of the caller's webhooks, newest first
+func (s *WebhookService) GetDeliveries(ctx context.Context, webhookID string, limit int, userID string) ([]models.WebhookDeliveryResponse, error) {
+	if _, err := s.getOwnedWebhook(ctx, webhookID, userID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultDeliveryLogSize
+	}
+	limit = min(limit, maxDeliveryLogSize)
+
+	deliveries, err := s.repo.Delivery.GetByWebhookID(ctx, webhookID, int64(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deliveries: %w", err)
+	}
+
+	responses := make([]models.WebhookDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		responses[i] = *mapDeliveryToResponse(&deliveries[i])
+	}
+	return responses, nil
+}
+
+// Redeliver queues a new delivery of the same event as an earlier delivery, with a fresh set of attempts
+func (s *WebhookService) Redeliver(ctx context.Context, webhookID string, deliveryID string, userID string) (*models.WebhookDeliveryResponse, error) {
+	if _, err := s.getOwnedWebhook(ctx, webhookID, userID); err != nil {
+		return nil, err
+	}
+	original, err := s.repo.Delivery.GetByID(ctx, webhookID, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delivery: %w", err)
+	}
+	if original == nil {
+		return nil, fmt.Errorf("delivery not found")
+	}
+
+	delivery := newDelivery(webhookID, original.EventID, original.Event, original.Payload, time.Now())
+	delivery.RedeliveryOf = original.UUID
+	if err := s.repo.Delivery.CreateMany(ctx, []models.WebhookDelivery{delivery}); err != nil {
+		return nil, fmt.Errorf("failed to queue delivery: %w", err)
+	}
+
+	log.Printf("[SERVICE_REDELIVER_WEBHOOK] Queued redelivery: webhookID=%s, original=%s, uuid=%s", webhookID, original.UUID, delivery.UUID)
+	return mapDeliveryToResponse(&delivery), nil
+}
+
+// Enqueue queues an event for every active webhook covering the list and subscribed to the event type
+// list is the top-level list of the changed item
+func (s *WebhookService) Enqueue(ctx context.Context, list *models.List, event *models.WebhookEvent) error {
+	webhooks, err := s.repo.Webhook.GetForList(ctx, list.UUID, listUsers(list))
+	if err != nil {
+		return fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	matching := slices.DeleteFunc(webhooks, func(webhook models.Webhook) bool {
+		return len(webhook.Events) > 0 && !slices.Contains(webhook.Events, event.Type)
+	})
+	if len(matching) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := make([]models.WebhookDelivery, len(matching))
+	for i, webhook := range matching {
+		deliveries[i] = newDelivery(webhook.UUID, event.ID, event.Type, string(payload), now)
+	}
+	return s.repo.Delivery.CreateMany(ctx, deliveries)
+}
+
+// DeliverDue attempts every pending delivery whose time has come
+// Failed attempts are retried with exponential backoff until maxDeliveryAttempts is reached
+func (s *WebhookService) DeliverDue(ctx context.Context, now time.Time) error {
+	deliveries, err := s.repo.Delivery.GetDue(ctx, now, deliveryBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get due deliveries: %w", err)
+	}
+
+	webhooks := map[string]*models.Webhook{}
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		// The lease starts now rather than at the start of the run, which earlier attempts may have delayed
+		// Stored times have millisecond precision, and the lease identifies the claim when the attempt is recorded
+		lease := time.Now().Add(deliveryLease).Truncate(time.Millisecond)
+		claimed, err := s.repo.Delivery.Claim(ctx, delivery, lease)
+		if err != nil || !claimed {
+			continue
+		}
+
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			if webhook, err = s.repo.Webhook.GetByID(ctx, delivery.WebhookID); err != nil {
+				log.Printf("[SERVICE_DELIVER_WEBHOOKS] Failed to get webhook: webhookID=%s, error=%v", delivery.WebhookID, err)
+				continue
+			}
+			webhooks[delivery.WebhookID] = webhook
+		}
+
+		s.attempt(ctx, webhook, delivery, now)
+		recorded, err := s.repo.Delivery.RecordAttempt(ctx, delivery, lease)
+		if err != nil {
+			log.Printf("[SERVICE_DELIVER_WEBHOOKS] Failed to record attempt: uuid=%s, error=%v", delivery.UUID, err)
+		} else if !recorded {
+			log.Printf("[SERVICE_DELIVER_WEBHOOKS] Claim expired before the attempt was recorded: uuid=%s", delivery.UUID)
+		}
+	}
+	return nil
+}
+
+// attempt posts a delivery once and updates it with the outcome
+func (s *WebhookService) attempt(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery, now time.Time) {
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.LastStatusCode = 0
+	delivery.LastError = ""
+
+	var err error
+	switch {
+	case webhook == nil:
+		err = fmt.Errorf("webhook was deleted")
+	case !webhook.Active:
+		err = fmt.Errorf("webhook is inactive")
+	default:
+		delivery.LastStatusCode, err = s.post(ctx, webhook, delivery, now)
+	}
+
+	if err == nil {
+		delivery.Status = models.DeliveryStatusDelivered
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= maxDeliveryAttempts || webhook == nil {
+		log.Printf("[SERVICE_DELIVER_WEBHOOKS] Delivery failed permanently: uuid=%s, attempts=%d, error=%v", delivery.UUID, delivery.Attempts, err)
+		delivery.Status = models.DeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+		return
+	}
+	next := now.Add(retryDelay(delivery.Attempts))
+	delivery.NextAttemptAt = &next
+}
+
+// post sends a delivery signed with the webhook's secret, failing on non-2xx responses
+// The signature is the hex HMAC-SHA256 of "<timestamp>.<body>", so receivers can reject replayed requests
+func (s *WebhookService) post(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery, now time.Time) (int, error) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader([]byte(delivery.Payload)))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "lists-viewer-webhooks")
+	req.Header.Set("X-Webhook-Id", delivery.UUID)
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhook(webhook.Secret, timestamp, []byte(delivery.Payload)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhook returns the signature of a webhook body sent at the given Unix timestamp
+func SignWebhook(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryDelay returns the wait after the given number of failed attempts
+func retryDelay(attempts int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// validateWebhook checks a webhook's URL, list and event types, returning the event types without duplicates
+func (s *WebhookService) validateWebhook(ctx context.Context, rawURL string, listID string, events []string) ([]string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("validation_error: url must be an http or https URL")
+	}
+
+	if listID != "" {
+		list, err := s.repo.List.GetByID(ctx, listID, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get list: %w", err)
+		}
+		if list == nil {
+			return nil, fmt.Errorf("list not found")
+		}
+	}
+
+	unique := []string{}
+	for _, event := range events {
+		if !slices.Contains(models.WebhookEventTypes, event) {
+			return nil, fmt.Errorf("validation_error: unknown event type %s", event)
+		}
+		if !slices.Contains(unique, event) {
+			unique = append(unique, event)
+		}
+	}
+	return unique, nil
+}
+
+// getOwnedWebhook loads a webhook of the caller; other users' webhooks are reported as not found
+func (s *WebhookService) getOwnedWebhook(ctx context.Context, webhookID string, userID string) (*models.Webhook, error) {
+	webhook, err := s.repo.Webhook.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if webhook == nil || webhook.UserID != userID {
+		return nil, fmt.Errorf("webhook not found")
+	}
+	return webhook, nil
+}
+
+// listUsers returns the owner and members of a list
+func listUsers(list *models.List) []string {
+	users := []string{}
+	for _, userID := range append([]string{list.UserID}, list.Members...) {
+		if userID != "" && !slices.Contains(users, userID) {
+			users = append(users, userID)
+		}
+	}
+	return users
+}
+
+// newDelivery creates a pending delivery of an event, due at the given time
+func newDelivery(webhookID string, eventID string, eventType string, payload string, now time.Time) models.WebhookDelivery {
+	return models.WebhookDelivery{
+		UUID:          uuid.New().String(),
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		Event:         eventType,
+		Payload:       payload,
+		Status:        models.DeliveryStatusPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+	}
+}
+
+// mapWebhookToResponse converts a Webhook model to a WebhookResponse, without its secret
+func mapWebhookToResponse(webhook *models.Webhook) *models.WebhookResponse {
+	events := webhook.Events
+	if events == nil {
+		events = []string{}
+	}
+	return &models.WebhookResponse{
+		ID:        webhook.UUID,
+		URL:       webhook.URL,
+		ListID:    webhook.ListID,
+		Events:    events,
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt: webhook.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		Version:   webhook.Version,
+	}
+}
+
+// mapDeliveryToResponse converts a WebhookDelivery model to a WebhookDeliveryResponse
+func mapDeliveryToResponse(delivery *models.WebhookDelivery) *models.WebhookDeliveryResponse {
+	return &models.WebhookDeliveryResponse{
+		ID:             delivery.UUID,
+		EventID:        delivery.EventID,
+		Event:          delivery.Event,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  formatOptionalTime(delivery.NextAttemptAt),
+		LastAttemptAt:  formatOptionalTime(delivery.LastAttemptAt),
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		RedeliveryOf:   delivery.RedeliveryOf,
+		CreatedAt:      delivery.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		DeliveredAt:    formatOptionalTime(delivery.DeliveredAt),
+		Payload:        delivery.Payload,
+	}
+}
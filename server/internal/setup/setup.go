@@ -1,35 +1,63 @@
 package setup
 
 import (
+	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/yair12/lists-viewer/server/internal/api"
 	"github.com/yair12/lists-viewer/server/internal/api/handler"
+	"github.com/yair12/lists-viewer/server/internal/config"
+	"github.com/yair12/lists-viewer/server/internal/mailer"
+	"github.com/yair12/lists-viewer/server/internal/notify"
 	"github.com/yair12/lists-viewer/server/internal/repository"
+	"github.com/yair12/lists-viewer/server/internal/scheduler"
 	"github.com/yair12/lists-viewer/server/internal/service"
+	"github.com/yair12/lists-viewer/server/internal/smtpd"
+	"github.com/yair12/lists-viewer/server/internal/webpush"
 )
 
+// databaseName is the MongoDB database used by the server
+const databaseName = "lists_viewer"
+
 // SetupRouter initializes and configures the Gorilla Mux router with all handlers
-func SetupRouter(dbClient *mongo.Client) http.Handler {
+func SetupRouter(dbClient *mongo.Client, cfg *config.Config) http.Handler {
 	log.Printf("[SETUP] Initializing router and dependencies...")
 	router := mux.NewRouter()
 
 	// Get database
-	db := dbClient.Database("lists_viewer")
+	db := dbClient.Database(databaseName)
 
 	// Initialize repositories
 	repos := repository.NewRepositories(db)
 
+	// Ensure indexes; search falls back to substring matching if the text index is missing
+	indexCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := repository.EnsureIndexes(indexCtx, db); err != nil {
+		log.Printf("[SETUP] Failed to ensure indexes: %v", err)
+	}
+
 	// Initialize services
+	webhookService := service.NewWebhookService(repos)
 	listService := service.NewListService(repos)
+	listService.SetWebhooks(webhookService)
 	itemService := service.NewItemService(repos)
+	itemService.SetNotifier(setupNotifier(repos, cfg))
+	itemService.SetWebhooks(webhookService)
 	userService := service.NewUserService(repos)
+	searchService := service.NewSearchService(repos)
+	templateService := service.NewTemplateService(repos)
+	templateService.SetWebhooks(webhookService)
+	pushService := service.NewPushService(repos, cfg.VAPIDKeys)
+	inboundService := service.NewInboundService(repos, itemService)
 	healthService := service.NewHealthService(dbClient)
 
 	// Initialize handlers
@@ -37,6 +65,13 @@ func SetupRouter(dbClient *mongo.Client) http.Handler {
 	listHandler := handler.NewListHandler(listService)
 	itemHandler := handler.NewItemHandler(itemService)
 	userHandler := handler.NewUserHandler(userService)
+	unitHandler := handler.NewUnitHandler()
+	viewHandler := handler.NewViewHandler(itemService)
+	searchHandler := handler.NewSearchHandler(searchService)
+	templateHandler := handler.NewTemplateHandler(templateService)
+	pushHandler := handler.NewPushHandler(pushService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	inboundHandler := handler.NewInboundHandler(inboundService)
 
 	// Health check endpoints (root level)
 	router.HandleFunc("/health/live", healthHandler.LivenessProbe).Methods("GET")
@@ -51,7 +86,9 @@ func SetupRouter(dbClient *mongo.Client) http.Handler {
 	// User/Icon endpoints
 	api1.HandleFunc("/users/init", userHandler.InitUser).Methods("POST")
 	api1.HandleFunc("/users/{username}/icon", userHandler.UpdateUserIcon).Methods("PATCH")
+	api1.HandleFunc("/users/{username}/preferences", userHandler.UpdatePreferences).Methods("PATCH")
 	api1.HandleFunc("/icons", userHandler.GetIcons).Methods("GET")
+	api1.HandleFunc("/units", unitHandler.GetUnits).Methods("GET")
 
 	// List CRUD endpoints
 	api1.HandleFunc("/lists", listHandler.GetAllLists).Methods("GET")
@@ -59,13 +96,65 @@ func SetupRouter(dbClient *mongo.Client) http.Handler {
 	api1.HandleFunc("/lists/{id}", listHandler.GetList).Methods("GET")
 	api1.HandleFunc("/lists/{id}", listHandler.UpdateList).Methods("PUT")
 	api1.HandleFunc("/lists/{id}", listHandler.DeleteList).Methods("DELETE")
+	api1.HandleFunc("/lists/{id}/demote", listHandler.DemoteList).Methods("POST")
+	api1.HandleFunc("/lists/{id}/clone", listHandler.CloneList).Methods("POST")
+
+	// Scheduled list resets
+	api1.HandleFunc("/lists/{id}/schedule", listHandler.SetResetSchedule).Methods("PUT")
+	api1.HandleFunc("/lists/{id}/schedule", listHandler.DeleteResetSchedule).Methods("DELETE")
+	api1.HandleFunc("/lists/{id}/reset", listHandler.ResetList).Methods("POST")
+	api1.HandleFunc("/lists/{id}/resets", listHandler.GetResetHistory).Methods("GET")
+
+	// Template endpoints
+	api1.HandleFunc("/templates", templateHandler.GetAllTemplates).Methods("GET")
+	api1.HandleFunc("/templates", templateHandler.CreateTemplate).Methods("POST")
+	api1.HandleFunc("/templates/{id}", templateHandler.GetTemplate).Methods("GET")
+	api1.HandleFunc("/templates/{id}", templateHandler.UpdateTemplate).Methods("PUT")
+	api1.HandleFunc("/templates/{id}", templateHandler.DeleteTemplate).Methods("DELETE")
+	api1.HandleFunc("/templates/{id}/instantiate", templateHandler.InstantiateTemplate).Methods("POST")
+
+	// Push notification subscriptions
+	api1.HandleFunc("/push/key", pushHandler.GetPublicKey).Methods("GET")
+	api1.HandleFunc("/push/subscriptions", pushHandler.GetSubscriptions).Methods("GET")
+	api1.HandleFunc("/push/subscriptions", pushHandler.RegisterSubscription).Methods("POST")
+	api1.HandleFunc("/push/subscriptions/{id}", pushHandler.DeleteSubscription).Methods("DELETE")
+
+	// Outbound webhooks
+	api1.HandleFunc("/webhooks", webhookHandler.GetWebhooks).Methods("GET")
+	api1.HandleFunc("/webhooks", webhookHandler.CreateWebhook).Methods("POST")
+	api1.HandleFunc("/webhooks/{id}", webhookHandler.GetWebhook).Methods("GET")
+	api1.HandleFunc("/webhooks/{id}", webhookHandler.UpdateWebhook).Methods("PUT")
+	api1.HandleFunc("/webhooks/{id}", webhookHandler.DeleteWebhook).Methods("DELETE")
+	api1.HandleFunc("/webhooks/{id}/deliveries", webhookHandler.GetDeliveries).Methods("GET")
+	api1.HandleFunc("/webhooks/{id}/deliveries/{deliveryId}/redeliver", webhookHandler.Redeliver).Methods("POST")
+
+	// Inbound quick-add for voice assistants and shortcuts, authorized by token instead of X-User-Id
+	api1.HandleFunc("/inbound/quick-add", inboundHandler.QuickAdd).Methods("POST")
+	api1.HandleFunc("/inbound/tokens", inboundHandler.GetTokens).Methods("GET")
+	api1.HandleFunc("/inbound/tokens", inboundHandler.CreateToken).Methods("POST")
+	api1.HandleFunc("/inbound/tokens/{id}", inboundHandler.DeleteToken).Methods("DELETE")
+
+	// Search across lists
+	api1.HandleFunc("/search", searchHandler.Search).Methods("GET")
+
+	// Views across multiple lists
+	api1.HandleFunc("/views/shopping", viewHandler.GetShoppingView).Methods("GET")
+	api1.HandleFunc("/views/shopping/complete", viewHandler.CompleteShoppingEntry).Methods("PATCH")
+	api1.HandleFunc("/views/overdue", viewHandler.GetOverdueItems).Methods("GET")
+	api1.HandleFunc("/views/due-soon", viewHandler.GetDueSoonItems).Methods("GET")
 
 	// Item endpoints - register static paths before dynamic {itemId} paths
 	itemsRouter := api1.PathPrefix("/lists/{listId}/items").Subrouter()
 
 	// Item bulk operations (static paths - must come first!)
+	itemsRouter.HandleFunc("/recent", itemHandler.GetRecentlyCompletedItems).Methods("GET")
+	itemsRouter.HandleFunc("/duplicates", itemHandler.GetDuplicateItems).Methods("GET")
+	itemsRouter.HandleFunc("/merge", itemHandler.MergeItems).Methods("POST")
+	itemsRouter.HandleFunc("/quick", itemHandler.QuickAddItem).Methods("POST")
 	itemsRouter.HandleFunc("/reorder", itemHandler.ReorderItems).Methods("PATCH")
 	itemsRouter.HandleFunc("/complete", itemHandler.BulkCompleteItems).Methods("PATCH")
+	itemsRouter.HandleFunc("/uncomplete", itemHandler.BulkUncompleteItems).Methods("PATCH")
+	itemsRouter.HandleFunc("/move", itemHandler.BulkMoveItems).Methods("PATCH")
 	itemsRouter.HandleFunc("/completed", itemHandler.DeleteCompletedItems).Methods("DELETE")
 
 	// Specific item operations (dynamic paths - come after static paths)
@@ -73,10 +162,18 @@ func SetupRouter(dbClient *mongo.Client) http.Handler {
 	itemsRouter.HandleFunc("/{itemId}", itemHandler.UpdateItem).Methods("PUT")
 	itemsRouter.HandleFunc("/{itemId}", itemHandler.DeleteItem).Methods("DELETE")
 	itemsRouter.HandleFunc("/{itemId}/move", itemHandler.MoveItem).Methods("PATCH")
+	itemsRouter.HandleFunc("/{itemId}/fulfill", itemHandler.FulfillItem).Methods("PATCH")
+	itemsRouter.HandleFunc("/{itemId}/convert", itemHandler.ConvertItem).Methods("PATCH")
+	itemsRouter.HandleFunc("/{itemId}/recurrence", itemHandler.SetRecurrence).Methods("PUT")
+	itemsRouter.HandleFunc("/{itemId}/recurrence", itemHandler.DeleteRecurrence).Methods("DELETE")
+	itemsRouter.HandleFunc("/{itemId}/due", itemHandler.SetDueDate).Methods("PUT")
+	itemsRouter.HandleFunc("/{itemId}/due", itemHandler.DeleteDueDate).Methods("DELETE")
+	itemsRouter.HandleFunc("/{itemId}/promote", listHandler.PromoteNestedList).Methods("POST")
 
 	// General item collection endpoints (no path suffix)
 	itemsRouter.HandleFunc("", itemHandler.GetItemsByList).Methods("GET")
 	itemsRouter.HandleFunc("", itemHandler.CreateItem).Methods("POST")
+	itemsRouter.HandleFunc("", itemHandler.BulkUpdateItems).Methods("PATCH")
 	itemsRouter.HandleFunc("", itemHandler.BulkDeleteItems).Methods("DELETE")
 
 	// Serve static files from public directory
@@ -102,3 +199,60 @@ func SetupRouter(dbClient *mongo.Client) http.Handler {
 	// Apply CORS middleware to all routes
 	return api.CorsMiddleware(router)
 }
+
+// SetupScheduler creates the scheduler running the background jobs; the caller starts it
+func SetupScheduler(dbClient *mongo.Client, cfg *config.Config) *scheduler.Scheduler {
+	repos := repository.NewRepositories(dbClient.Database(databaseName))
+	webhookService := service.NewWebhookService(repos)
+	listService := service.NewListService(repos)
+	listService.SetWebhooks(webhookService)
+	itemService := service.NewItemService(repos)
+	itemService.SetWebhooks(webhookService)
+
+	// Reminders are also posted to a webhook when one is configured
+	notifiers := setupNotifier(repos, cfg)
+	if cfg.ReminderWebhookURL != "" {
+		notifiers = append(notifiers, notify.NewWebhookNotifier(cfg.ReminderWebhookURL))
+	}
+	reminderService := service.NewReminderService(repos, notifiers)
+
+	jobs := []scheduler.Job{
+		{Name: "list_resets", Interval: time.Minute, Run: listService.RunDueResets},
+		{Name: "item_recurrences", Interval: time.Minute, Run: itemService.ReopenRecurringItems},
+		{Name: "reminders", Interval: time.Minute, Run: reminderService.SendDueReminders},
+		{Name: "webhook_deliveries", Interval: 10 * time.Second, Run: webhookService.DeliverDue},
+	}
+
+	// Email digests need a relay to send through
+	if cfg.SMTPRelayHost != "" {
+		relay := mailer.New(net.JoinHostPort(cfg.SMTPRelayHost, cfg.SMTPRelayPort), cfg.SMTPRelayUsername, cfg.SMTPRelayPassword, cfg.MailFrom)
+		digestService := service.NewDigestService(repos, relay)
+		jobs = append(jobs, scheduler.Job{Name: "digests", Interval: 5 * time.Minute, Run: digestService.SendDueDigests})
+	}
+
+	return scheduler.New(jobs...)
+}
+
+// SetupMailServer creates the SMTP listener adding items from mail sent to list addresses, or nil when it is disabled
+// The caller starts it
+func SetupMailServer(dbClient *mongo.Client, cfg *config.Config) *smtpd.Server {
+	if cfg.SMTPPort == "" {
+		return nil
+	}
+
+	repos := repository.NewRepositories(dbClient.Database(databaseName))
+	itemService := service.NewItemService(repos)
+	itemService.SetNotifier(setupNotifier(repos, cfg))
+	itemService.SetWebhooks(service.NewWebhookService(repos))
+	return smtpd.NewServer(":"+cfg.SMTPPort, cfg.SMTPHostname, service.NewMailService(repos, itemService))
+}
+
+// setupNotifier creates the notifier for user notifications, which are logged and, when VAPID keys are configured,
+// pushed to the user's registered devices
+func setupNotifier(repos *repository.Repositories, cfg *config.Config) notify.Multi {
+	notifier := notify.Multi{notify.LogNotifier{}}
+	if cfg.VAPIDKeys != nil {
+		notifier = append(notifier, notify.NewPushNotifier(repos.Push, webpush.NewClient(cfg.VAPIDKeys, cfg.VAPIDSubject)))
+	}
+	return notifier
+}
@@ -0,0 +1,374 @@
+// Package smtpd is a minimal SMTP server (RFC 5321) for receiving mail addressed to the application
+// It supports what mail transfer agents need to hand over messages: no authentication, TLS or relaying
+package smtpd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/textproto"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Backend decides which recipients are accepted and receives the messages sent to them
+type Backend interface {
+	// Recipient is called for every RCPT command; an error rejects the recipient
+	Recipient(ctx context.Context, from string, to string) error
+
+	// Deliver is called with the raw message once the DATA command completes
+	Deliver(ctx context.Context, from string, to []string, data []byte) error
+}
+
+// RejectError is a permanent failure reported to the client with a 5xx reply
+// Other backend errors are reported as temporary, so the sending server tries again later
+type RejectError struct {
+	Message string
+}
+
+func (e *RejectError) Error() string {
+	return e.Message
+}
+
+// Reject returns a permanent failure with the given message
+func Reject(format string, args ...any) error {
+	return &RejectError{Message: fmt.Sprintf(format, args...)}
+}
+
+// Server accepts SMTP connections and hands the received messages to its backend
+type Server struct {
+	Addr            string // TCP address to listen on, e.g. ":2525"
+	Hostname        string // Name the server greets clients with
+	Backend         Backend
+	MaxLineLength   int           // Longest command line, including its line ending
+	MaxMessageBytes int64         // Largest accepted message
+	MaxRecipients   int           // Most recipients of one message
+	Timeout         time.Duration // Longest wait for the next command or data
+
+	mu       sync.Mutex
+	listener net.Listener
+	closed   bool
+}
+
+// NewServer creates a server listening on addr with default limits
+func NewServer(addr string, hostname string, backend Backend) *Server {
+	return &Server{
+		Addr:            addr,
+		Hostname:        hostname,
+		Backend:         backend,
+		MaxLineLength:   1000,
+		MaxMessageBytes: 10 << 20,
+		MaxRecipients:   50,
+		Timeout:         5 * time.Minute,
+	}
+}
+
+// ErrServerClosed is returned by Serve and ListenAndServe after Close
+var ErrServerClosed = errors.New("smtpd: server closed")
+
+// errLineTooLong is returned for command lines longer than the server's limit
+var errLineTooLong = errors.New("smtpd: line too long")
+
+// ListenAndServe listens on the server's address and serves connections until Close is called
+func (s *Server) ListenAndServe() error {
+	l, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on the listener until Close is called
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		l.Close()
+		return ErrServerClosed
+	}
+	s.listener = l
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// Close stops accepting connections; sessions in progress finish or time out on their own
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.listener != nil {
+		return s.listener.Close()
+	}
+	return nil
+}
+
+// session is the state of one SMTP connection
+type session struct {
+	server *Server
+	conn   net.Conn
+	reader *bufio.Reader
+	helo   string
+	from   string
+	to     []string
+	inMail bool
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	sess := &session{
+		server: s,
+		conn:   conn,
+		reader: bufio.NewReaderSize(conn, s.MaxLineLength),
+	}
+
+	sess.reply(220, "%s ESMTP ready", s.Hostname)
+	for {
+		conn.SetDeadline(time.Now().Add(s.Timeout))
+		line, err := sess.readLine()
+		if errors.Is(err, errLineTooLong) {
+			sess.reply(500, "5.5.2 Line too long")
+			continue
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Printf("[SMTPD] Connection error: remote=%s, error=%v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+		if !sess.handle(strings.ToUpper(verb), strings.TrimSpace(arg)) {
+			return
+		}
+	}
+}
+
+// readLine reads a command line without its line ending
+// Lines longer than the server's limit are skipped and reported with errLineTooLong, so they are never buffered whole
+func (sess *session) readLine() (string, error) {
+	line, err := sess.reader.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		for errors.Is(err, bufio.ErrBufferFull) {
+			_, err = sess.reader.ReadSlice('\n')
+		}
+		if err != nil {
+			return "", err
+		}
+		return "", errLineTooLong
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+// handle runs one command, reporting whether the session continues
+func (sess *session) handle(verb string, arg string) bool {
+	switch verb {
+	case "HELO":
+		sess.helo = arg
+		sess.reset()
+		sess.reply(250, "%s", sess.server.Hostname)
+	case "EHLO":
+		sess.helo = arg
+		sess.reset()
+		sess.reply(250, "%s\n8BITMIME\nPIPELINING\nSIZE %d", sess.server.Hostname, sess.server.MaxMessageBytes)
+	case "MAIL":
+		sess.mail(arg)
+	case "RCPT":
+		sess.rcpt(arg)
+	case "DATA":
+		return sess.data()
+	case "RSET":
+		sess.reset()
+		sess.reply(250, "2.0.0 OK")
+	case "NOOP":
+		sess.reply(250, "2.0.0 OK")
+	case "VRFY":
+		sess.reply(252, "2.5.0 Cannot verify the user")
+	case "QUIT":
+		sess.reply(221, "2.0.0 Bye")
+		return false
+	default:
+		sess.reply(502, "5.5.2 Command not implemented")
+	}
+	return true
+}
+
+// mail starts a transaction: MAIL FROM:<address> [SIZE=n] [BODY=8BITMIME]
+func (sess *session) mail(arg string) {
+	if sess.helo == "" {
+		sess.reply(503, "5.5.1 Send HELO or EHLO first")
+		return
+	}
+	if sess.inMail {
+		sess.reply(503, "5.5.1 Nested MAIL command")
+		return
+	}
+
+	from, params, ok := parsePath(arg, "FROM:")
+	if !ok {
+		sess.reply(501, "5.5.4 Syntax: MAIL FROM:<address>")
+		return
+	}
+	for _, param := range params {
+		name, value, _ := strings.Cut(param, "=")
+		if strings.EqualFold(name, "SIZE") {
+			if size, err := strconv.ParseInt(value, 10, 64); err == nil && size > sess.server.MaxMessageBytes {
+				sess.reply(552, "5.3.4 Message too big")
+				return
+			}
+		}
+	}
+
+	sess.from = from
+	sess.inMail = true
+	sess.reply(250, "2.1.0 OK")
+}
+
+// rcpt adds a recipient: RCPT TO:<address>
+func (sess *session) rcpt(arg string) {
+	if !sess.inMail {
+		sess.reply(503, "5.5.1 Send MAIL first")
+		return
+	}
+	to, _, ok := parsePath(arg, "TO:")
+	if !ok || to == "" {
+		sess.reply(501, "5.5.4 Syntax: RCPT TO:<address>")
+		return
+	}
+	// A repeated recipient is accepted once, so the message is delivered to it once
+	if slices.ContainsFunc(sess.to, func(address string) bool { return strings.EqualFold(address, to) }) {
+		sess.reply(250, "2.1.5 OK")
+		return
+	}
+	if len(sess.to) >= sess.server.MaxRecipients {
+		sess.reply(452, "4.5.3 Too many recipients")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sess.server.Timeout)
+	defer cancel()
+	if err := sess.server.Backend.Recipient(ctx, sess.from, to); err != nil {
+		sess.replyError(err, "5.7.1")
+		return
+	}
+
+	sess.to = append(sess.to, to)
+	sess.reply(250, "2.1.5 OK")
+}
+
+// data reads the message and delivers it, reporting whether the session continues
+func (sess *session) data() bool {
+	if len(sess.to) == 0 {
+		sess.reply(503, "5.5.1 Send RCPT first")
+		return true
+	}
+	sess.reply(354, "End data with <CR><LF>.<CR><LF>")
+
+	limit := sess.server.MaxMessageBytes
+	body := textproto.NewReader(sess.reader).DotReader()
+	message, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		log.Printf("[SMTPD] Failed to read message: remote=%s, error=%v", sess.conn.RemoteAddr(), err)
+		return false
+	}
+	if int64(len(message)) > limit {
+		// Skip the rest of the message so the connection stays usable
+		io.Copy(io.Discard, body)
+		sess.reset()
+		sess.reply(552, "5.3.4 Message too big")
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sess.server.Timeout)
+	defer cancel()
+	err = sess.server.Backend.Deliver(ctx, sess.from, sess.to, message)
+	sess.reset()
+	if err != nil {
+		sess.replyError(err, "5.6.0")
+		return true
+	}
+	sess.reply(250, "2.0.0 OK: queued")
+	return true
+}
+
+// reset aborts the current transaction
+func (sess *session) reset() {
+	sess.from = ""
+	sess.to = nil
+	sess.inMail = false
+}
+
+// reply writes a possibly multi-line reply; lines of the message are separated by \n
+func (sess *session) reply(code int, format string, args ...any) {
+	lines := strings.Split(fmt.Sprintf(format, args...), "\n")
+	w := bufio.NewWriter(sess.conn)
+	for i, line := range lines {
+		separator := "-"
+		if i == len(lines)-1 {
+			separator = " "
+		}
+		fmt.Fprintf(w, "%d%s%s\r\n", code, separator, line)
+	}
+	w.Flush()
+}
+
+// replyError reports a backend error, permanently for rejections and temporarily otherwise
+func (sess *session) replyError(err error, status string) {
+	var reject *RejectError
+	if errors.As(err, &reject) {
+		sess.reply(550, "%s %s", status, reject.Message)
+		return
+	}
+	log.Printf("[SMTPD] Backend error: remote=%s, error=%v", sess.conn.RemoteAddr(), err)
+	sess.reply(451, "4.3.0 Temporary failure, try again later")
+}
+
+// parsePath parses "FROM:<address> PARAM=value ..." into the address and its parameters
+// The null sender "<>" yields an empty address
+func parsePath(arg string, prefix string) (string, []string, bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", nil, false
+	}
+	fields := strings.Fields(strings.TrimSpace(arg[len(prefix):]))
+	if len(fields) == 0 {
+		return "", nil, false
+	}
+
+	path := fields[0]
+	if !strings.HasPrefix(path, "<") || !strings.HasSuffix(path, ">") {
+		return "", nil, false
+	}
+	address := path[1 : len(path)-1]
+	// Source routes ("<@relay:user@host>") are obsolete; only the mailbox counts
+	if i := strings.LastIndex(address, ":"); strings.HasPrefix(address, "@") && i >= 0 {
+		address = address[i+1:]
+	}
+	return address, fields[1:], true
+}
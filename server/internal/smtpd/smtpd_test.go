@@ -0,0 +1,166 @@
+package smtpd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/textproto"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testBackend accepts every recipient but nobody@ (permanently) and later@ (temporarily), recording delivered messages
+type testBackend struct {
+	to      [][]string
+	message []string
+}
+
+func (b *testBackend) Recipient(ctx context.Context, from string, to string) error {
+	switch {
+	case strings.HasPrefix(to, "nobody@"):
+		return Reject("no such mailbox")
+	case strings.HasPrefix(to, "later@"):
+		return errors.New("database unavailable")
+	}
+	return nil
+}
+
+func (b *testBackend) Deliver(ctx context.Context, from string, to []string, data []byte) error {
+	b.to = append(b.to, to)
+	b.message = append(b.message, string(data))
+	return nil
+}
+
+// step is a line sent by the client and the reply code expected for it
+type step struct {
+	send string
+	code int
+}
+
+func TestSession(t *testing.T) {
+	hello := []step{{"EHLO client.test", 250}, {"MAIL FROM:<me@example.com>", 250}}
+	tests := []struct {
+		name  string
+		steps []step
+		want  [][]string // Recipients of each delivered message
+	}{
+		{
+			"Deliver a message",
+			append(hello, step{"RCPT TO:<a@lists.test>", 250}, step{"DATA", 354}, step{"Subject: hi\r\n\r\nmilk\r\n.", 250}, step{"QUIT", 221}),
+			[][]string{{"a@lists.test"}},
+		},
+		{
+			"Repeated recipients are delivered to once",
+			append(hello, step{"RCPT TO:<a@lists.test>", 250}, step{"RCPT TO:<A@Lists.test>", 250}, step{"RCPT TO:<b@lists.test>", 250}, step{"DATA", 354}, step{"\r\nmilk\r\n.", 250}),
+			[][]string{{"a@lists.test", "b@lists.test"}},
+		},
+		{
+			"Rejected recipients are left out",
+			append(hello, step{"RCPT TO:<nobody@lists.test>", 550}, step{"RCPT TO:<later@lists.test>", 451}, step{"RCPT TO:<a@lists.test>", 250}, step{"DATA", 354}, step{"\r\nmilk\r\n.", 250}),
+			[][]string{{"a@lists.test"}},
+		},
+		{
+			"Too many recipients",
+			append(hello, step{"RCPT TO:<a@lists.test>", 250}, step{"RCPT TO:<b@lists.test>", 250}, step{"RCPT TO:<c@lists.test>", 452}),
+			nil,
+		},
+		{
+			"Commands out of order",
+			[]step{{"MAIL FROM:<me@example.com>", 503}, {"HELO client.test", 250}, {"RCPT TO:<a@lists.test>", 503}, {"DATA", 503}, {"MAIL FROM:<me@example.com>", 250}, {"MAIL FROM:<me@example.com>", 503}},
+			nil,
+		},
+		{
+			"Malformed paths",
+			[]step{{"HELO client.test", 250}, {"MAIL FROM:me@example.com", 501}, {"MAIL FROM:<>", 250}, {"RCPT TO:<>", 501}},
+			nil,
+		},
+		{
+			"Too long command lines are rejected",
+			[]step{{"HELO " + strings.Repeat("x", 100), 500}, {"NOOP", 250}, {"FOO", 502}},
+			nil,
+		},
+		{
+			"Announced sizes over the limit are rejected",
+			[]step{{"EHLO client.test", 250}, {"MAIL FROM:<me@example.com> SIZE=1000", 552}, {"MAIL FROM:<me@example.com> SIZE=100", 250}},
+			nil,
+		},
+		{
+			"Too big messages are rejected and the session continues",
+			append(hello, step{"RCPT TO:<a@lists.test>", 250}, step{"DATA", 354}, step{"\r\n" + strings.Repeat("milk\r\n", 50) + ".", 552}, step{"NOOP", 250}),
+			nil,
+		},
+		{
+			"Reset aborts the transaction",
+			append(hello, step{"RCPT TO:<a@lists.test>", 250}, step{"RSET", 250}, step{"DATA", 503}),
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &testBackend{}
+			server := NewServer("", "lists.test", backend)
+			server.MaxLineLength = 64
+			server.MaxMessageBytes = 200
+			server.MaxRecipients = 2
+
+			client, conn := net.Pipe()
+			defer client.Close()
+			done := make(chan struct{})
+			go func() {
+				server.serveConn(conn)
+				close(done)
+			}()
+
+			text := textproto.NewConn(client)
+			if code, _, err := text.ReadResponse(220); err != nil {
+				t.Fatalf("Expected a greeting, got %d: %v", code, err)
+			}
+			for _, step := range tt.steps {
+				if err := text.PrintfLine("%s", step.send); err != nil {
+					t.Fatalf("Failed to send %q: %v", step.send, err)
+				}
+				code, message, err := text.ReadResponse(0)
+				if err != nil {
+					t.Fatalf("Failed to read the reply to %q: %v", step.send, err)
+				}
+				if code != step.code {
+					t.Fatalf("Expected %d for %q, got %d %s", step.code, step.send, code, message)
+				}
+			}
+			client.Close()
+			<-done
+
+			if !slices.EqualFunc(backend.to, tt.want, slices.Equal) {
+				t.Errorf("Expected deliveries to %v, got %v", tt.want, backend.to)
+			}
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		arg     string
+		address string
+		params  []string
+		ok      bool
+	}{
+		{"FROM:<me@example.com>", "me@example.com", []string{}, true},
+		{"from: <me@example.com> SIZE=10 BODY=8BITMIME", "me@example.com", []string{"SIZE=10", "BODY=8BITMIME"}, true},
+		{"FROM:<>", "", []string{}, true},
+		{"FROM:<@relay.test:me@example.com>", "me@example.com", []string{}, true},
+		{"FROM:me@example.com", "", nil, false},
+		{"FROM:", "", nil, false},
+		{"TO:<me@example.com>", "", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			address, params, ok := parsePath(tt.arg, "FROM:")
+			if ok != tt.ok || address != tt.address || !slices.Equal(params, tt.params) {
+				t.Errorf("Expected %q %q %v, got %q %q %v", tt.address, tt.params, tt.ok, address, params, ok)
+			}
+		})
+	}
+}
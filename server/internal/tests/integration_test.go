@@ -3,11 +3,24 @@ package tests
 import (
 	"bytes"
 	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/mail"
+	"net/smtp"
 	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -16,8 +29,15 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/yair12/lists-viewer/server/internal/config"
+	"github.com/yair12/lists-viewer/server/internal/mailer"
 	"github.com/yair12/lists-viewer/server/internal/models"
+	"github.com/yair12/lists-viewer/server/internal/notify"
+	"github.com/yair12/lists-viewer/server/internal/repository"
+	"github.com/yair12/lists-viewer/server/internal/service"
 	"github.com/yair12/lists-viewer/server/internal/setup"
+	"github.com/yair12/lists-viewer/server/internal/smtpd"
+	"github.com/yair12/lists-viewer/server/internal/webpush"
 )
 
 var (
@@ -92,7 +112,16 @@ func TestMain(m *testing.M) {
 }
 
 func setupTestRouter(t *testing.T) http.Handler {
-	return setup.SetupRouter(mongoClient)
+	return setup.SetupRouter(mongoClient, testConfig(t))
+}
+
+// testConfig returns a server configuration with fresh VAPID keys
+func testConfig(t *testing.T) *config.Config {
+	keys, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatalf("Failed to generate VAPID keys: %v", err)
+	}
+	return &config.Config{DatabaseName: "lists_viewer", VAPIDKeys: keys, VAPIDSubject: "mailto:test@example.com"}
 }
 
 func clearDatabase(t *testing.T) {
@@ -100,7 +129,7 @@ func clearDatabase(t *testing.T) {
 	defer cancel()
 
 	db := mongoClient.Database("lists_viewer")
-	collections := []string{"lists", "items", "users"}
+	collections := []string{"lists", "items", "templates", "list_resets", "users", "push_subscriptions", "webhooks", "webhook_deliveries", "inbound_tokens"}
 	for _, col := range collections {
 		if _, err := db.Collection(col).DeleteMany(ctx, map[string]interface{}{}); err != nil {
 			t.Fatalf("Failed to clear collection %s: %v", col, err)
@@ -1123,3 +1152,2543 @@ func TestOptimisticLockingItemDelete(t *testing.T) {
 func ptrBool(v bool) *bool {
 	return &v
 }
+
+// createTestList creates a list and returns its response
+func createTestList(t *testing.T, handler http.Handler, userID string, name string) models.ListResponse {
+	rec := makeRequest(t, handler, "POST", "/api/v1/lists", models.CreateListRequest{Name: name}, userID)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Failed to create list: %d: %s", rec.Code, rec.Body.String())
+	}
+	var list models.ListResponse
+	json.Unmarshal(rec.Body.Bytes(), &list)
+	return list
+}
+
+// createTestItem creates an item in a list and returns its response
+func createTestItem(t *testing.T, handler http.Handler, userID string, listID string, req models.CreateItemRequest) models.ItemResponse {
+	path := fmt.Sprintf("/api/v1/lists/%s/items", listID)
+	rec := makeRequest(t, handler, "POST", path, req, userID)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Failed to create item: %d: %s", rec.Code, rec.Body.String())
+	}
+	var item models.ItemResponse
+	json.Unmarshal(rec.Body.Bytes(), &item)
+	return item
+}
+
+// TestBulkOperationResults tests per-item outcomes of bulk complete and bulk delete
+func TestBulkOperationResults(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-bulk-results"
+
+	list := createTestList(t, handler, userID, "Bulk Results List")
+	first := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "First"})
+	second := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Second"})
+
+	t.Run("Bulk complete reports per-item status", func(t *testing.T) {
+		req := models.BulkCompleteRequest{
+			Items: []models.BulkItemRef{
+				{ID: first.ID, Version: ptrInt32(first.Version)},
+				{ID: second.ID, Version: ptrInt32(second.Version + 5)},
+				{ID: "missing-item"},
+			},
+		}
+		path := fmt.Sprintf("/api/v1/lists/%s/items/complete", list.ID)
+		rec := makeRequest(t, handler, "PATCH", path, req, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var response models.BulkCompleteResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+
+		if response.CompletedCount != 1 {
+			t.Errorf("Expected 1 completed item, got %d", response.CompletedCount)
+		}
+		expected := []string{models.BulkStatusDone, models.BulkStatusVersionConflict, models.BulkStatusNotFound}
+		for i, status := range expected {
+			if response.Results[i].Status != status {
+				t.Errorf("Expected result %d to be %s, got %s", i, status, response.Results[i].Status)
+			}
+		}
+		if response.Results[1].Item == nil || response.Results[1].Item.Version != second.Version {
+			t.Errorf("Expected conflict result to include current item state")
+		}
+	})
+
+	t.Run("Completing an already completed item keeps its version", func(t *testing.T) {
+		req := models.BulkCompleteRequest{ItemIDs: []string{first.ID}}
+		path := fmt.Sprintf("/api/v1/lists/%s/items/complete", list.ID)
+		rec := makeRequest(t, handler, "PATCH", path, req, userID)
+
+		var response models.BulkCompleteResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+
+		if len(response.Results) != 1 || response.Results[0].Item.Version != first.Version+1 {
+			t.Errorf("Expected version %d to be kept, got %+v", first.Version+1, response.Results)
+		}
+	})
+
+	t.Run("Bulk delete counts only deleted items", func(t *testing.T) {
+		req := models.BulkDeleteRequest{
+			Items: []models.BulkItemRef{
+				{ID: second.ID, Version: ptrInt32(second.Version)},
+				{ID: "missing-item"},
+			},
+		}
+		path := fmt.Sprintf("/api/v1/lists/%s/items", list.ID)
+		rec := makeRequest(t, handler, "DELETE", path, req, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var response models.BulkDeleteResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+
+		if response.DeletedCount != 1 {
+			t.Errorf("Expected 1 deleted item, got %d", response.DeletedCount)
+		}
+		if response.Results[1].Status != models.BulkStatusNotFound {
+			t.Errorf("Expected missing item to be not_found, got %s", response.Results[1].Status)
+		}
+	})
+}
+
+// TestBulkUncompleteMoveAndUpdate tests bulk uncomplete, bulk move and bulk field edit
+func TestBulkUncompleteMoveAndUpdate(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-bulk-edit"
+
+	source := createTestList(t, handler, userID, "Source")
+	target := createTestList(t, handler, userID, "Target")
+	first := createTestItem(t, handler, userID, source.ID, models.CreateItemRequest{Type: "item", Name: "Flour"})
+	second := createTestItem(t, handler, userID, source.ID, models.CreateItemRequest{Type: "item", Name: "Sugar"})
+
+	completePath := fmt.Sprintf("/api/v1/lists/%s/items/complete", source.ID)
+	makeRequest(t, handler, "PATCH", completePath, models.BulkCompleteRequest{ItemIDs: []string{first.ID, second.ID}}, userID)
+
+	t.Run("Bulk uncomplete items", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/lists/%s/items/uncomplete", source.ID)
+		rec := makeRequest(t, handler, "PATCH", path, models.BulkUncompleteRequest{ItemIDs: []string{first.ID, second.ID}}, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var response models.BulkUpdateResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if response.UpdatedCount != 2 {
+			t.Errorf("Expected 2 updated items, got %d", response.UpdatedCount)
+		}
+		for _, item := range response.Data {
+			if item.Completed {
+				t.Errorf("Expected item %s to be open", item.ID)
+			}
+		}
+	})
+
+	t.Run("Bulk update quantity type", func(t *testing.T) {
+		quantityType := "kg"
+		req := models.BulkUpdateRequest{ItemIDs: []string{first.ID, second.ID}, QuantityType: &quantityType}
+		path := fmt.Sprintf("/api/v1/lists/%s/items", source.ID)
+		rec := makeRequest(t, handler, "PATCH", path, req, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var response models.BulkUpdateResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		for _, item := range response.Data {
+			if item.QuantityType != "kg" {
+				t.Errorf("Expected quantityType kg, got %s", item.QuantityType)
+			}
+		}
+	})
+
+	t.Run("Bulk update without fields is rejected", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/lists/%s/items", source.ID)
+		rec := makeRequest(t, handler, "PATCH", path, models.BulkUpdateRequest{ItemIDs: []string{first.ID}}, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("Bulk move items", func(t *testing.T) {
+		req := models.BulkMoveRequest{TargetListID: target.ID, ItemIDs: []string{first.ID, second.ID}}
+		path := fmt.Sprintf("/api/v1/lists/%s/items/move", source.ID)
+		rec := makeRequest(t, handler, "PATCH", path, req, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		getRec := makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s/items", target.ID), nil, userID)
+		var itemsResp models.ItemsResponse
+		json.Unmarshal(getRec.Body.Bytes(), &itemsResp)
+		if len(itemsResp.Data) != 2 {
+			t.Errorf("Expected 2 items in target list, got %d", len(itemsResp.Data))
+		}
+	})
+
+	t.Run("Bulk move to missing list is rejected", func(t *testing.T) {
+		req := models.BulkMoveRequest{TargetListID: "missing-list", ItemIDs: []string{first.ID}}
+		path := fmt.Sprintf("/api/v1/lists/%s/items/move", target.ID)
+		rec := makeRequest(t, handler, "PATCH", path, req, userID)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+// TestCompletionMetadata tests completedAt/completedBy and the recently completed query
+func TestCompletionMetadata(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-completion"
+
+	list := createTestList(t, handler, userID, "Groceries")
+	milk := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Milk"})
+	createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Bread"})
+
+	t.Run("Completing an item records metadata", func(t *testing.T) {
+		updateReq := models.UpdateItemRequest{
+			Name:      milk.Name,
+			Completed: ptrBool(true),
+			Order:     milk.Order,
+			Version:   milk.Version,
+		}
+		path := fmt.Sprintf("/api/v1/lists/%s/items/%s", list.ID, milk.ID)
+		rec := makeRequest(t, handler, "PUT", path, updateReq, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var item models.ItemResponse
+		json.Unmarshal(rec.Body.Bytes(), &item)
+		if item.CompletedAt == "" || item.CompletedBy != userID {
+			t.Errorf("Expected completion metadata, got completedAt=%q completedBy=%q", item.CompletedAt, item.CompletedBy)
+		}
+	})
+
+	t.Run("Recently completed items", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/lists/%s/items/recent?days=7", list.ID)
+		rec := makeRequest(t, handler, "GET", path, nil, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var itemsResp models.ItemsResponse
+		json.Unmarshal(rec.Body.Bytes(), &itemsResp)
+		if len(itemsResp.Data) != 1 || itemsResp.Data[0].ID != milk.ID {
+			t.Errorf("Expected only milk to be recently completed, got %+v", itemsResp.Data)
+		}
+	})
+
+	t.Run("Completed items are listed after open items", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/lists/%s/items", list.ID)
+		rec := makeRequest(t, handler, "GET", path, nil, userID)
+
+		var itemsResp models.ItemsResponse
+		json.Unmarshal(rec.Body.Bytes(), &itemsResp)
+		if len(itemsResp.Data) != 2 || itemsResp.Data[1].ID != milk.ID {
+			t.Errorf("Expected completed item last, got %+v", itemsResp.Data)
+		}
+	})
+
+	t.Run("Invalid days is rejected", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/lists/%s/items/recent?days=0", list.ID)
+		rec := makeRequest(t, handler, "GET", path, nil, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+}
+
+// TestPartialFulfillment tests recording partially bought quantities
+func TestPartialFulfillment(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-fulfill"
+
+	list := createTestList(t, handler, userID, "Groceries")
+	quantity := 5.0
+	apples := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Apples", Quantity: &quantity})
+	path := fmt.Sprintf("/api/v1/lists/%s/items/%s/fulfill", list.ID, apples.ID)
+
+	t.Run("Partial fulfillment keeps item open", func(t *testing.T) {
+		rec := makeRequest(t, handler, "PATCH", path, models.FulfillItemRequest{Quantity: 2, Version: apples.Version}, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var item models.ItemResponse
+		json.Unmarshal(rec.Body.Bytes(), &item)
+		if item.Completed || item.FulfilledQuantity == nil || *item.FulfilledQuantity != 2 {
+			t.Errorf("Expected open item with 2 fulfilled, got %+v", item)
+		}
+		apples = item
+
+		listRec := makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s", list.ID), nil, userID)
+		var updatedList models.ListResponse
+		json.Unmarshal(listRec.Body.Bytes(), &updatedList)
+		if updatedList.PartialItemCount != 1 {
+			t.Errorf("Expected 1 partially fulfilled item, got %d", updatedList.PartialItemCount)
+		}
+	})
+
+	t.Run("Lowering the quantity below the bought amount completes the item", func(t *testing.T) {
+		pears := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Pears", Quantity: &quantity})
+		pearsPath := fmt.Sprintf("/api/v1/lists/%s/items/%s", list.ID, pears.ID)
+		json.Unmarshal(makeRequest(t, handler, "PATCH", pearsPath+"/fulfill", models.FulfillItemRequest{Quantity: 3, Version: pears.Version}, userID).Body.Bytes(), &pears)
+
+		two := 2.0
+		update := models.UpdateItemRequest{Name: pears.Name, Quantity: &two, Order: pears.Order, Version: pears.Version}
+		rec := makeRequest(t, handler, "PUT", pearsPath, update, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		json.Unmarshal(rec.Body.Bytes(), &pears)
+		if !pears.Completed || pears.FulfilledQuantity == nil || *pears.FulfilledQuantity != 2 {
+			t.Errorf("Expected a completed item with 2 of 2 bought, got %+v", pears)
+		}
+
+		update = models.UpdateItemRequest{Name: pears.Name, Completed: ptrBool(false), Order: pears.Order, Version: pears.Version}
+		json.Unmarshal(makeRequest(t, handler, "PUT", pearsPath, update, userID).Body.Bytes(), &pears)
+		if pears.Completed || pears.FulfilledQuantity != nil {
+			t.Errorf("Expected an open item without a bought amount, got %+v", pears)
+		}
+	})
+
+	t.Run("Reaching the quantity completes the item", func(t *testing.T) {
+		rec := makeRequest(t, handler, "PATCH", path, models.FulfillItemRequest{Quantity: 3, Version: apples.Version}, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var item models.ItemResponse
+		json.Unmarshal(rec.Body.Bytes(), &item)
+		if !item.Completed {
+			t.Errorf("Expected item to be completed")
+		}
+	})
+
+	t.Run("Items without quantity cannot be fulfilled", func(t *testing.T) {
+		bread := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Bread"})
+		breadPath := fmt.Sprintf("/api/v1/lists/%s/items/%s/fulfill", list.ID, bread.ID)
+		rec := makeRequest(t, handler, "PATCH", breadPath, models.FulfillItemRequest{Quantity: 1, Version: bread.Version}, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+// TestPerMemberCompletion tests lists where each member completes items individually
+func TestPerMemberCompletion(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+
+	for _, username := range []string{"alice", "bob"} {
+		makeRequest(t, handler, "POST", "/api/v1/users/init", models.InitUserRequest{Username: username, IconID: "icon1"}, "")
+	}
+
+	createReq := models.CreateListRequest{
+		Name:           "Packing",
+		CompletionMode: models.CompletionModePerMember,
+		Members:        []string{"alice", "bob", "carol"},
+	}
+	if rec := makeRequest(t, handler, "POST", "/api/v1/lists", createReq, "alice"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown member, got %d", rec.Code)
+	}
+	createReq.Members = []string{"alice", "bob", " bob "}
+	rec := makeRequest(t, handler, "POST", "/api/v1/lists", createReq, "alice")
+	var list models.ListResponse
+	json.Unmarshal(rec.Body.Bytes(), &list)
+	if list.CompletionMode != models.CompletionModePerMember {
+		t.Fatalf("Expected per_member list, got %q", list.CompletionMode)
+	}
+
+	passport := createTestItem(t, handler, "alice", list.ID, models.CreateItemRequest{Type: "item", Name: "Passport"})
+	if passport.MemberCount != 2 {
+		t.Errorf("Expected the created item to show progress of the two members, got %+v", passport)
+	}
+	completePath := fmt.Sprintf("/api/v1/lists/%s/items/complete", list.ID)
+	itemPath := fmt.Sprintf("/api/v1/lists/%s/items/%s", list.ID, passport.ID)
+
+	t.Run("Completion is tracked per member", func(t *testing.T) {
+		makeRequest(t, handler, "PATCH", completePath, models.BulkCompleteRequest{ItemIDs: []string{passport.ID}}, "alice")
+
+		var forAlice, forBob models.ItemResponse
+		json.Unmarshal(makeRequest(t, handler, "GET", itemPath, nil, "alice").Body.Bytes(), &forAlice)
+		json.Unmarshal(makeRequest(t, handler, "GET", itemPath, nil, "bob").Body.Bytes(), &forBob)
+
+		if !forAlice.Completed || forBob.Completed {
+			t.Errorf("Expected completed for alice only, got alice=%t bob=%t", forAlice.Completed, forBob.Completed)
+		}
+		if forBob.CompletedUserCount != 1 || forBob.MemberCount != 2 {
+			t.Errorf("Expected progress 1/2, got %d/%d", forBob.CompletedUserCount, forBob.MemberCount)
+		}
+	})
+
+	t.Run("Item is completed once every member completed it", func(t *testing.T) {
+		makeRequest(t, handler, "PATCH", completePath, models.BulkCompleteRequest{ItemIDs: []string{passport.ID}}, "bob")
+
+		listRec := makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s", list.ID), nil, "alice")
+		var updatedList models.ListResponse
+		json.Unmarshal(listRec.Body.Bytes(), &updatedList)
+		if updatedList.CompletedItemCount != 1 {
+			t.Errorf("Expected 1 completed item, got %d", updatedList.CompletedItemCount)
+		}
+	})
+
+	t.Run("Recently completed items show per-member progress", func(t *testing.T) {
+		var response models.ItemsResponse
+		json.Unmarshal(makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s/items/recent", list.ID), nil, "bob").Body.Bytes(), &response)
+		if len(response.Data) != 1 || !response.Data[0].Completed || response.Data[0].CompletedUserCount != 2 || response.Data[0].MemberCount != 2 {
+			t.Errorf("Expected the passport completed by both members, got %+v", response.Data)
+		}
+	})
+
+	t.Run("Members must be known users", func(t *testing.T) {
+		var current models.ListResponse
+		json.Unmarshal(makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s", list.ID), nil, "alice").Body.Bytes(), &current)
+		req := models.UpdateListRequest{Name: current.Name, Members: []string{"alice", "carol"}, Version: current.Version}
+		if rec := makeRequest(t, handler, "PUT", fmt.Sprintf("/api/v1/lists/%s", list.ID), req, "alice"); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for an unknown member, got %d", rec.Code)
+		}
+	})
+}
+
+// TestDuplicateDetectionAndMerge tests duplicate reporting on create and merging duplicates
+func TestDuplicateDetectionAndMerge(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-duplicates"
+
+	list := createTestList(t, handler, userID, "Groceries")
+	two, three := 2.0, 3.0
+	first := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Tomatoes", Quantity: &two, QuantityType: "kg"})
+	second := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "  tomatoes ", Quantity: &three, QuantityType: "KG"})
+
+	t.Run("Create reports likely duplicates", func(t *testing.T) {
+		if len(second.Duplicates) != 1 || second.Duplicates[0].ID != first.ID {
+			t.Errorf("Expected first item to be reported as duplicate, got %+v", second.Duplicates)
+		}
+	})
+
+	t.Run("Unicode folding is optional", func(t *testing.T) {
+		createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Café"})
+		plain := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "cafe"})
+		if len(plain.Duplicates) != 0 {
+			t.Errorf("Expected no duplicates without folding, got %+v", plain.Duplicates)
+		}
+		folded := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "CAFE", FoldUnicode: true})
+		if len(folded.Duplicates) != 2 {
+			t.Errorf("Expected 2 duplicates with folding, got %+v", folded.Duplicates)
+		}
+	})
+
+	t.Run("Merge sums quantities and deletes the rest", func(t *testing.T) {
+		req := models.MergeItemsRequest{
+			KeepID: first.ID,
+			Items: []models.BulkItemRef{
+				{ID: first.ID, Version: ptrInt32(first.Version)},
+				{ID: second.ID, Version: ptrInt32(second.Version)},
+			},
+		}
+		path := fmt.Sprintf("/api/v1/lists/%s/items/merge", list.ID)
+		rec := makeRequest(t, handler, "POST", path, req, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var response models.MergeItemsResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if response.Data.Quantity == nil || *response.Data.Quantity != 5 {
+			t.Errorf("Expected merged quantity 5, got %v", response.Data.Quantity)
+		}
+		if len(response.DeletedIDs) != 1 || response.DeletedIDs[0] != second.ID {
+			t.Errorf("Expected second item to be deleted, got %v", response.DeletedIDs)
+		}
+
+		getRec := makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s/items/%s", list.ID, second.ID), nil, userID)
+		if getRec.Code != http.StatusNotFound {
+			t.Errorf("Expected merged item to be gone, got %d", getRec.Code)
+		}
+	})
+}
+
+// TestQuantityUnits tests unit normalization and unit conversion when merging
+func TestQuantityUnits(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-units"
+
+	list := createTestList(t, handler, userID, "Baking")
+	one, fiveHundred := 1.0, 500.0
+	flour := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Flour", Quantity: &one, QuantityType: "Kilograms"})
+	moreFlour := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "flour", Quantity: &fiveHundred, QuantityType: "גרם"})
+
+	t.Run("Quantity types are normalized", func(t *testing.T) {
+		if flour.QuantityType != "kg" || moreFlour.QuantityType != "g" {
+			t.Errorf("Expected kg and g, got %q and %q", flour.QuantityType, moreFlour.QuantityType)
+		}
+	})
+
+	t.Run("Merging converts compatible units", func(t *testing.T) {
+		req := models.MergeItemsRequest{
+			KeepID: flour.ID,
+			Items:  []models.BulkItemRef{{ID: flour.ID}, {ID: moreFlour.ID}},
+		}
+		rec := makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/lists/%s/items/merge", list.ID), req, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var response models.MergeItemsResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if response.Data.Quantity == nil || *response.Data.Quantity != 1.5 || response.Data.QuantityType != "kg" {
+			t.Errorf("Expected 1.5 kg, got %v %s", response.Data.Quantity, response.Data.QuantityType)
+		}
+	})
+
+	t.Run("Merging incompatible units is rejected", func(t *testing.T) {
+		two := 2.0
+		milk := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Milk", Quantity: &two, QuantityType: "liters"})
+		req := models.MergeItemsRequest{
+			KeepID: flour.ID,
+			Items:  []models.BulkItemRef{{ID: flour.ID}, {ID: milk.ID}},
+		}
+		rec := makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/lists/%s/items/merge", list.ID), req, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("Get known units", func(t *testing.T) {
+		rec := makeRequest(t, handler, "GET", "/api/v1/units", nil, userID)
+		var response models.UnitsResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if rec.Code != http.StatusOK || len(response.Data) == 0 {
+			t.Errorf("Expected known units, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+func TestQuickAddItem(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-quick-add"
+
+	list := createTestList(t, handler, userID, "Groceries")
+	path := fmt.Sprintf("/api/v1/lists/%s/items/quick", list.ID)
+
+	cases := []struct {
+		text     string
+		name     string
+		quantity float64
+		unit     string
+	}{
+		{"2kg tomatoes", "tomatoes", 2, "kg"},
+		{"tomatoes x3", "tomatoes", 3, "pcs"},
+		{"2 kg of potatoes", "potatoes", 2, "kg"},
+		{"עגבניות 1,5 ק\"ג", "עגבניות", 1.5, "kg"},
+		{"2 ליטר חלב", "חלב", 2, "l"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.text, func(t *testing.T) {
+			rec := makeRequest(t, handler, "POST", path, models.QuickAddItemRequest{Text: tc.text, UserIconID: "icon1"}, userID)
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+			}
+
+			var response models.QuickAddItemResponse
+			json.Unmarshal(rec.Body.Bytes(), &response)
+			if response.Item == nil || response.Item.Name != tc.name || response.Item.Quantity == nil ||
+				*response.Item.Quantity != tc.quantity || response.Item.QuantityType != tc.unit {
+				t.Errorf("Expected %s %v %s, got %s", tc.name, tc.quantity, tc.unit, rec.Body.String())
+			}
+			if response.Parsed.Name != tc.name {
+				t.Errorf("Expected parsed name %s, got %s", tc.name, response.Parsed.Name)
+			}
+		})
+	}
+
+	t.Run("Preview does not create the item", func(t *testing.T) {
+		rec := makeRequest(t, handler, "POST", path, models.QuickAddItemRequest{Text: "bread", Preview: true}, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var response models.QuickAddItemResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if response.Item != nil || response.Parsed.Name != "bread" || response.Parsed.Quantity != nil {
+			t.Errorf("Expected preview of bread only, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("Quantity without a name is rejected", func(t *testing.T) {
+		rec := makeRequest(t, handler, "POST", path, models.QuickAddItemRequest{Text: "2 kg"}, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+func TestShoppingView(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-shopping"
+
+	salad := createTestList(t, handler, userID, "Salad")
+	soup := createTestList(t, handler, userID, "Soup")
+	half, oneAndHalf, two := 0.5, 1.5, 2.0
+	createTestItem(t, handler, userID, salad.ID, models.CreateItemRequest{Type: "item", Name: "Tomatoes", Quantity: &half, QuantityType: "kg"})
+	createTestItem(t, handler, userID, salad.ID, models.CreateItemRequest{Type: "item", Name: "Cucumbers", Quantity: &two})
+	broth := createTestItem(t, handler, userID, soup.ID, models.CreateItemRequest{Type: "list", Name: "Broth"})
+	createTestItem(t, handler, userID, broth.ID, models.CreateItemRequest{Type: "item", Name: "tomatoes", Quantity: &oneAndHalf, QuantityType: "g"})
+	createTestItem(t, handler, userID, soup.ID, models.CreateItemRequest{Type: "item", Name: "Salt"})
+
+	path := fmt.Sprintf("/api/v1/views/shopping?lists=%s,%s", salad.ID, soup.ID)
+
+	t.Run("Groups same-named items across lists and nested lists", func(t *testing.T) {
+		rec := makeRequest(t, handler, "GET", path, nil, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var response models.ShoppingViewResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if len(response.Data) != 3 {
+			t.Fatalf("Expected 3 entries, got %d: %s", len(response.Data), rec.Body.String())
+		}
+
+		tomatoes := response.Data[2]
+		if tomatoes.Key != "tomatoes" || len(tomatoes.Sources) != 2 {
+			t.Fatalf("Expected tomatoes from two sources, got %+v", tomatoes)
+		}
+		if len(tomatoes.Quantities) != 1 || tomatoes.Quantities[0].Quantity != 0.5015 || tomatoes.Quantities[0].QuantityType != "kg" {
+			t.Errorf("Expected 0.5015 kg of tomatoes, got %+v", tomatoes.Quantities)
+		}
+		if tomatoes.Sources[1].ListID != broth.ID || tomatoes.Sources[1].ListName != "Broth" {
+			t.Errorf("Expected second source in the nested list, got %+v", tomatoes.Sources[1])
+		}
+	})
+
+	t.Run("Complete a grouped entry", func(t *testing.T) {
+		req := models.CompleteShoppingEntryRequest{Lists: []string{salad.ID, soup.ID}, Key: "Tomatoes"}
+		rec := makeRequest(t, handler, "PATCH", "/api/v1/views/shopping/complete", req, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var response models.BulkCompleteResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if response.CompletedCount != 2 {
+			t.Errorf("Expected 2 completed items, got %d", response.CompletedCount)
+		}
+
+		rec = makeRequest(t, handler, "GET", path, nil, userID)
+		var view models.ShoppingViewResponse
+		json.Unmarshal(rec.Body.Bytes(), &view)
+		if len(view.Data) != 2 {
+			t.Errorf("Expected 2 open entries after completing tomatoes, got %d", len(view.Data))
+		}
+	})
+
+	t.Run("Unknown list returns 404", func(t *testing.T) {
+		rec := makeRequest(t, handler, "GET", "/api/v1/views/shopping?lists=missing", nil, userID)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+	})
+}
+
+func TestSearch(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-search"
+
+	hardware := createTestList(t, handler, userID, "Hardware")
+	home := createTestList(t, handler, userID, "Home")
+	createTestItem(t, handler, userID, hardware.ID, models.CreateItemRequest{Type: "item", Name: "AA Batteries"})
+	drawer := createTestItem(t, handler, userID, home.ID, models.CreateItemRequest{Type: "list", Name: "Drawer", Description: "spare batteries and tape"})
+	createTestItem(t, handler, userID, drawer.ID, models.CreateItemRequest{Type: "item", Name: "Batteries 9V"})
+	createTestItem(t, handler, userID, home.ID, models.CreateItemRequest{Type: "item", Name: "סוללות"})
+
+	search := func(t *testing.T, query string) models.SearchResponse {
+		rec := makeRequest(t, handler, "GET", "/api/v1/search?"+query, nil, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var response models.SearchResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		return response
+	}
+
+	t.Run("Text search across lists with highlights", func(t *testing.T) {
+		response := search(t, "q=batteries")
+		if response.Mode != "text" || len(response.Data) != 3 {
+			t.Fatalf("Expected 3 text matches, got %s %+v", response.Mode, response.Data)
+		}
+		first := response.Data[0]
+		if len(first.Highlights) == 0 || first.Highlights[0].Field != "name" {
+			t.Errorf("Expected a name highlight on the best match, got %+v", first)
+		}
+	})
+
+	t.Run("Nested list items report their nested list", func(t *testing.T) {
+		response := search(t, "q=9V")
+		if len(response.Data) != 1 || response.Data[0].ListName != "Drawer" {
+			t.Errorf("Expected the 9V batteries in Drawer, got %+v", response.Data)
+		}
+	})
+
+	t.Run("Hebrew falls back to substring search", func(t *testing.T) {
+		response := search(t, "q=%D7%A1%D7%95%D7%9C%D7%9C")
+		if response.Mode != "substring" || len(response.Data) != 1 {
+			t.Fatalf("Expected 1 substring match, got %s %+v", response.Mode, response.Data)
+		}
+		ranges := response.Data[0].Highlights[0].Ranges
+		if len(ranges) != 1 || ranges[0].Start != 0 || ranges[0].End != 4 {
+			t.Errorf("Expected highlight 0-4, got %+v", ranges)
+		}
+	})
+
+	t.Run("Filters by type, list and completed state", func(t *testing.T) {
+		response := search(t, "q=batteries&type=item&list="+home.ID)
+		if len(response.Data) != 1 || response.Data[0].Item == nil || response.Data[0].Item.Name != "Batteries 9V" {
+			t.Errorf("Expected only the 9V batteries, got %+v", response.Data)
+		}
+
+		response = search(t, "q=batteries&completed=true")
+		if len(response.Data) != 0 {
+			t.Errorf("Expected no completed matches, got %+v", response.Data)
+		}
+	})
+
+	t.Run("Missing query is rejected", func(t *testing.T) {
+		rec := makeRequest(t, handler, "GET", "/api/v1/search", nil, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+}
+
+func TestItemListingQuery(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-item-query"
+
+	list := createTestList(t, handler, userID, "Pantry")
+	names := []string{"Tea", "apples", "Bread", "tomatoes", "Tahini"}
+	for _, name := range names {
+		createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: name})
+	}
+	createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "list", Name: "Spices"})
+
+	items := fmt.Sprintf("/api/v1/lists/%s/items", list.ID)
+	get := func(t *testing.T, query string) models.ItemsResponse {
+		rec := makeRequest(t, handler, "GET", items+"?"+query, nil, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var response models.ItemsResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		return response
+	}
+
+	t.Run("Filter by type and name prefix", func(t *testing.T) {
+		response := get(t, "type=item&prefix=t&sort=name")
+		if len(response.Data) != 3 || response.Data[0].Name != "Tahini" || response.Data[2].Name != "tomatoes" {
+			t.Errorf("Expected Tahini, Tea, tomatoes, got %+v", response.Data)
+		}
+	})
+
+	t.Run("Cursor pagination visits every item once", func(t *testing.T) {
+		seen := map[string]bool{}
+		cursor := ""
+		for page := 0; page < 10; page++ {
+			response := get(t, "type=item&sort=name&direction=desc&limit=2&cursor="+cursor)
+			for _, item := range response.Data {
+				if seen[item.ID] {
+					t.Fatalf("Item %s returned twice", item.Name)
+				}
+				seen[item.ID] = true
+			}
+			if response.NextCursor == "" {
+				break
+			}
+			cursor = response.NextCursor
+		}
+		if len(seen) != len(names) {
+			t.Errorf("Expected %d items across pages, got %d", len(names), len(seen))
+		}
+	})
+
+	t.Run("Completed filter", func(t *testing.T) {
+		all := get(t, "type=item")
+		req := models.BulkCompleteRequest{ItemIDs: []string{all.Data[0].ID}}
+		makeRequest(t, handler, "PATCH", items+"/complete", req, userID)
+
+		if response := get(t, "completed=true"); len(response.Data) != 1 {
+			t.Errorf("Expected 1 completed item, got %d", len(response.Data))
+		}
+		if response := get(t, "completed=false&type=item"); len(response.Data) != len(names)-1 {
+			t.Errorf("Expected %d open items, got %d", len(names)-1, len(response.Data))
+		}
+	})
+
+	t.Run("Invalid sort and cursor are rejected", func(t *testing.T) {
+		for _, query := range []string{"sort=color", "cursor=not-a-cursor"} {
+			rec := makeRequest(t, handler, "GET", items+"?"+query, nil, userID)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %s, got %d", query, rec.Code)
+			}
+		}
+	})
+}
+
+func TestExpandedListFetch(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-expand"
+
+	list := createTestList(t, handler, userID, "Groceries")
+	createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Bread"})
+	dairy := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "list", Name: "Dairy"})
+	createTestItem(t, handler, userID, dairy.ID, models.CreateItemRequest{Type: "item", Name: "Milk"})
+	createTestItem(t, handler, userID, dairy.ID, models.CreateItemRequest{Type: "item", Name: "Cheese"})
+
+	t.Run("Expand items and children", func(t *testing.T) {
+		rec := makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s?expand=items,children", list.ID), nil, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var response models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if response.ID != list.ID || len(response.Items) != 2 {
+			t.Fatalf("Expected the list with 2 items, got %+v", response)
+		}
+		nested := response.Items[1]
+		if nested.ID != dairy.ID || len(nested.Children) != 2 || nested.Children[0].Name != "Milk" {
+			t.Errorf("Expected Dairy with Milk and Cheese, got %+v", nested)
+		}
+	})
+
+	t.Run("Expand items only", func(t *testing.T) {
+		rec := makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s?expand=items", list.ID), nil, userID)
+		var response models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if len(response.Items) != 2 || len(response.Items[1].Children) != 0 {
+			t.Errorf("Expected items without children, got %+v", response.Items)
+		}
+	})
+
+	t.Run("Without expand the list has no items", func(t *testing.T) {
+		rec := makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s", list.ID), nil, userID)
+		var response models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if response.Items != nil {
+			t.Errorf("Expected no items, got %+v", response.Items)
+		}
+	})
+
+	t.Run("Unknown expand and missing list", func(t *testing.T) {
+		rec := makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s?expand=members", list.ID), nil, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+		rec = makeRequest(t, handler, "GET", "/api/v1/lists/missing?expand=items", nil, userID)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+	})
+}
+
+func TestNestingRules(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-nesting"
+
+	list := createTestList(t, handler, userID, "House")
+	other := createTestList(t, handler, userID, "Garden")
+	kitchen := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "list", Name: "Kitchen"})
+	tools := createTestItem(t, handler, userID, other.ID, models.CreateItemRequest{Type: "list", Name: "Tools"})
+
+	t.Run("Items can be created in nested lists", func(t *testing.T) {
+		createTestItem(t, handler, userID, kitchen.ID, models.CreateItemRequest{Type: "item", Name: "Sponges"})
+	})
+
+	t.Run("Nested lists cannot contain lists", func(t *testing.T) {
+		req := models.CreateItemRequest{Type: "list", Name: "Drawer"}
+		rec := makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/lists/%s/items", kitchen.ID), req, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("Parent list must exist", func(t *testing.T) {
+		req := models.CreateItemRequest{Type: "item", Name: "Orphan"}
+		rec := makeRequest(t, handler, "POST", "/api/v1/lists/missing-list/items", req, userID)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		rec = makeRequest(t, handler, "GET", "/api/v1/lists/missing-list/items", nil, userID)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 when listing, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Invalid type is rejected", func(t *testing.T) {
+		req := models.CreateItemRequest{Type: "folder", Name: "Stuff"}
+		rec := makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/lists/%s/items", list.ID), req, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Nested lists cannot be completed", func(t *testing.T) {
+		req := models.UpdateItemRequest{Name: kitchen.Name, Completed: ptrBool(true), Version: kitchen.Version}
+		rec := makeRequest(t, handler, "PUT", fmt.Sprintf("/api/v1/lists/%s/items/%s", list.ID, kitchen.ID), req, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("Nothing can be created under a list nested too deep", func(t *testing.T) {
+		// Such lists predate the nesting rules and can only exist in the database
+		repos := repository.NewRepositories(mongoClient.Database("lists_viewer"))
+		drawer := &models.Item{UUID: "legacy-drawer", ListID: kitchen.ID, Type: "list", Name: "Drawer"}
+		if err := repos.Item.Create(context.Background(), drawer); err != nil {
+			t.Fatalf("Failed to create nested list: %v", err)
+		}
+		req := models.CreateItemRequest{Type: "item", Name: "Spoons"}
+		rec := makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/lists/%s/items", drawer.UUID), req, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("Bulk completing a nested list completes its items", func(t *testing.T) {
+		req := models.BulkCompleteRequest{ItemIDs: []string{kitchen.ID}}
+		rec := makeRequest(t, handler, "PATCH", fmt.Sprintf("/api/v1/lists/%s/items/complete", list.ID), req, userID)
+		var response models.BulkCompleteResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if rec.Code != http.StatusOK || len(response.Results) != 1 || response.Results[0].Status != models.BulkStatusDone {
+			t.Fatalf("Expected the nested list to be done, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		rec = makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s/items", kitchen.ID), nil, userID)
+		var items models.ItemsResponse
+		json.Unmarshal(rec.Body.Bytes(), &items)
+		for _, item := range items.Data {
+			if item.Type == "item" && !item.Completed {
+				t.Errorf("Expected %s to be completed", item.Name)
+			}
+		}
+	})
+
+	t.Run("Nested lists cannot move into nested lists", func(t *testing.T) {
+		req := models.BulkMoveRequest{TargetListID: tools.ID, ItemIDs: []string{kitchen.ID}}
+		rec := makeRequest(t, handler, "PATCH", fmt.Sprintf("/api/v1/lists/%s/items/move", list.ID), req, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+func TestPromoteAndDemoteLists(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-promote"
+
+	list := createTestList(t, handler, userID, "Groceries")
+	dairy := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "list", Name: "Dairy", Description: "Cold stuff"})
+	createTestItem(t, handler, userID, dairy.ID, models.CreateItemRequest{Type: "item", Name: "Milk"})
+	promotePath := fmt.Sprintf("/api/v1/lists/%s/items/%s/promote", list.ID, dairy.ID)
+
+	t.Run("Promote with a stale version conflicts", func(t *testing.T) {
+		rec := makeRequest(t, handler, "POST", promotePath, models.PromoteNestedListRequest{Version: dairy.Version + 1}, userID)
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Expected status 409, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	var promoted models.ListResponse
+	t.Run("Promote keeps UUID, description and children", func(t *testing.T) {
+		rec := makeRequest(t, handler, "POST", promotePath, models.PromoteNestedListRequest{Version: dairy.Version}, userID)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		json.Unmarshal(rec.Body.Bytes(), &promoted)
+		if promoted.ID != dairy.ID || promoted.Description != "Cold stuff" || promoted.ItemCount != 1 || promoted.CreatedAt != dairy.CreatedAt {
+			t.Errorf("Expected promoted Dairy with 1 item and its creation time, got %+v", promoted)
+		}
+
+		rec = makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s/items", list.ID), nil, userID)
+		var items models.ItemsResponse
+		json.Unmarshal(rec.Body.Bytes(), &items)
+		if len(items.Data) != 0 {
+			t.Errorf("Expected the nested list to leave its parent, got %+v", items.Data)
+		}
+	})
+
+	t.Run("Demote back into a top-level list", func(t *testing.T) {
+		update := models.UpdateListRequest{Name: promoted.Name, Description: promoted.Description, Color: "#00ff00", Aliases: []string{"milk and cheese"}, Version: promoted.Version}
+		json.Unmarshal(makeRequest(t, handler, "PUT", fmt.Sprintf("/api/v1/lists/%s", promoted.ID), update, userID).Body.Bytes(), &promoted)
+
+		req := models.DemoteListRequest{TargetListID: list.ID, Version: promoted.Version}
+		rec := makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/lists/%s/demote", promoted.ID), req, userID)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var item models.ItemResponse
+		json.Unmarshal(rec.Body.Bytes(), &item)
+		if item.ID != dairy.ID || item.Type != "list" || item.ListID != list.ID || item.ItemCount != 1 || item.CreatedAt != dairy.CreatedAt {
+			t.Errorf("Expected Dairy nested in Groceries with 1 item and its creation time, got %+v", item)
+		}
+
+		rec = makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s", dairy.ID), nil, userID)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected the top-level list to be gone, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Demote respects the nesting depth", func(t *testing.T) {
+		other := createTestList(t, handler, userID, "Other")
+		req := models.DemoteListRequest{TargetListID: dairy.ID, Version: other.Version}
+		rec := makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/lists/%s/demote", other.ID), req, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for a nested target, got %d", rec.Code)
+		}
+
+		req = models.DemoteListRequest{TargetListID: other.ID, Version: list.Version}
+		rec = makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/lists/%s/demote", list.ID), req, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for a list with nested lists, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Promoting a demoted list restores its settings", func(t *testing.T) {
+		var current models.ItemResponse
+		json.Unmarshal(makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s/items/%s", list.ID, dairy.ID), nil, userID).Body.Bytes(), &current)
+
+		rec := makeRequest(t, handler, "POST", promotePath, models.PromoteNestedListRequest{Version: current.Version}, userID)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var restored models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &restored)
+		if restored.Color != "#00ff00" || len(restored.Aliases) != 1 || restored.Aliases[0] != "milk and cheese" || restored.CreatedAt != dairy.CreatedAt {
+			t.Errorf("Expected the color, aliases and creation time from before the demotion, got %+v", restored)
+		}
+	})
+}
+
+func TestConvertItemType(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-convert"
+
+	list := createTestList(t, handler, userID, "Party")
+	ten := 10.0
+	supplies := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Party supplies", Quantity: &ten, QuantityType: "pcs"})
+	path := fmt.Sprintf("/api/v1/lists/%s/items/%s/convert", list.ID, supplies.ID)
+	fulfillPath := fmt.Sprintf("/api/v1/lists/%s/items/%s/fulfill", list.ID, supplies.ID)
+	json.Unmarshal(makeRequest(t, handler, "PATCH", fulfillPath, models.FulfillItemRequest{Quantity: 4, Version: supplies.Version}, userID).Body.Bytes(), &supplies)
+	if supplies.FulfilledQuantity == nil || *supplies.FulfilledQuantity != 4 {
+		t.Fatalf("Expected 4 fulfilled, got %+v", supplies)
+	}
+
+	var converted models.ItemResponse
+	t.Run("Item becomes a nested list", func(t *testing.T) {
+		rec := makeRequest(t, handler, "PATCH", path, models.ConvertItemRequest{Type: "list", Version: supplies.Version}, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		json.Unmarshal(rec.Body.Bytes(), &converted)
+		if converted.Type != "list" || converted.Quantity != nil || converted.FulfilledQuantity != nil || converted.Description != "10 pcs" ||
+			converted.Order != supplies.Order || converted.Version != supplies.Version+1 {
+			t.Errorf("Expected nested list keeping order with quantity in description, got %+v", converted)
+		}
+	})
+
+	t.Run("Stale version conflicts", func(t *testing.T) {
+		rec := makeRequest(t, handler, "PATCH", path, models.ConvertItemRequest{Type: "item", Version: supplies.Version}, userID)
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Expected status 409, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Nested list with children cannot become an item", func(t *testing.T) {
+		balloons := createTestItem(t, handler, userID, converted.ID, models.CreateItemRequest{Type: "item", Name: "Balloons"})
+		rec := makeRequest(t, handler, "PATCH", path, models.ConvertItemRequest{Type: "item", Version: converted.Version}, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+
+		deletePath := fmt.Sprintf("/api/v1/lists/%s/items/%s", converted.ID, balloons.ID)
+		makeRequest(t, handler, "DELETE", deletePath, models.DeleteItemRequest{Version: balloons.Version}, userID)
+	})
+
+	t.Run("Empty nested list becomes an item", func(t *testing.T) {
+		rec := makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s/items/%s", list.ID, supplies.ID), nil, userID)
+		var current models.ItemResponse
+		json.Unmarshal(rec.Body.Bytes(), &current)
+
+		rec = makeRequest(t, handler, "PATCH", path, models.ConvertItemRequest{Type: "item", Version: current.Version}, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var item models.ItemResponse
+		json.Unmarshal(rec.Body.Bytes(), &item)
+		if item.Type != "item" || item.ID != supplies.ID || item.Description != "10 pcs" {
+			t.Errorf("Expected a plain item with the same ID and description, got %+v", item)
+		}
+
+		itemPath := fmt.Sprintf("/api/v1/lists/%s/items/%s", list.ID, supplies.ID)
+		update := models.UpdateItemRequest{Name: item.Name, Description: "the blue ones", Order: item.Order, Version: item.Version}
+		json.Unmarshal(makeRequest(t, handler, "PUT", itemPath, update, userID).Body.Bytes(), &item)
+		if item.Description != "the blue ones" {
+			t.Errorf("Expected the item description to be editable, got %+v", item)
+		}
+	})
+}
+
+func TestCloneList(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-clone"
+
+	list := createTestList(t, handler, userID, "Weekly")
+	quantity := 2.0
+	milk := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Milk", Quantity: &quantity, QuantityType: "l"})
+	dairy := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "list", Name: "Dairy"})
+	createTestItem(t, handler, userID, dairy.ID, models.CreateItemRequest{Type: "item", Name: "Cheese"})
+
+	completed := true
+	rec := makeRequest(t, handler, "PUT", fmt.Sprintf("/api/v1/lists/%s/items/%s", list.ID, milk.ID),
+		models.UpdateItemRequest{Name: milk.Name, Completed: &completed, Version: milk.Version, Order: milk.Order}, userID)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Failed to complete item: %d %s", rec.Code, rec.Body.String())
+	}
+
+	t.Run("Clone copies items and nested lists under new IDs", func(t *testing.T) {
+		rec := makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/lists/%s/clone", list.ID), models.CloneListRequest{}, userID)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var clone models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &clone)
+		if clone.ID == list.ID || clone.Name != "Weekly (copy)" || clone.ItemCount != 2 {
+			t.Fatalf("Expected a new list with 2 items, got %+v", clone)
+		}
+
+		rec = makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s?expand=items,children", clone.ID), nil, userID)
+		var expanded models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &expanded)
+		if len(expanded.Items) != 2 {
+			t.Fatalf("Expected 2 cloned items, got %+v", expanded.Items)
+		}
+		for _, item := range expanded.Items {
+			if item.ID == milk.ID || item.ID == dairy.ID {
+				t.Errorf("Expected new item IDs, got %s", item.ID)
+			}
+			if item.Name == "Milk" && !item.Completed {
+				t.Error("Expected completion to be kept by default")
+			}
+			if item.Name == "Dairy" && (len(item.Children) != 1 || item.Children[0].ListID != item.ID) {
+				t.Errorf("Expected the nested list child to be cloned, got %+v", item.Children)
+			}
+		}
+	})
+
+	t.Run("Clone can reset completion and drop quantities", func(t *testing.T) {
+		req := models.CloneListRequest{Name: "Next week", ResetCompletion: true, DropQuantities: true}
+		rec := makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/lists/%s/clone", list.ID), req, userID)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var clone models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &clone)
+		rec = makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s/items", clone.ID), nil, userID)
+		var items models.ItemsResponse
+		json.Unmarshal(rec.Body.Bytes(), &items)
+		for _, item := range items.Data {
+			if item.Completed || item.Quantity != nil {
+				t.Errorf("Expected an open item without quantity, got %+v", item)
+			}
+		}
+	})
+
+	t.Run("Clone validates the owner", func(t *testing.T) {
+		rec := makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/lists/%s/clone", list.ID), models.CloneListRequest{OwnerID: "nobody"}, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for an unknown owner, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Clone into a workspace list", func(t *testing.T) {
+		workspace := createTestList(t, handler, userID, "Trips")
+		req := models.CloneListRequest{TargetListID: workspace.ID}
+		rec := makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/lists/%s/clone", list.ID), req, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status 400 for a list with nested lists, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		flat := createTestList(t, handler, userID, "Gear")
+		createTestItem(t, handler, userID, flat.ID, models.CreateItemRequest{Type: "item", Name: "Tent"})
+		rec = makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/lists/%s/clone", flat.ID), req, userID)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var clone models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &clone)
+
+		rec = makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s?expand=items,children", workspace.ID), nil, userID)
+		var expanded models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &expanded)
+		if len(expanded.Items) != 1 || expanded.Items[0].ID != clone.ID || expanded.Items[0].Name != "Gear (copy)" || len(expanded.Items[0].Children) != 1 {
+			t.Errorf("Expected the copy nested in the workspace with its item, got %+v", expanded.Items)
+		}
+	})
+
+	t.Run("Clone of a missing list returns 404", func(t *testing.T) {
+		rec := makeRequest(t, handler, "POST", "/api/v1/lists/missing/clone", models.CloneListRequest{}, userID)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+	})
+}
+
+func TestListTemplates(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-templates"
+
+	list := createTestList(t, handler, userID, "Weekly")
+	createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Bread"})
+	dairy := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "list", Name: "Dairy"})
+	createTestItem(t, handler, userID, dairy.ID, models.CreateItemRequest{Type: "item", Name: "Milk"})
+
+	var template models.TemplateResponse
+	t.Run("Save a list as a template", func(t *testing.T) {
+		req := models.CreateTemplateRequest{ListID: list.ID, Name: "Shopping {date}"}
+		rec := makeRequest(t, handler, "POST", "/api/v1/templates", req, userID)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		json.Unmarshal(rec.Body.Bytes(), &template)
+		if template.ItemCount != 2 || len(template.Items[1].Children) != 1 || template.Version != 1 {
+			t.Errorf("Expected 2 template items with a nested child, got %+v", template)
+		}
+
+		rec = makeRequest(t, handler, "GET", "/api/v1/lists", nil, userID)
+		var lists models.ListsResponse
+		json.Unmarshal(rec.Body.Bytes(), &lists)
+		if len(lists.Data) != 1 {
+			t.Errorf("Expected templates to stay out of the lists, got %d lists", len(lists.Data))
+		}
+	})
+
+	t.Run("Browse templates", func(t *testing.T) {
+		rec := makeRequest(t, handler, "GET", "/api/v1/templates", nil, userID)
+		var templates models.TemplatesResponse
+		json.Unmarshal(rec.Body.Bytes(), &templates)
+		if len(templates.Data) != 1 || templates.Data[0].ID != template.ID {
+			t.Errorf("Expected the saved template, got %+v", templates.Data)
+		}
+
+		rec = makeRequest(t, handler, "GET", "/api/v1/templates", nil, "someone-else")
+		json.Unmarshal(rec.Body.Bytes(), &templates)
+		if len(templates.Data) != 0 {
+			t.Errorf("Expected no templates of other users, got %+v", templates.Data)
+		}
+	})
+
+	t.Run("Update with a stale version conflicts", func(t *testing.T) {
+		req := models.UpdateTemplateRequest{Name: "Other", Version: template.Version + 1}
+		rec := makeRequest(t, handler, "PUT", "/api/v1/templates/"+template.ID, req, userID)
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Expected status 409, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Instantiate substitutes variables", func(t *testing.T) {
+		req := models.InstantiateTemplateRequest{Date: "2025-03-14"}
+		rec := makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/templates/%s/instantiate", template.ID), req, userID)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var created models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &created)
+		if created.Name != "Shopping 2025-03-14" || created.ItemCount != 2 {
+			t.Errorf("Expected 'Shopping 2025-03-14' with 2 items, got %+v", created)
+		}
+
+		rec = makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s?expand=items,children", created.ID), nil, userID)
+		var expanded models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &expanded)
+		if len(expanded.Items) != 2 || len(expanded.Items[1].Children) != 1 || expanded.Items[1].Children[0].Name != "Milk" {
+			t.Errorf("Expected the nested list to be instantiated, got %+v", expanded.Items)
+		}
+	})
+
+	t.Run("The default date is today in the user's time zone", func(t *testing.T) {
+		makeRequest(t, handler, "POST", "/api/v1/users/init", models.InitUserRequest{Username: userID, IconID: "icon1"}, "")
+		makeRequest(t, handler, "PATCH", "/api/v1/users/"+userID+"/preferences", models.UpdatePreferencesRequest{TimeZone: "Pacific/Kiritimati"}, userID)
+
+		rec := makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/templates/%s/instantiate", template.ID), models.InstantiateTemplateRequest{}, userID)
+		var created models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &created)
+		loc, _ := time.LoadLocation("Pacific/Kiritimati")
+		if want := "Shopping " + time.Now().In(loc).Format("2006-01-02"); created.Name != want {
+			t.Errorf("Expected %q, got %d %q", want, rec.Code, created.Name)
+		}
+	})
+
+	t.Run("Nested lists cannot contain lists", func(t *testing.T) {
+		req := models.CreateTemplateRequest{Name: "Bad", Items: []models.TemplateItem{
+			{Type: "list", Name: "Outer", Children: []models.TemplateItem{{Type: "list", Name: "Inner"}}},
+		}}
+		rec := makeRequest(t, handler, "POST", "/api/v1/templates", req, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Recurring template items keep their anchor day", func(t *testing.T) {
+		req := models.CreateTemplateRequest{Name: "Bills", Items: []models.TemplateItem{
+			{Type: "item", Name: "Rent", Recurrence: &models.Recurrence{Rule: "freq=monthly", AnchorDay: 31}},
+		}}
+		rec := makeRequest(t, handler, "POST", "/api/v1/templates", req, userID)
+		var bills models.TemplateResponse
+		json.Unmarshal(rec.Body.Bytes(), &bills)
+		if rec.Code != http.StatusCreated || bills.Items[0].Recurrence.AnchorDay != 31 || bills.Items[0].Recurrence.Rule != "FREQ=MONTHLY" {
+			t.Errorf("Expected the canonical rule with its anchor day, got %d %+v", rec.Code, bills.Items[0].Recurrence)
+		}
+	})
+
+	t.Run("Delete a template", func(t *testing.T) {
+		rec := makeRequest(t, handler, "DELETE", "/api/v1/templates/"+template.ID, models.DeleteTemplateRequest{Version: template.Version}, userID)
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("Expected status 204, got %d", rec.Code)
+		}
+		rec = makeRequest(t, handler, "GET", "/api/v1/templates/"+template.ID, nil, userID)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+	})
+}
+
+func TestScheduledListReset(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-reset"
+	listService := service.NewListService(repository.NewRepositories(mongoClient.Database("lists_viewer")))
+
+	list := createTestList(t, handler, userID, "Cleaning")
+	sweep := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Sweep"})
+	completeItem := func(item *models.ItemResponse) {
+		rec := makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s/items/%s", list.ID, item.ID), nil, userID)
+		json.Unmarshal(rec.Body.Bytes(), item)
+		completed := true
+		req := models.UpdateItemRequest{Name: item.Name, Completed: &completed, Version: item.Version, Order: item.Order}
+		rec = makeRequest(t, handler, "PUT", fmt.Sprintf("/api/v1/lists/%s/items/%s", list.ID, item.ID), req, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Failed to complete item: %d %s", rec.Code, rec.Body.String())
+		}
+	}
+	schedulePath := fmt.Sprintf("/api/v1/lists/%s/schedule", list.ID)
+
+	t.Run("Invalid schedules are rejected", func(t *testing.T) {
+		for _, req := range []models.SetResetScheduleRequest{
+			{Preset: "weekly", Time: "08:00"},
+			{Preset: "daily", Time: "8am"},
+			{Cron: "0 8 * *"},
+			{Preset: "daily", Time: "08:00", TimeZone: "Mars/Olympus"},
+		} {
+			rec := makeRequest(t, handler, "PUT", schedulePath, req, userID)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %+v, got %d", req, rec.Code)
+			}
+		}
+	})
+
+	var scheduled models.ListResponse
+	t.Run("Weekly preset runs on Monday morning in the list time zone", func(t *testing.T) {
+		req := models.SetResetScheduleRequest{Preset: "weekly", Weekday: "monday", Time: "08:00", TimeZone: "Asia/Jerusalem"}
+		rec := makeRequest(t, handler, "PUT", schedulePath, req, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		json.Unmarshal(rec.Body.Bytes(), &scheduled)
+
+		loc, _ := time.LoadLocation("Asia/Jerusalem")
+		next := scheduled.ResetSchedule.NextRunAt.In(loc)
+		if scheduled.ResetSchedule.Cron != "0 8 * * 1" || next.Weekday() != time.Monday || next.Hour() != 8 || next.Minute() != 0 {
+			t.Errorf("Expected the next run on Monday 08:00, got %+v (%s)", scheduled.ResetSchedule, next)
+		}
+	})
+
+	t.Run("Due resets uncomplete items once per run", func(t *testing.T) {
+		completeItem(&sweep)
+		runAt := scheduled.ResetSchedule.NextRunAt.Add(time.Minute)
+		if err := listService.RunDueResets(context.Background(), runAt); err != nil {
+			t.Fatalf("Failed to run resets: %v", err)
+		}
+		if err := listService.RunDueResets(context.Background(), runAt); err != nil {
+			t.Fatalf("Failed to run resets: %v", err)
+		}
+
+		rec := makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s/items/%s", list.ID, sweep.ID), nil, userID)
+		var item models.ItemResponse
+		json.Unmarshal(rec.Body.Bytes(), &item)
+		if item.Completed {
+			t.Error("Expected the item to be uncompleted")
+		}
+
+		rec = makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s/resets", list.ID), nil, userID)
+		var history models.ListResetsResponse
+		json.Unmarshal(rec.Body.Bytes(), &history)
+		if len(history.Data) != 1 || history.Data[0].Trigger != models.ResetTriggerSchedule || history.Data[0].ItemCount != 1 {
+			t.Errorf("Expected one scheduled reset of 1 item, got %+v", history.Data)
+		}
+
+		rec = makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s", list.ID), nil, userID)
+		var updated models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &updated)
+		if !updated.ResetSchedule.NextRunAt.After(runAt) {
+			t.Errorf("Expected the next run after %s, got %s", runAt, updated.ResetSchedule.NextRunAt)
+		}
+	})
+
+	t.Run("Manual reset restores the list from a template", func(t *testing.T) {
+		template := models.CreateTemplateRequest{Name: "Chores", Items: []models.TemplateItem{{Type: "item", Name: "Mop {weekday}"}}}
+		rec := makeRequest(t, handler, "POST", "/api/v1/templates", template, userID)
+		var created models.TemplateResponse
+		json.Unmarshal(rec.Body.Bytes(), &created)
+
+		req := models.ResetListRequest{Action: models.ResetActionTemplate, TemplateID: created.ID}
+		rec = makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/lists/%s/reset", list.ID), req, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		rec = makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s/items", list.ID), nil, userID)
+		var items models.ItemsResponse
+		json.Unmarshal(rec.Body.Bytes(), &items)
+		loc, _ := time.LoadLocation("Asia/Jerusalem")
+		if len(items.Data) != 1 || items.Data[0].Name != "Mop "+time.Now().In(loc).Weekday().String() {
+			t.Errorf("Expected the template items to replace the list items, got %+v", items.Data)
+		}
+	})
+
+	t.Run("Failed scheduled resets are retried", func(t *testing.T) {
+		template := models.CreateTemplateRequest{Name: "Gone", Items: []models.TemplateItem{{Type: "item", Name: "Dust"}}}
+		rec := makeRequest(t, handler, "POST", "/api/v1/templates", template, userID)
+		var created models.TemplateResponse
+		json.Unmarshal(rec.Body.Bytes(), &created)
+
+		req := models.SetResetScheduleRequest{Preset: "daily", Time: "08:00", Action: models.ResetActionTemplate, TemplateID: created.ID}
+		rec = makeRequest(t, handler, "PUT", schedulePath, req, userID)
+		var failing models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &failing)
+		makeRequest(t, handler, "DELETE", "/api/v1/templates/"+created.ID, models.DeleteTemplateRequest{Version: created.Version}, userID)
+
+		if err := listService.RunDueResets(context.Background(), failing.ResetSchedule.NextRunAt.Add(time.Minute)); err != nil {
+			t.Fatalf("Failed to run resets: %v", err)
+		}
+		rec = makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s", list.ID), nil, userID)
+		var updated models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &updated)
+		if !updated.ResetSchedule.NextRunAt.Equal(failing.ResetSchedule.NextRunAt) || updated.ResetSchedule.LastRunAt != nil {
+			t.Errorf("Expected the failed run to stay due, got %+v", updated.ResetSchedule)
+		}
+	})
+
+	t.Run("Delete the schedule", func(t *testing.T) {
+		rec := makeRequest(t, handler, "DELETE", schedulePath, nil, userID)
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("Expected status 204, got %d", rec.Code)
+		}
+		rec = makeRequest(t, handler, "GET", fmt.Sprintf("/api/v1/lists/%s", list.ID), nil, userID)
+		var updated models.ListResponse
+		json.Unmarshal(rec.Body.Bytes(), &updated)
+		if updated.ResetSchedule != nil {
+			t.Errorf("Expected no schedule, got %+v", updated.ResetSchedule)
+		}
+	})
+}
+
+func TestRecurringItems(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-recurring"
+	itemService := service.NewItemService(repository.NewRepositories(mongoClient.Database("lists_viewer")))
+
+	list := createTestList(t, handler, userID, "Household")
+	itemPath := func(item models.ItemResponse) string {
+		return fmt.Sprintf("/api/v1/lists/%s/items/%s", list.ID, item.ID)
+	}
+	getItem := func(item models.ItemResponse) models.ItemResponse {
+		rec := makeRequest(t, handler, "GET", itemPath(item), nil, userID)
+		var current models.ItemResponse
+		json.Unmarshal(rec.Body.Bytes(), &current)
+		return current
+	}
+
+	t.Run("Invalid rules are rejected", func(t *testing.T) {
+		for _, rule := range []string{"FREQ=YEARLY", "FREQ=WEEKLY;BYMONTHDAY=3", "INTERVAL=2", "FREQ=DAILY;COUNT=3"} {
+			req := models.CreateItemRequest{Type: "item", Name: "Bad", Recurrence: &models.SetRecurrenceRequest{Rule: rule}}
+			rec := makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/lists/%s/items", list.ID), req, userID)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %s, got %d", rule, rec.Code)
+			}
+		}
+	})
+
+	milk := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{
+		Type: "item", Name: "Milk", Recurrence: &models.SetRecurrenceRequest{Rule: "rrule:freq=daily;interval=2"},
+	})
+
+	var completed models.ItemResponse
+	t.Run("Completing schedules the next occurrence", func(t *testing.T) {
+		if milk.Recurrence == nil || milk.Recurrence.Rule != "FREQ=DAILY;INTERVAL=2" || milk.Recurrence.TimeZone != "UTC" {
+			t.Fatalf("Expected a canonical daily rule, got %+v", milk.Recurrence)
+		}
+
+		done := true
+		req := models.UpdateItemRequest{Name: milk.Name, Completed: &done, Version: milk.Version, Order: milk.Order}
+		rec := makeRequest(t, handler, "PUT", itemPath(milk), req, userID)
+		json.Unmarshal(rec.Body.Bytes(), &completed)
+
+		now := time.Now().UTC()
+		expected := time.Date(now.Year(), now.Month(), now.Day()+2, 0, 0, 0, 0, time.UTC)
+		if completed.Recurrence == nil || completed.Recurrence.NextAt == nil || !completed.Recurrence.NextAt.Equal(expected) {
+			t.Errorf("Expected the item to reopen at %s, got %+v", expected, completed.Recurrence)
+		}
+	})
+
+	t.Run("The job reopens due items once", func(t *testing.T) {
+		if err := itemService.ReopenRecurringItems(context.Background(), time.Now()); err != nil {
+			t.Fatalf("Failed to run job: %v", err)
+		}
+		if !getItem(milk).Completed {
+			t.Fatal("Expected the item to stay completed before its next occurrence")
+		}
+
+		runAt := completed.Recurrence.NextAt.Add(time.Minute)
+		for i := 0; i < 2; i++ {
+			if err := itemService.ReopenRecurringItems(context.Background(), runAt); err != nil {
+				t.Fatalf("Failed to run job: %v", err)
+			}
+		}
+
+		reopened := getItem(milk)
+		if reopened.Completed || reopened.Recurrence == nil || reopened.Recurrence.NextAt != nil {
+			t.Errorf("Expected an open item waiting for completion, got %+v", reopened)
+		}
+		if reopened.Version != completed.Version+1 {
+			t.Errorf("Expected a single reopen (version %d), got version %d", completed.Version+1, reopened.Version)
+		}
+	})
+
+	t.Run("Reopening moves the due date and reminder to the next occurrence", func(t *testing.T) {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		trash := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{
+			Type: "item", Name: "Take out trash", Due: today.Format("2006-01-02"), ReminderOffset: ptrInt32(60),
+			Recurrence: &models.SetRecurrenceRequest{Rule: "FREQ=DAILY"},
+		})
+		done := true
+		req := models.UpdateItemRequest{Name: trash.Name, Completed: &done, Version: trash.Version, Order: trash.Order}
+		rec := makeRequest(t, handler, "PUT", itemPath(trash), req, userID)
+		var completed models.ItemResponse
+		json.Unmarshal(rec.Body.Bytes(), &completed)
+		if completed.Recurrence == nil || completed.Recurrence.NextAt == nil {
+			t.Fatalf("Expected the next occurrence to be scheduled, got %+v", completed)
+		}
+
+		if err := itemService.ReopenRecurringItems(context.Background(), completed.Recurrence.NextAt.Add(time.Minute)); err != nil {
+			t.Fatalf("Failed to run job: %v", err)
+		}
+		reopened := getItem(trash)
+		tomorrow := today.AddDate(0, 0, 1)
+		if dueAt, _ := time.Parse(time.RFC3339, reopened.DueAt); !dueAt.Equal(tomorrow) {
+			t.Errorf("Expected the item to be due %s, got %s", tomorrow, reopened.DueAt)
+		}
+		if remindAt, _ := time.Parse(time.RFC3339, reopened.RemindAt); !remindAt.Equal(tomorrow.Add(-time.Hour)) {
+			t.Errorf("Expected a reminder an hour before, got %q", reopened.RemindAt)
+		}
+	})
+
+	t.Run("Set and remove recurrence on an existing item", func(t *testing.T) {
+		filter := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Change water filter"})
+		req := models.SetRecurrenceRequest{Rule: "FREQ=MONTHLY;BYMONTHDAY=1", TimeZone: "Asia/Jerusalem", Version: filter.Version}
+		rec := makeRequest(t, handler, "PUT", itemPath(filter)+"/recurrence", req, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var updated models.ItemResponse
+		json.Unmarshal(rec.Body.Bytes(), &updated)
+		if updated.Recurrence == nil || updated.Recurrence.TimeZone != "Asia/Jerusalem" {
+			t.Fatalf("Expected a monthly recurrence, got %+v", updated.Recurrence)
+		}
+
+		rec = makeRequest(t, handler, "DELETE", itemPath(filter)+"/recurrence", models.DeleteItemRequest{Version: updated.Version}, userID)
+		json.Unmarshal(rec.Body.Bytes(), &updated)
+		if rec.Code != http.StatusOK || updated.Recurrence != nil {
+			t.Errorf("Expected the recurrence to be removed, got %d %+v", rec.Code, updated.Recurrence)
+		}
+	})
+}
+
+// captureNotifier records the notifications it is asked to deliver
+type captureNotifier struct {
+	reminders []notify.Notification
+}
+
+func (n *captureNotifier) Notify(ctx context.Context, notification notify.Notification) error {
+	n.reminders = append(n.reminders, notification)
+	return nil
+}
+
+func TestDueDatesAndReminders(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "test-user-due"
+	notifier := &captureNotifier{}
+	reminderService := service.NewReminderService(repository.NewRepositories(mongoClient.Database("lists_viewer")), notifier)
+
+	makeRequest(t, handler, "POST", "/api/v1/users/init", models.InitUserRequest{Username: userID, IconID: "icon1"}, "")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+
+	t.Run("Time zone preference", func(t *testing.T) {
+		rec := makeRequest(t, handler, "PATCH", "/api/v1/users/"+userID+"/preferences", models.UpdatePreferencesRequest{TimeZone: "Mars/Olympus"}, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for an unknown time zone, got %d", rec.Code)
+		}
+
+		rec = makeRequest(t, handler, "PATCH", "/api/v1/users/"+userID+"/preferences", models.UpdatePreferencesRequest{TimeZone: "Asia/Tokyo"}, userID)
+		var user models.UserResponse
+		json.Unmarshal(rec.Body.Bytes(), &user)
+		if rec.Code != http.StatusOK || user.Preferences.TimeZone != "Asia/Tokyo" || user.Preferences.Theme != "dark" {
+			t.Fatalf("Expected the time zone to be updated and the theme kept, got %d %+v", rec.Code, user.Preferences)
+		}
+	})
+
+	list := createTestList(t, handler, userID, "Errands")
+	itemsPath := fmt.Sprintf("/api/v1/lists/%s/items", list.ID)
+	now := time.Now()
+
+	yesterday := now.In(tokyo).AddDate(0, 0, -1)
+	rent := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Pay rent", Due: yesterday.Format("2006-01-02")})
+	offset := int32(30)
+	plumber := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{
+		Type: "item", Name: "Call plumber", Due: now.Add(2 * time.Hour).Format(time.RFC3339), ReminderOffset: &offset,
+	})
+	dentist := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Dentist", Due: now.Add(72 * time.Hour).Format(time.RFC3339)})
+
+	t.Run("All-day items are due in the caller's time zone", func(t *testing.T) {
+		expected := time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 0, 0, 0, 0, tokyo).UTC().Format("2006-01-02T15:04:05Z")
+		if !rent.AllDay || rent.DueAt != expected || rent.RemindAt != "" {
+			t.Errorf("Expected an all-day item due at %s, got %+v", expected, rent)
+		}
+
+		remindAt, _ := time.Parse(time.RFC3339, plumber.RemindAt)
+		dueAt, _ := time.Parse(time.RFC3339, plumber.DueAt)
+		if plumber.AllDay || dueAt.Sub(remindAt) != 30*time.Minute {
+			t.Errorf("Expected a reminder 30 minutes before the due time, got %+v", plumber)
+		}
+
+		req := models.CreateItemRequest{Type: "item", Name: "Bad", Due: "next tuesday"}
+		if rec := makeRequest(t, handler, "POST", itemsPath, req, userID); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for an invalid due date, got %d", rec.Code)
+		}
+	})
+
+	dueView := func(path string) []models.DueItem {
+		rec := makeRequest(t, handler, "GET", path, nil, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var response models.DueItemsResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		return response.Data
+	}
+
+	t.Run("Overdue and due-soon views", func(t *testing.T) {
+		overdue := dueView("/api/v1/views/overdue")
+		if len(overdue) != 1 || overdue[0].Item.ID != rent.ID || !overdue[0].Overdue || overdue[0].ListName != "Errands" {
+			t.Errorf("Expected only the rent to be overdue, got %+v", overdue)
+		}
+
+		soon := dueView("/api/v1/views/due-soon?lists=" + list.ID)
+		if len(soon) != 1 || soon[0].Item.ID != plumber.ID || soon[0].Overdue {
+			t.Errorf("Expected only the plumber to be due within a day, got %+v", soon)
+		}
+
+		soon = dueView("/api/v1/views/due-soon?hours=96")
+		if len(soon) != 2 || soon[0].Item.ID != plumber.ID || soon[1].Item.ID != dentist.ID {
+			t.Errorf("Expected the plumber then the dentist within four days, got %+v", soon)
+		}
+
+		if rec := makeRequest(t, handler, "GET", "/api/v1/views/due-soon?hours=0", nil, userID); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for an empty window, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Reminders are sent once", func(t *testing.T) {
+		if err := reminderService.SendDueReminders(context.Background(), now); err != nil {
+			t.Fatalf("Failed to run job: %v", err)
+		}
+		if len(notifier.reminders) != 0 {
+			t.Fatalf("Expected no reminders before their time, got %+v", notifier.reminders)
+		}
+
+		runAt := now.Add(2 * time.Hour)
+		for i := 0; i < 2; i++ {
+			if err := reminderService.SendDueReminders(context.Background(), runAt); err != nil {
+				t.Fatalf("Failed to run job: %v", err)
+			}
+		}
+		if len(notifier.reminders) != 1 {
+			t.Fatalf("Expected a single reminder, got %+v", notifier.reminders)
+		}
+		reminder := notifier.reminders[0]
+		if reminder.UserID != userID || reminder.ItemID != plumber.ID || reminder.ListName != "Errands" || reminder.TimeZone != "Asia/Tokyo" {
+			t.Errorf("Unexpected reminder: %+v", reminder)
+		}
+	})
+
+	t.Run("Completed and cleared items leave the views", func(t *testing.T) {
+		done := true
+		req := models.UpdateItemRequest{Name: rent.Name, Completed: &done, Version: rent.Version, Order: rent.Order}
+		makeRequest(t, handler, "PUT", itemsPath+"/"+rent.ID, req, userID)
+		if overdue := dueView("/api/v1/views/overdue"); len(overdue) != 0 {
+			t.Errorf("Expected no overdue items, got %+v", overdue)
+		}
+
+		rec := makeRequest(t, handler, "DELETE", itemsPath+"/"+dentist.ID+"/due", models.DeleteItemRequest{Version: dentist.Version}, userID)
+		var cleared models.ItemResponse
+		json.Unmarshal(rec.Body.Bytes(), &cleared)
+		if rec.Code != http.StatusOK || cleared.DueAt != "" {
+			t.Errorf("Expected the due date to be removed, got %d %+v", rec.Code, cleared)
+		}
+
+		due := now.Add(5 * time.Hour).Format(time.RFC3339)
+		rec = makeRequest(t, handler, "PUT", itemsPath+"/"+dentist.ID+"/due", models.SetDueDateRequest{Due: due, Version: cleared.Version}, userID)
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if soon := dueView("/api/v1/views/due-soon"); len(soon) != 2 {
+			t.Errorf("Expected two items due within a day, got %+v", soon)
+		}
+	})
+}
+
+// pushServiceStandIn plays a browser's push service: it checks VAPID, decrypts messages with the browser keys and collects them
+type pushServiceStandIn struct {
+	server     *httptest.Server
+	browserKey *ecdh.PrivateKey
+	authSecret []byte
+	messages   chan notify.PushPayload
+}
+
+func newPushServiceStandIn(t *testing.T, vapidPublicKey string) *pushServiceStandIn {
+	standIn := &pushServiceStandIn{authSecret: make([]byte, 16), messages: make(chan notify.PushPayload, 10)}
+	standIn.browserKey, _ = ecdh.P256().GenerateKey(rand.Reader)
+	rand.Read(standIn.authSecret)
+
+	standIn.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, "vapid t=") || !strings.HasSuffix(authorization, ", k="+vapidPublicKey) || r.Header.Get("Content-Encoding") != "aes128gcm" {
+			t.Errorf("Unexpected push headers: %v", r.Header)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body := new(bytes.Buffer)
+		body.ReadFrom(r.Body)
+		plaintext, err := webpush.Decrypt(body.Bytes(), standIn.browserKey, standIn.authSecret)
+		if err != nil {
+			t.Errorf("Failed to decrypt push message: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var payload notify.PushPayload
+		json.Unmarshal(plaintext, &payload)
+		standIn.messages <- payload
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(standIn.server.Close)
+	return standIn
+}
+
+// subscription returns a registration request for the stand-in at the given path
+func (p *pushServiceStandIn) subscription(path string) models.RegisterPushSubscriptionRequest {
+	req := models.RegisterPushSubscriptionRequest{Endpoint: p.server.URL + path, DeviceName: "Phone"}
+	req.Keys.P256dh = base64.RawURLEncoding.EncodeToString(p.browserKey.PublicKey().Bytes())
+	req.Keys.Auth = base64.RawURLEncoding.EncodeToString(p.authSecret)
+	return req
+}
+
+// next waits for the next push message
+func (p *pushServiceStandIn) next(t *testing.T) notify.PushPayload {
+	select {
+	case payload := <-p.messages:
+		return payload
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a push message")
+		return notify.PushPayload{}
+	}
+}
+
+func TestPushNotifications(t *testing.T) {
+	clearDatabase(t)
+	cfg := testConfig(t)
+	handler := setup.SetupRouter(mongoClient, cfg)
+	owner, member := "push-owner", "push-member"
+	for _, username := range []string{owner, member} {
+		makeRequest(t, handler, "POST", "/api/v1/users/init", models.InitUserRequest{Username: username, IconID: "icon1"}, "")
+	}
+
+	var key models.PushKeyResponse
+	json.Unmarshal(makeRequest(t, handler, "GET", "/api/v1/push/key", nil, "").Body.Bytes(), &key)
+	if key.PublicKey != cfg.VAPIDKeys.PublicKey {
+		t.Fatalf("Expected the configured VAPID public key, got %q", key.PublicKey)
+	}
+	standIn := newPushServiceStandIn(t, key.PublicKey)
+
+	getSubscriptions := func() []models.PushSubscriptionResponse {
+		var response models.PushSubscriptionsResponse
+		json.Unmarshal(makeRequest(t, handler, "GET", "/api/v1/push/subscriptions", nil, member).Body.Bytes(), &response)
+		return response.Data
+	}
+
+	var phone models.PushSubscriptionResponse
+	t.Run("Register subscriptions", func(t *testing.T) {
+		invalid := standIn.subscription("/member")
+		invalid.Keys.Auth = "short"
+		if rec := makeRequest(t, handler, "POST", "/api/v1/push/subscriptions", invalid, member); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for an invalid auth secret, got %d", rec.Code)
+		}
+
+		for _, path := range []string{"/member", "/member", "/gone"} {
+			rec := makeRequest(t, handler, "POST", "/api/v1/push/subscriptions", standIn.subscription(path), member)
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if path == "/member" {
+				json.Unmarshal(rec.Body.Bytes(), &phone)
+			}
+		}
+		if subs := getSubscriptions(); len(subs) != 2 {
+			t.Errorf("Expected re-registering an endpoint to keep one subscription, got %+v", subs)
+		}
+	})
+
+	list := models.ListResponse{}
+	rec := makeRequest(t, handler, "POST", "/api/v1/lists", models.CreateListRequest{Name: "Groceries", Members: []string{owner, member}}, owner)
+	json.Unmarshal(rec.Body.Bytes(), &list)
+
+	t.Run("Members are notified of items added by others", func(t *testing.T) {
+		createTestItem(t, handler, owner, list.ID, models.CreateItemRequest{Type: "item", Name: "Bread"})
+
+		payload := standIn.next(t)
+		if payload.Notification.Type != notify.EventItemAdded || payload.Title != "New in Groceries" || payload.Body != "push-owner added Bread" {
+			t.Errorf("Unexpected push message: %+v", payload)
+		}
+		if payload.Notification.UserID != member || payload.URL != "/lists/"+list.ID {
+			t.Errorf("Expected a message for the member linking to the list, got %+v", payload)
+		}
+
+		// The push service rejected the gone endpoint, so its subscription is dropped
+		deadline := time.Now().Add(5 * time.Second)
+		for len(getSubscriptions()) != 1 && time.Now().Before(deadline) {
+			time.Sleep(50 * time.Millisecond)
+		}
+		if subs := getSubscriptions(); len(subs) != 1 || subs[0].ID != phone.ID {
+			t.Errorf("Expected only the phone subscription to remain, got %+v", subs)
+		}
+	})
+
+	t.Run("Reminders are pushed", func(t *testing.T) {
+		repos := repository.NewRepositories(mongoClient.Database("lists_viewer"))
+		reminderService := service.NewReminderService(repos, notify.NewPushNotifier(repos.Push, webpush.NewClient(cfg.VAPIDKeys, cfg.VAPIDSubject)))
+
+		offset := int32(60)
+		due := time.Now().Add(3 * time.Hour)
+		createTestItem(t, handler, member, list.ID, models.CreateItemRequest{Type: "item", Name: "Milk", Due: due.Format(time.RFC3339), ReminderOffset: &offset})
+		if err := reminderService.SendDueReminders(context.Background(), due); err != nil {
+			t.Fatalf("Failed to run job: %v", err)
+		}
+
+		payload := standIn.next(t)
+		if payload.Notification.Type != notify.EventReminder || payload.Title != "Milk is due" {
+			t.Errorf("Unexpected push message: %+v", payload)
+		}
+	})
+
+	t.Run("Unregister a subscription", func(t *testing.T) {
+		path := "/api/v1/push/subscriptions/" + phone.ID
+		if rec := makeRequest(t, handler, "DELETE", path, nil, owner); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for another user's subscription, got %d", rec.Code)
+		}
+		if rec := makeRequest(t, handler, "DELETE", path, nil, member); rec.Code != http.StatusNoContent {
+			t.Errorf("Expected status 204, got %d", rec.Code)
+		}
+		if subs := getSubscriptions(); len(subs) != 0 {
+			t.Errorf("Expected no subscriptions, got %+v", subs)
+		}
+	})
+
+	t.Run("Push is off without VAPID keys", func(t *testing.T) {
+		handler := setup.SetupRouter(mongoClient, &config.Config{DatabaseName: "lists_viewer"})
+		if rec := makeRequest(t, handler, "GET", "/api/v1/push/key", nil, ""); rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected status 503, got %d", rec.Code)
+		}
+		createTestItem(t, handler, owner, list.ID, models.CreateItemRequest{Type: "item", Name: "Butter"})
+	})
+}
+
+// webhookDelivery is a request received by a webhook receiver
+type webhookDelivery struct {
+	header http.Header
+	event  models.WebhookEvent
+}
+
+func TestOutboundWebhooks(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	webhookService := service.NewWebhookService(repository.NewRepositories(mongoClient.Database("lists_viewer")))
+	userID, other := "webhook-user", "webhook-other"
+	list := createTestList(t, handler, userID, "Groceries")
+	itemsPath := fmt.Sprintf("/api/v1/lists/%s/items", list.ID)
+
+	var secret string
+	var failing atomic.Bool
+	received := make(chan webhookDelivery, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := new(bytes.Buffer)
+		body.ReadFrom(r.Body)
+		signature := "sha256=" + service.SignWebhook(secret, r.Header.Get("X-Webhook-Timestamp"), body.Bytes())
+		if r.Header.Get("X-Webhook-Signature") != signature {
+			t.Errorf("Invalid webhook signature: %v", r.Header)
+		}
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var event models.WebhookEvent
+		json.Unmarshal(body.Bytes(), &event)
+		received <- webhookDelivery{header: r.Header, event: event}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	deliver := func(now time.Time) {
+		if err := webhookService.DeliverDue(context.Background(), now); err != nil {
+			t.Fatalf("Failed to run job: %v", err)
+		}
+	}
+	next := func(t *testing.T) webhookDelivery {
+		select {
+		case delivery := <-received:
+			return delivery
+		default:
+			t.Fatal("Expected a webhook delivery")
+			return webhookDelivery{}
+		}
+	}
+	getDeliveries := func(webhookID string) []models.WebhookDeliveryResponse {
+		var response models.WebhookDeliveriesResponse
+		json.Unmarshal(makeRequest(t, handler, "GET", "/api/v1/webhooks/"+webhookID+"/deliveries", nil, userID).Body.Bytes(), &response)
+		return response.Data
+	}
+
+	var webhook models.WebhookResponse
+	t.Run("Create a webhook", func(t *testing.T) {
+		invalid := []models.CreateWebhookRequest{
+			{URL: "ftp://example.com/hook"},
+			{URL: receiver.URL, Events: []string{"item.exploded"}},
+		}
+		for _, req := range invalid {
+			if rec := makeRequest(t, handler, "POST", "/api/v1/webhooks", req, userID); rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %+v, got %d", req, rec.Code)
+			}
+		}
+		if rec := makeRequest(t, handler, "POST", "/api/v1/webhooks", models.CreateWebhookRequest{URL: receiver.URL, ListID: "missing"}, userID); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for an unknown list, got %d", rec.Code)
+		}
+
+		req := models.CreateWebhookRequest{URL: receiver.URL, ListID: list.ID, Events: []string{models.WebhookEventItemCreated, models.WebhookEventItemCompleted}}
+		rec := makeRequest(t, handler, "POST", "/api/v1/webhooks", req, userID)
+		json.Unmarshal(rec.Body.Bytes(), &webhook)
+		if rec.Code != http.StatusCreated || len(webhook.Secret) != 64 || !webhook.Active {
+			t.Fatalf("Expected an active webhook with a secret, got %d %+v", rec.Code, webhook)
+		}
+		secret = webhook.Secret
+
+		var fetched models.WebhookResponse
+		json.Unmarshal(makeRequest(t, handler, "GET", "/api/v1/webhooks/"+webhook.ID, nil, userID).Body.Bytes(), &fetched)
+		if fetched.ID != webhook.ID || fetched.Secret != "" {
+			t.Errorf("Expected the webhook without its secret, got %+v", fetched)
+		}
+		if rec := makeRequest(t, handler, "GET", "/api/v1/webhooks/"+webhook.ID, nil, other); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for another user's webhook, got %d", rec.Code)
+		}
+	})
+
+	var eggs models.ItemResponse
+	t.Run("Signed deliveries of subscribed events", func(t *testing.T) {
+		eggs = createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Eggs"})
+		deliver(time.Now())
+
+		delivery := next(t)
+		if delivery.header.Get("X-Webhook-Event") != models.WebhookEventItemCreated || delivery.event.Type != models.WebhookEventItemCreated {
+			t.Errorf("Expected an item.created delivery, got %+v", delivery)
+		}
+		if delivery.event.Actor != userID || delivery.event.List.ID != list.ID || delivery.event.List.Name != "Groceries" || delivery.event.Item.ID != eggs.ID {
+			t.Errorf("Unexpected event: %+v", delivery.event)
+		}
+
+		// Renaming is an item.updated event, which the webhook is not subscribed to
+		rec := makeRequest(t, handler, "PUT", itemsPath+"/"+eggs.ID, models.UpdateItemRequest{Name: "Free-range eggs", Order: eggs.Order, Version: eggs.Version}, userID)
+		json.Unmarshal(rec.Body.Bytes(), &eggs)
+		rec = makeRequest(t, handler, "PUT", itemsPath+"/"+eggs.ID, models.UpdateItemRequest{Name: eggs.Name, Order: eggs.Order, Completed: ptrBool(true), Version: eggs.Version}, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		deliver(time.Now())
+
+		delivery = next(t)
+		if delivery.event.Type != models.WebhookEventItemCompleted || !delivery.event.Item.Completed {
+			t.Errorf("Expected an item.completed delivery, got %+v", delivery.event)
+		}
+		if len(received) != 0 {
+			t.Errorf("Expected no other deliveries, got %d", len(received))
+		}
+	})
+
+	t.Run("Failed deliveries are retried with backoff", func(t *testing.T) {
+		failing.Store(true)
+		createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Milk"})
+		now := time.Now()
+		deliver(now)
+
+		deliveries := getDeliveries(webhook.ID)
+		if len(deliveries) != 3 || deliveries[0].Status != models.DeliveryStatusPending || deliveries[0].Attempts != 1 || deliveries[0].LastStatusCode != 500 {
+			t.Fatalf("Expected the newest delivery to be pending after a failed attempt, got %+v", deliveries)
+		}
+
+		failing.Store(false)
+		deliver(now.Add(10 * time.Second))
+		if len(received) != 0 {
+			t.Fatal("Expected no attempt before the backoff elapsed")
+		}
+		deliver(now.Add(31 * time.Second))
+		if delivery := next(t); delivery.event.Item.Name != "Milk" {
+			t.Errorf("Expected the retried delivery, got %+v", delivery.event)
+		}
+		if deliveries = getDeliveries(webhook.ID); deliveries[0].Status != models.DeliveryStatusDelivered || deliveries[0].Attempts != 2 {
+			t.Errorf("Expected the delivery to succeed on the second attempt, got %+v", deliveries[0])
+		}
+	})
+
+	t.Run("Manual redelivery of a failed delivery", func(t *testing.T) {
+		failing.Store(true)
+		createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Butter"})
+		now := time.Now()
+		for i := 0; i < 8; i++ {
+			deliver(now.Add(time.Duration(i) * 2 * time.Hour))
+		}
+
+		failed := getDeliveries(webhook.ID)[0]
+		if failed.Status != models.DeliveryStatusFailed || failed.Attempts != 8 || failed.NextAttemptAt != "" || failed.LastError == "" {
+			t.Fatalf("Expected the delivery to fail after 8 attempts, got %+v", failed)
+		}
+
+		failing.Store(false)
+		path := fmt.Sprintf("/api/v1/webhooks/%s/deliveries/%s/redeliver", webhook.ID, failed.ID)
+		rec := makeRequest(t, handler, "POST", path, nil, userID)
+		var redelivery models.WebhookDeliveryResponse
+		json.Unmarshal(rec.Body.Bytes(), &redelivery)
+		if rec.Code != http.StatusAccepted || redelivery.RedeliveryOf != failed.ID || redelivery.EventID != failed.EventID || redelivery.Status != models.DeliveryStatusPending {
+			t.Fatalf("Expected a pending redelivery, got %d %+v", rec.Code, redelivery)
+		}
+		deliver(time.Now())
+		if delivery := next(t); delivery.event.ID != failed.EventID || delivery.header.Get("X-Webhook-Id") != redelivery.ID {
+			t.Errorf("Expected the same event in a new delivery, got %+v", delivery)
+		}
+
+		rec = makeRequest(t, handler, "POST", fmt.Sprintf("/api/v1/webhooks/%s/deliveries/missing/redeliver", webhook.ID), nil, userID)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for an unknown delivery, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Moves, merges and resets are published", func(t *testing.T) {
+		rec := makeRequest(t, handler, "PUT", "/api/v1/webhooks/"+webhook.ID, models.UpdateWebhookRequest{URL: receiver.URL, ListID: list.ID, Active: true, Version: webhook.Version}, userID)
+		json.Unmarshal(rec.Body.Bytes(), &webhook)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		events := func() map[string][]string {
+			deliver(time.Now())
+			types := map[string][]string{}
+			for len(received) > 0 {
+				delivery := <-received
+				types[delivery.event.Type] = append(types[delivery.event.Type], delivery.event.Item.ID)
+			}
+			return types
+		}
+
+		// Moving to another list removes the item from this list's point of view
+		pantry := createTestList(t, handler, userID, "Pantry")
+		rice := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Rice"})
+		events()
+		rec = makeRequest(t, handler, "PATCH", itemsPath+"/"+rice.ID+"/move", models.MoveItemRequest{TargetListID: pantry.ID, Order: 1, Version: rice.Version}, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got := events(); len(got) != 1 || !slices.Equal(got[models.WebhookEventItemDeleted], []string{rice.ID}) {
+			t.Errorf("Expected an item.deleted event for the moved item, got %v", got)
+		}
+
+		oats := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Oats"})
+		moreOats := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "oats"})
+		events()
+		rec = makeRequest(t, handler, "POST", itemsPath+"/merge", models.MergeItemsRequest{KeepID: oats.ID, Items: []models.BulkItemRef{{ID: oats.ID}, {ID: moreOats.ID}}}, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got := events(); !slices.Equal(got[models.WebhookEventItemDeleted], []string{moreOats.ID}) || !slices.Equal(got[models.WebhookEventItemUpdated], []string{oats.ID}) {
+			t.Errorf("Expected the merged item deleted and the kept one updated, got %v", got)
+		}
+
+		// Eggs were completed earlier
+		rec = makeRequest(t, handler, "POST", "/api/v1/lists/"+list.ID+"/reset", models.ResetListRequest{}, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got := events(); !slices.Equal(got[models.WebhookEventItemUncompleted], []string{eggs.ID}) {
+			t.Errorf("Expected an item.uncompleted event for the reset item, got %v", got)
+		}
+	})
+
+	t.Run("Update and delete a webhook", func(t *testing.T) {
+		req := models.UpdateWebhookRequest{URL: receiver.URL, Active: false, Version: webhook.Version}
+		rec := makeRequest(t, handler, "PUT", "/api/v1/webhooks/"+webhook.ID, req, userID)
+		json.Unmarshal(rec.Body.Bytes(), &webhook)
+		if rec.Code != http.StatusOK || webhook.Active || webhook.ListID != "" || len(webhook.Events) != 0 {
+			t.Fatalf("Expected an inactive webhook for every list and event, got %d %+v", rec.Code, webhook)
+		}
+		createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Flour"})
+		deliver(time.Now())
+		if len(received) != 0 {
+			t.Error("Expected no deliveries for an inactive webhook")
+		}
+
+		if rec := makeRequest(t, handler, "DELETE", "/api/v1/webhooks/"+webhook.ID, models.DeleteWebhookRequest{Version: webhook.Version - 1}, userID); rec.Code != http.StatusConflict {
+			t.Errorf("Expected status 409 for a stale version, got %d", rec.Code)
+		}
+		if rec := makeRequest(t, handler, "DELETE", "/api/v1/webhooks/"+webhook.ID, models.DeleteWebhookRequest{Version: webhook.Version}, userID); rec.Code != http.StatusNoContent {
+			t.Errorf("Expected status 204, got %d", rec.Code)
+		}
+		if rec := makeRequest(t, handler, "GET", "/api/v1/webhooks/"+webhook.ID+"/deliveries", nil, userID); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 after deletion, got %d", rec.Code)
+		}
+	})
+}
+
+func TestInboundQuickAdd(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "inbound-user"
+
+	rec := makeRequest(t, handler, "POST", "/api/v1/lists", models.CreateListRequest{Name: "Groceries", Aliases: []string{"shopping", " Shopping ", ""}}, userID)
+	var groceries models.ListResponse
+	json.Unmarshal(rec.Body.Bytes(), &groceries)
+	if len(groceries.Aliases) != 1 || groceries.Aliases[0] != "shopping" {
+		t.Fatalf("Expected the aliases without blanks and repeats, got %+v", groceries.Aliases)
+	}
+	hardware := createTestList(t, handler, userID, "Hardware store")
+	getItems := func(listID string) []models.ItemResponse {
+		var response models.ItemsResponse
+		json.Unmarshal(makeRequest(t, handler, "GET", "/api/v1/lists/"+listID+"/items", nil, userID).Body.Bytes(), &response)
+		return response.Data
+	}
+
+	var token models.InboundTokenResponse
+	t.Run("Create a token", func(t *testing.T) {
+		if rec := makeRequest(t, handler, "POST", "/api/v1/inbound/tokens", models.CreateInboundTokenRequest{Name: "Siri", ListID: "missing"}, userID); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for an unknown default list, got %d", rec.Code)
+		}
+
+		rec := makeRequest(t, handler, "POST", "/api/v1/inbound/tokens", models.CreateInboundTokenRequest{Name: "Siri", ListID: groceries.ID}, userID)
+		json.Unmarshal(rec.Body.Bytes(), &token)
+		if rec.Code != http.StatusCreated || token.Token == "" || token.ListID != groceries.ID {
+			t.Fatalf("Expected a token, got %d %+v", rec.Code, token)
+		}
+
+		var tokens models.InboundTokensResponse
+		json.Unmarshal(makeRequest(t, handler, "GET", "/api/v1/inbound/tokens", nil, userID).Body.Bytes(), &tokens)
+		if len(tokens.Data) != 1 || tokens.Data[0].Token != "" {
+			t.Errorf("Expected the token to be listed without its value, got %+v", tokens.Data)
+		}
+	})
+
+	quickAdd := func(contentType string, body string, header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/inbound/quick-add", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		if header != "" {
+			req.Header.Set("Authorization", "Bearer "+header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Plain text names the list", func(t *testing.T) {
+		rec := quickAdd("text/plain", "Add 2 kg tomatoes to the shopping list", token.Token)
+		if rec.Code != http.StatusCreated || rec.Body.String() != "Added 2 kg tomatoes to Groceries" {
+			t.Fatalf("Expected a plain-text confirmation, got %d %q", rec.Code, rec.Body.String())
+		}
+
+		rec = quickAdd("text/plain", "nails x20 to hardware store", token.Token)
+		if rec.Code != http.StatusCreated || rec.Body.String() != "Added 20 nails to Hardware store" {
+			t.Errorf("Expected the item on the hardware list, got %d %q", rec.Code, rec.Body.String())
+		}
+
+		items := getItems(groceries.ID)
+		if len(items) != 1 || items[0].Name != "tomatoes" || *items[0].Quantity != 2 || items[0].QuantityType != "kg" || items[0].CreatedBy != userID {
+			t.Errorf("Expected 2 kg tomatoes added by the token's user, got %+v", items)
+		}
+		if items := getItems(hardware.ID); len(items) != 1 {
+			t.Errorf("Expected one item on the hardware list, got %+v", items)
+		}
+	})
+
+	t.Run("JSON and the default list", func(t *testing.T) {
+		rec := quickAdd("application/json", `{"text": "eggs"}`, token.Token)
+		var response models.InboundQuickAddResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if rec.Code != http.StatusCreated || response.Message != "Added eggs to Groceries" || response.ListID != groceries.ID || response.Item.Name != "eggs" {
+			t.Errorf("Expected eggs on the default list, got %d %+v", rec.Code, response)
+		}
+
+		rec = quickAdd("application/json", `{"text": "eggs", "list": "Groceries"}`, token.Token)
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if rec.Code != http.StatusCreated || response.Message != "Added eggs to Groceries (it was already on the list)" {
+			t.Errorf("Expected the duplicate to be mentioned, got %d %+v", rec.Code, response)
+		}
+
+		if rec := quickAdd("application/json", `{"text": "eggs", "list": "Bakery"}`, token.Token); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for an unknown list, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Tokens are required", func(t *testing.T) {
+		if rec := quickAdd("text/plain", "eggs", ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 without a token, got %d", rec.Code)
+		}
+		if rec := quickAdd("text/plain", "eggs", "not-a-token"); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 for an unknown token, got %d", rec.Code)
+		}
+
+		req := httptest.NewRequest("POST", "/api/v1/inbound/quick-add?token="+token.Token, strings.NewReader("screws"))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 for a token in the query, got %d", rec.Code)
+		}
+
+		req = httptest.NewRequest("POST", "/api/v1/inbound/quick-add?list=hardware+store", strings.NewReader("screws"))
+		req.Header.Set("Authorization", "Bearer "+token.Token)
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated || rec.Body.String() != "Added screws to Hardware store" {
+			t.Errorf("Expected the list from the query, got %d %q", rec.Code, rec.Body.String())
+		}
+
+		if rec := makeRequest(t, handler, "DELETE", "/api/v1/inbound/tokens/"+token.ID, nil, "someone-else"); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for another user's token, got %d", rec.Code)
+		}
+		if rec := makeRequest(t, handler, "DELETE", "/api/v1/inbound/tokens/"+token.ID, nil, userID); rec.Code != http.StatusNoContent {
+			t.Errorf("Expected status 204, got %d", rec.Code)
+		}
+		if rec := quickAdd("text/plain", "eggs", token.Token); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 for a revoked token, got %d", rec.Code)
+		}
+	})
+}
+
+func TestMailToList(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "mail-user"
+	repos := repository.NewRepositories(mongoClient.Database("lists_viewer"))
+
+	rec := makeRequest(t, handler, "POST", "/api/v1/lists", models.CreateListRequest{Name: "Groceries", Aliases: []string{"shopping"}}, userID)
+	var groceries models.ListResponse
+	json.Unmarshal(rec.Body.Bytes(), &groceries)
+	hardware := createTestList(t, handler, userID, "Hardware store")
+
+	rec = makeRequest(t, handler, "POST", "/api/v1/inbound/tokens", models.CreateInboundTokenRequest{Name: "Mail", ListID: groceries.ID, Senders: []string{"Me <Me@Example.com>"}}, userID)
+	var token models.InboundTokenResponse
+	json.Unmarshal(rec.Body.Bytes(), &token)
+	if rec.Code != http.StatusCreated || len(token.Senders) != 1 || token.Senders[0] != "me@example.com" {
+		t.Fatalf("Expected a token with a normalized sender, got %d %+v", rec.Code, token)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := smtpd.NewServer("", "lists.test", service.NewMailService(repos, service.NewItemService(repos)))
+	go server.Serve(listener)
+	defer server.Close()
+
+	send := func(from string, to string, message string) error {
+		return smtp.SendMail(listener.Addr().String(), nil, from, []string{to}, []byte(strings.ReplaceAll(message, "\n", "\r\n")))
+	}
+	itemNames := func(listID string) []string {
+		var response models.ItemsResponse
+		json.Unmarshal(makeRequest(t, handler, "GET", "/api/v1/lists/"+listID+"/items", nil, userID).Body.Bytes(), &response)
+		names := []string{}
+		for _, item := range response.Data {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+
+	t.Run("Plain-text lines become items", func(t *testing.T) {
+		message := "From: me@example.com\nSubject: shopping\n\nHi,\n- 2 kg tomatoes\n* eggs x12\n\n> old reply\n-- \nSent from my phone\n"
+		if err := send("me@example.com", "shopping+"+token.Token+"@lists.test", message); err != nil {
+			t.Fatalf("Failed to send mail: %v", err)
+		}
+		if names := itemNames(groceries.ID); strings.Join(names, ",") != "tomatoes,eggs" {
+			t.Errorf("Expected tomatoes and eggs, got %v", names)
+		}
+	})
+
+	t.Run("HTML bullets become items", func(t *testing.T) {
+		message := "From: me@example.com\nMIME-Version: 1.0\nContent-Type: multipart/alternative; boundary=b\n\n" +
+			"--b\nContent-Type: text/html; charset=utf-8\nContent-Transfer-Encoding: quoted-printable\n\n" +
+			"<p>Ingredients:</p><ul><li>Nails x20</li><li>Wood =\n glue</li></ul>\n--b--\n"
+		if err := send("me@example.com", "hardware-store+"+token.Token+"@lists.test", message); err != nil {
+			t.Fatalf("Failed to send mail: %v", err)
+		}
+		if names := itemNames(hardware.ID); strings.Join(names, ",") != "Nails,Wood glue" {
+			t.Errorf("Expected nails and wood glue, got %v", names)
+		}
+	})
+
+	t.Run("The token's list is the default", func(t *testing.T) {
+		if err := send("me@example.com", token.Token+"@lists.test", "From: me@example.com\nSubject: more\n\nbread\n"); err != nil {
+			t.Fatalf("Failed to send mail: %v", err)
+		}
+		if names := itemNames(groceries.ID); len(names) != 3 || names[2] != "bread" {
+			t.Errorf("Expected bread on the default list, got %v", names)
+		}
+	})
+
+	t.Run("Unauthorized mail is rejected", func(t *testing.T) {
+		if err := send("stranger@example.com", "groceries+"+token.Token+"@lists.test", "\nbeer\n"); err == nil || !strings.HasPrefix(err.Error(), "550") {
+			t.Errorf("Expected an unknown sender to be rejected, got %v", err)
+		}
+		if err := send("me@example.com", "groceries+not-a-token@lists.test", "\nbeer\n"); err == nil || !strings.HasPrefix(err.Error(), "550") {
+			t.Errorf("Expected an unknown token to be rejected, got %v", err)
+		}
+		if err := send("me@example.com", "bakery+"+token.Token+"@lists.test", "\nbeer\n"); err == nil || !strings.HasPrefix(err.Error(), "550") {
+			t.Errorf("Expected an unknown list to be rejected, got %v", err)
+		}
+		if err := send("me@example.com", "groceries+"+token.Token+"@lists.test", "From: stranger@example.com\n\nbeer\n"); err == nil || !strings.HasPrefix(err.Error(), "550") {
+			t.Errorf("Expected an unknown author to be rejected, got %v", err)
+		}
+		if names := itemNames(groceries.ID); len(names) != 3 {
+			t.Errorf("Expected no items from rejected mail, got %v", names)
+		}
+	})
+}
+
+// mailSink is an SMTP backend keeping the messages it receives
+type mailSink struct {
+	mu       sync.Mutex
+	messages [][]byte
+}
+
+func (s *mailSink) Recipient(ctx context.Context, from string, to string) error {
+	return nil
+}
+
+func (s *mailSink) Deliver(ctx context.Context, from string, to []string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, data)
+	return nil
+}
+
+func (s *mailSink) received() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.messages)
+}
+
+func TestEmailDigests(t *testing.T) {
+	clearDatabase(t)
+	handler := setupTestRouter(t)
+	userID := "digest-user"
+	ctx := context.Background()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	sink := &mailSink{}
+	server := smtpd.NewServer("", "sink.test", sink)
+	go server.Serve(listener)
+	defer server.Close()
+
+	relay := mailer.New(listener.Addr().String(), "", "", "Lists <lists@lists.test>")
+	digestService := service.NewDigestService(repository.NewRepositories(mongoClient.Database("lists_viewer")), relay)
+
+	makeRequest(t, handler, "POST", "/api/v1/users/init", models.InitUserRequest{Username: userID, IconID: "icon1"}, "")
+	preferencesPath := "/api/v1/users/" + userID + "/preferences"
+
+	t.Run("Opting in needs an email address", func(t *testing.T) {
+		rec := makeRequest(t, handler, "PATCH", preferencesPath, models.UpdatePreferencesRequest{Digest: "daily"}, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 without an email address, got %d", rec.Code)
+		}
+		rec = makeRequest(t, handler, "PATCH", preferencesPath, models.UpdatePreferencesRequest{Email: "not an address", Digest: "daily"}, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for an invalid email address, got %d", rec.Code)
+		}
+		rec = makeRequest(t, handler, "PATCH", preferencesPath, models.UpdatePreferencesRequest{Email: "Dana <dana@example.com>", Digest: "hourly"}, userID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for an unknown frequency, got %d", rec.Code)
+		}
+
+		rec = makeRequest(t, handler, "PATCH", preferencesPath, models.UpdatePreferencesRequest{Email: "Dana <dana@example.com>", Digest: "daily"}, userID)
+		var user models.UserResponse
+		json.Unmarshal(rec.Body.Bytes(), &user)
+		if rec.Code != http.StatusOK || user.Preferences.Email != "dana@example.com" || user.Preferences.Digest != "daily" {
+			t.Fatalf("Expected the digest to be turned on, got %d %+v", rec.Code, user.Preferences)
+		}
+	})
+
+	now := time.Now().UTC()
+	slot := time.Date(now.Year(), now.Month(), now.Day(), 7, 0, 0, 0, time.UTC)
+	if !slot.After(now) {
+		slot = slot.AddDate(0, 0, 1)
+	}
+
+	list := createTestList(t, handler, userID, "Groceries")
+	quantity := 2.0
+	createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Tomatoes", Quantity: &quantity, QuantityType: "kg"})
+	eggs := createTestItem(t, handler, userID, list.ID, models.CreateItemRequest{Type: "item", Name: "Eggs"})
+	completed := true
+	rec := makeRequest(t, handler, "PUT", fmt.Sprintf("/api/v1/lists/%s/items/%s", list.ID, eggs.ID), models.UpdateItemRequest{Name: "Eggs", Completed: &completed, Version: eggs.Version}, userID)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Failed to complete item: %d %s", rec.Code, rec.Body.String())
+	}
+	other := createTestList(t, handler, "someone-else", "Secret plans")
+	createTestItem(t, handler, "someone-else", other.ID, models.CreateItemRequest{Type: "item", Name: "Surprise party"})
+
+	t.Run("No digest before the next digest time", func(t *testing.T) {
+		if err := digestService.SendDueDigests(ctx, now); err != nil {
+			t.Fatalf("Failed to send digests: %v", err)
+		}
+		if messages := sink.received(); len(messages) != 0 {
+			t.Errorf("Expected no digest right after opting in, got %d", len(messages))
+		}
+	})
+
+	t.Run("A digest that cannot be mailed is retried", func(t *testing.T) {
+		unreachable := mailer.New("127.0.0.1:1", "", "", "Lists <lists@lists.test>")
+		failing := service.NewDigestService(repository.NewRepositories(mongoClient.Database("lists_viewer")), unreachable)
+		if err := failing.SendDueDigests(ctx, slot.Add(time.Minute)); err != nil {
+			t.Fatalf("Failed to send digests: %v", err)
+		}
+		if messages := sink.received(); len(messages) != 0 {
+			t.Errorf("Expected no digest, got %d", len(messages))
+		}
+	})
+
+	t.Run("Daily digest is sent once as text and HTML", func(t *testing.T) {
+		at := slot.Add(time.Minute)
+		for i := 0; i < 2; i++ {
+			if err := digestService.SendDueDigests(ctx, at); err != nil {
+				t.Fatalf("Failed to send digests: %v", err)
+			}
+		}
+		messages := sink.received()
+		if len(messages) != 1 {
+			t.Fatalf("Expected exactly one digest, got %d", len(messages))
+		}
+
+		msg, err := mail.ReadMessage(bytes.NewReader(messages[0]))
+		if err != nil {
+			t.Fatalf("Failed to parse digest: %v", err)
+		}
+		subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+		if msg.Header.Get("To") != "<dana@example.com>" || !strings.Contains(subject, "daily") {
+			t.Errorf("Expected a daily digest to dana, got to=%q subject=%q", msg.Header.Get("To"), subject)
+		}
+
+		mediaType, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+		if mediaType != "multipart/alternative" {
+			t.Fatalf("Expected a multipart/alternative digest, got %s", mediaType)
+		}
+		bodies := map[string]string{}
+		reader := multipart.NewReader(msg.Body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+			body, _ := io.ReadAll(part)
+			bodies[partType] = string(body)
+		}
+
+		text, html := bodies["text/plain"], bodies["text/html"]
+		for _, want := range []string{"Groceries", "+ 2 kg Tomatoes (by digest-user)", "x Eggs (by digest-user)", "- 2 kg Tomatoes", "1 still open"} {
+			if !strings.Contains(text, want) {
+				t.Errorf("Expected the text digest to contain %q, got:\n%s", want, text)
+			}
+		}
+		if !strings.Contains(html, "<s>Eggs</s>") || !strings.Contains(html, "<li>2 kg Tomatoes") {
+			t.Errorf("Expected the HTML digest to list the items, got:\n%s", html)
+		}
+		if strings.Contains(text, "Surprise") || strings.Contains(html, "Surprise") {
+			t.Error("Expected lists of other users to be left out")
+		}
+	})
+
+	t.Run("Opting out stops digests", func(t *testing.T) {
+		rec := makeRequest(t, handler, "PATCH", preferencesPath, models.UpdatePreferencesRequest{Digest: "off"}, userID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+		if err := digestService.SendDueDigests(ctx, slot.AddDate(0, 0, 1).Add(time.Minute)); err != nil {
+			t.Fatalf("Failed to send digests: %v", err)
+		}
+		if messages := sink.received(); len(messages) != 1 {
+			t.Errorf("Expected no digest after opting out, got %d messages", len(messages))
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Dimensions of measurement
+const (
+	DimensionMass   = "mass"
+	DimensionVolume = "volume"
+	DimensionCount  = "count"
+)
+
+// Unit represents a known quantity unit
+type Unit struct {
+	Code      string   `json:"code"`      // Canonical name stored as the item quantity type
+	Dimension string   `json:"dimension"` // "mass", "volume" or "count"
+	Factor    float64  `json:"-"`         // Size of the unit in the base unit of its dimension (g, ml, pcs)
+	Aliases   []string `json:"aliases"`   // Alternative spellings, in several languages
+}
+
+// registry holds the known units; the first unit of each dimension is its base unit
+// Single-letter Hebrew abbreviations ("ל", "ג") are left out since they are also common words and prefixes
+var registry = []Unit{
+	// Mass
+	{Code: "g", Dimension: DimensionMass, Factor: 1, Aliases: []string{"gr", "grs", "gram", "grams", "gramme", "grammes", "gramm", "gramo", "gramos", "גרם", "גרמים", "גר"}},
+	{Code: "mg", Dimension: DimensionMass, Factor: 0.001, Aliases: []string{"milligram", "milligrams", "miligramo", "מג", "מיליגרם"}},
+	{Code: "kg", Dimension: DimensionMass, Factor: 1000, Aliases: []string{"kgs", "kilo", "kilos", "kilogram", "kilograms", "kilogramme", "kilogramm", "kilogramo", "kilogramos", "קג", "קילו", "קילוגרם", "קילוגרמים"}},
+	{Code: "lb", Dimension: DimensionMass, Factor: 453.59237, Aliases: []string{"lbs", "pound", "pounds", "ליברה"}},
+	{Code: "oz", Dimension: DimensionMass, Factor: 28.349523125, Aliases: []string{"ounce", "ounces", "אונקיה"}},
+
+	// Volume
+	{Code: "ml", Dimension: DimensionVolume, Factor: 1, Aliases: []string{"milliliter", "milliliters", "millilitre", "millilitres", "mililitro", "mililitros", "מל", "מיליליטר"}},
+	{Code: "l", Dimension: DimensionVolume, Factor: 1000, Aliases: []string{"lt", "ltr", "liter", "liters", "litre", "litres", "litro", "litros", "ליטר", "ליטרים"}},
+	{Code: "cl", Dimension: DimensionVolume, Factor: 10, Aliases: []string{"centiliter", "centiliters", "centilitre", "centilitres"}},
+	{Code: "dl", Dimension: DimensionVolume, Factor: 100, Aliases: []string{"deciliter", "deciliters", "decilitre", "decilitres"}},
+	{Code: "tsp", Dimension: DimensionVolume, Factor: 5, Aliases: []string{"teaspoon", "teaspoons", "כפית", "כפיות"}},
+	{Code: "tbsp", Dimension: DimensionVolume, Factor: 15, Aliases: []string{"tablespoon", "tablespoons", "כף", "כפות"}},
+	{Code: "cup", Dimension: DimensionVolume, Factor: 240, Aliases: []string{"cups", "כוס", "כוסות"}},
+
+	// Count
+	{Code: "pcs", Dimension: DimensionCount, Factor: 1, Aliases: []string{"pc", "piece", "pieces", "unit", "units", "x", "count", "pieza", "piezas", "stück", "stk", "יחידה", "יחידות", "יח"}},
+	{Code: "dozen", Dimension: DimensionCount, Factor: 12, Aliases: []string{"dz", "doz", "docena", "tucet", "תריסר"}},
+}
+
+// index maps normalized unit names and aliases to units
+var index = buildIndex()
+
+func buildIndex() map[string]Unit {
+	idx := map[string]Unit{}
+	for _, unit := range registry {
+		idx[lookupKey(unit.Code)] = unit
+		for _, alias := range unit.Aliases {
+			idx[lookupKey(alias)] = unit
+		}
+	}
+	return idx
+}
+
+// lookupKey normalizes a unit name for lookup: lower-cased, without quotes, geresh/gershayim or trailing dots
+func lookupKey(name string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	key = strings.NewReplacer(`"`, "", "'", "", "״", "", "׳", "", "’", "").Replace(key)
+	key = strings.TrimRight(key, ".")
+	return strings.Join(strings.Fields(key), " ")
+}
+
+// All returns the known units ordered by dimension
+func All() []Unit {
+	units := make([]Unit, len(registry))
+	copy(units, registry)
+	sort.SliceStable(units, func(i, j int) bool {
+		return units[i].Dimension < units[j].Dimension
+	})
+	return units
+}
+
+// Lookup finds a unit by its code or one of its aliases
+func Lookup(name string) (Unit, bool) {
+	unit, ok := index[lookupKey(name)]
+	return unit, ok
+}
+
+// Normalize returns the canonical code of a known unit, or the trimmed name when the unit is unknown
+func Normalize(name string) string {
+	if unit, ok := Lookup(name); ok {
+		return unit.Code
+	}
+	return strings.TrimSpace(name)
+}
+
+// Compatible reports whether quantities in the two units can be converted into each other
+// Unknown units are only compatible with the same name (case-insensitive)
+func Compatible(from, to string) bool {
+	fromUnit, fromOK := Lookup(from)
+	toUnit, toOK := Lookup(to)
+	if fromOK && toOK {
+		return fromUnit.Dimension == toUnit.Dimension
+	}
+	return !fromOK && !toOK && strings.EqualFold(strings.TrimSpace(from), strings.TrimSpace(to))
+}
+
+// Convert converts a value between two compatible units
+func Convert(value float64, from, to string) (float64, error) {
+	if !Compatible(from, to) {
+		return 0, fmt.Errorf("cannot convert %q to %q", from, to)
+	}
+
+	fromUnit, known := Lookup(from)
+	if !known {
+		return value, nil
+	}
+	toUnit, _ := Lookup(to)
+	return round(value * fromUnit.Factor / toUnit.Factor), nil
+}
+
+// Add sums two quantities, returning the result in the first quantity's unit
+// An empty unit is treated as a plain count
+func Add(value float64, unit string, other float64, otherUnit string) (float64, error) {
+	converted, err := Convert(other, countIfEmpty(otherUnit), countIfEmpty(unit))
+	if err != nil {
+		return 0, err
+	}
+	return round(value + converted), nil
+}
+
+// countIfEmpty treats a missing unit as a plain count
+func countIfEmpty(unit string) string {
+	if strings.TrimSpace(unit) == "" {
+		return "pcs"
+	}
+	return unit
+}
+
+// round removes floating point noise from converted values
+func round(value float64) float64 {
+	return math.Round(value*1e6) / 1e6
+}
@@ -0,0 +1,139 @@
+package units
+
+import (
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name string
+		code string // Empty for an unknown unit
+	}{
+		{"kg", "kg"},
+		{"KG", "kg"},
+		{" Kilos ", "kg"},
+		{"kg.", "kg"},
+		{"ק\"ג", "kg"},
+		{"ק״ג", "kg"},
+		{"קילו", "kg"},
+		{"gr", "g"},
+		{"גרם", "g"},
+		{"ליטר", "l"},
+		{"מ\"ל", "ml"},
+		{"stück", "pcs"},
+		{"יח'", "pcs"},
+		{"tucet", "dozen"},
+		// Single Hebrew letters are words and prefixes, not units
+		{"ל", ""},
+		{"ג", ""},
+		{"bunch", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unit, ok := Lookup(tt.name)
+			if ok != (tt.code != "") || unit.Code != tt.code {
+				t.Errorf("Expected %q, got %q (found %v)", tt.code, unit.Code, ok)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Kilograms", "kg"},
+		{"ליטרים", "l"},
+		{" bunch ", "bunch"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.name); got != tt.want {
+			t.Errorf("Expected %q to normalize to %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		value   float64
+		from    string
+		to      string
+		want    float64
+		wantErr bool
+	}{
+		{1.5, "kg", "g", 1500, false},
+		{250, "g", "kg", 0.25, false},
+		{1, "lb", "g", 453.59237, false},
+		{2, "cups", "ml", 480, false},
+		{3, "tbsp", "tsp", 9, false},
+		{2, "dozen", "pcs", 24, false},
+		{0.1, "l", "ml", 100, false},
+		{2, "Bunch", "bunch", 2, false},
+		{1, "kg", "l", 0, true},
+		{1, "kg", "bunch", 0, true},
+		{1, "bunch", "box", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := Convert(tt.value, tt.from, tt.to)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Expected converting %s to %s to fail, got %v", tt.from, tt.to, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Expected %v %s to be %v %s, got %v (%v)", tt.value, tt.from, tt.want, tt.to, got, err)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		value     float64
+		unit      string
+		other     float64
+		otherUnit string
+		want      float64
+		wantErr   bool
+	}{
+		{1, "kg", 500, "g", 1.5, false},
+		{0.1, "l", 0.2, "l", 0.3, false},
+		{2, "", 3, "pcs", 5, false},
+		{1, "dozen", 6, "", 1.5, false},
+		{1, "kg", 1, "", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := Add(tt.value, tt.unit, tt.other, tt.otherUnit)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Expected adding %s to %s to fail, got %v", tt.otherUnit, tt.unit, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Expected %v %s + %v %s to be %v, got %v (%v)", tt.value, tt.unit, tt.other, tt.otherUnit, tt.want, got, err)
+		}
+	}
+}
+
+func TestAllOrderedByDimension(t *testing.T) {
+	all := All()
+	if len(all) != len(registry) {
+		t.Fatalf("Expected %d units, got %d", len(registry), len(all))
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i-1].Dimension > all[i].Dimension {
+			t.Errorf("Expected units ordered by dimension, got %s before %s", all[i-1].Code, all[i].Code)
+		}
+	}
+	if all[0].Code != "pcs" {
+		t.Errorf("Expected the base unit first within its dimension, got %s", all[0].Code)
+	}
+}
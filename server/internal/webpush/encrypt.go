@@ -0,0 +1,165 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// recordSize is the aes128gcm record size; messages are sent as a single record
+	recordSize = 4096
+
+	// headerSize is the salt, record size, key ID length and uncompressed P-256 key ID of the aes128gcm header
+	headerSize = 16 + 4 + 1 + 65
+
+	// MaxPayloadSize is the largest plaintext that keeps the message, header included, within the 4096 bytes
+	// push services accept (RFC 8291, section 4), after the padding delimiter and GCM tag
+	MaxPayloadSize = recordSize - headerSize - 1 - 16
+)
+
+// ErrPayloadTooLarge is returned for messages that do not fit a single push record
+var ErrPayloadTooLarge = errors.New("push payload too large")
+
+// errInvalidPadding is returned by Decrypt for a record that does not end in the delimiter and zero padding
+var errInvalidPadding = errors.New("invalid push message padding")
+
+// Encrypt encrypts a message for a subscription using the aes128gcm content coding (RFC 8291)
+func Encrypt(sub Subscription, payload []byte) ([]byte, error) {
+	if len(payload) > MaxPayloadSize {
+		return nil, ErrPayloadTooLarge
+	}
+
+	uaPublicBytes, err := decode(sub.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	authSecret, err := decode(sub.Auth)
+	if err != nil || len(authSecret) != 16 {
+		return nil, fmt.Errorf("invalid auth secret")
+	}
+
+	// A fresh key pair and salt per message keep every message's content key unique
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	sharedSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+	asPublicBytes := asPrivate.PublicKey().Bytes()
+	gcm, nonce, err := contentCipher(sharedSecret, authSecret, salt, uaPublicBytes, asPublicBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	body := make([]byte, headerSize, headerSize+len(payload)+1+gcm.Overhead())
+	copy(body, salt)
+	binary.BigEndian.PutUint32(body[16:20], recordSize)
+	body[20] = byte(len(asPublicBytes))
+	copy(body[21:], asPublicBytes)
+
+	// The 0x02 delimiter marks the last (and only) record, with no further padding
+	plaintext := append(append([]byte{}, payload...), 0x02)
+	return gcm.Seal(body, nonce, plaintext, nil), nil
+}
+
+// Decrypt decrypts an aes128gcm message with the user agent's subscription keys, as a browser does
+// It is used by push-service stand-ins to check what the server sends
+func Decrypt(body []byte, uaPrivate *ecdh.PrivateKey, authSecret []byte) ([]byte, error) {
+	if len(body) < headerSize {
+		return nil, errors.New("push message too short")
+	}
+	salt := body[:16]
+	keyLength := int(body[20])
+	if keyLength != 65 || len(body) < 21+keyLength {
+		return nil, errors.New("unexpected key ID in push message")
+	}
+	asPublicBytes := body[21 : 21+keyLength]
+
+	asPublic, err := ecdh.P256().NewPublicKey(asPublicBytes)
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, err := uaPrivate.ECDH(asPublic)
+	if err != nil {
+		return nil, err
+	}
+	gcm, nonce, err := contentCipher(sharedSecret, authSecret, salt, uaPrivate.PublicKey().Bytes(), asPublicBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, body[21+keyLength:], nil)
+	if err != nil {
+		return nil, err
+	}
+	// Strip the padding: trailing zeros followed by the record delimiter
+	for i := len(plaintext) - 1; i >= 0; i-- {
+		switch plaintext[i] {
+		case 0x00:
+			continue
+		case 0x02:
+			return plaintext[:i], nil
+		default:
+			return nil, errInvalidPadding
+		}
+	}
+	return nil, errInvalidPadding
+}
+
+// contentCipher derives the content encryption key and nonce of a message (RFC 8291 section 3.4)
+func contentCipher(sharedSecret, authSecret, salt, uaPublic, asPublic []byte) (cipher.AEAD, []byte, error) {
+	keyInfo := append(append([]byte("WebPush: info\x00"), uaPublic...), asPublic...)
+	ikm, err := expand(hkdf.Extract(sha256.New, sharedSecret, authSecret), keyInfo, 32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	cek, err := expand(prk, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce, err := expand(prk, []byte("Content-Encoding: nonce\x00"), 12)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return gcm, nonce, nil
+}
+
+// expand reads length bytes of HKDF-Expand output
+func expand(prk []byte, info []byte, length int) ([]byte, error) {
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, info), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,110 @@
+package webpush
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// testSubscriber holds the user agent keys of a subscription, as a browser does
+type testSubscriber struct {
+	private    *ecdh.PrivateKey
+	authSecret []byte
+}
+
+func newTestSubscriber(t *testing.T) *testSubscriber {
+	t.Helper()
+	private, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	authSecret := make([]byte, 16)
+	rand.Read(authSecret)
+	return &testSubscriber{private: private, authSecret: authSecret}
+}
+
+func (s *testSubscriber) subscription() Subscription {
+	return Subscription{
+		Endpoint: "https://push.example.com/send/1",
+		P256dh:   base64.RawURLEncoding.EncodeToString(s.private.PublicKey().Bytes()),
+		Auth:     base64.RawURLEncoding.EncodeToString(s.authSecret),
+	}
+}
+
+// seal encrypts a record with the given plaintext as is, so tests can control its padding
+func (s *testSubscriber) seal(t *testing.T, plaintext []byte) []byte {
+	t.Helper()
+	asPrivate, _ := ecdh.P256().GenerateKey(rand.Reader)
+	sharedSecret, _ := asPrivate.ECDH(s.private.PublicKey())
+	salt := make([]byte, 16)
+	rand.Read(salt)
+	asPublic := asPrivate.PublicKey().Bytes()
+	gcm, nonce, err := contentCipher(sharedSecret, s.authSecret, salt, s.private.PublicKey().Bytes(), asPublic)
+	if err != nil {
+		t.Fatalf("Failed to derive content key: %v", err)
+	}
+
+	body := make([]byte, headerSize)
+	copy(body, salt)
+	binary.BigEndian.PutUint32(body[16:20], recordSize)
+	body[20] = byte(len(asPublic))
+	copy(body[21:], asPublic)
+	return gcm.Seal(body, nonce, plaintext, nil)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	subscriber := newTestSubscriber(t)
+	payload := []byte(`{"title":"New in Groceries"}`)
+
+	body, err := Encrypt(subscriber.subscription(), payload)
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	decrypted, err := Decrypt(body, subscriber.private, subscriber.authSecret)
+	if err != nil {
+		t.Fatalf("Failed to decrypt: %v", err)
+	}
+	if !bytes.Equal(decrypted, payload) {
+		t.Errorf("Expected %q, got %q", payload, decrypted)
+	}
+
+	if _, err := Encrypt(subscriber.subscription(), make([]byte, MaxPayloadSize+1)); !errors.Is(err, ErrPayloadTooLarge) {
+		t.Errorf("Expected an oversized payload to be rejected, got %v", err)
+	}
+}
+
+func TestDecryptPadding(t *testing.T) {
+	subscriber := newTestSubscriber(t)
+
+	tests := []struct {
+		name      string
+		plaintext []byte
+		want      []byte
+	}{
+		{"Delimiter and zero padding", []byte("hello\x02\x00\x00\x00"), []byte("hello")},
+		{"Delimiter inside the content", []byte("a\x02b\x02"), []byte("a\x02b")},
+		{"Bytes after the delimiter", []byte("a\x02b\x05"), nil},
+		{"Non-zero padding", []byte("a\x02b\x00\x01\x00"), nil},
+		{"No delimiter", []byte("\x00\x00"), nil},
+		{"Empty record", []byte{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decrypted, err := Decrypt(subscriber.seal(t, tt.plaintext), subscriber.private, subscriber.authSecret)
+			if tt.want == nil {
+				if !errors.Is(err, errInvalidPadding) {
+					t.Errorf("Expected invalid padding to be rejected, got %q, %v", decrypted, err)
+				}
+				return
+			}
+			if err != nil || !bytes.Equal(decrypted, tt.want) {
+				t.Errorf("Expected %q, got %q, %v", tt.want, decrypted, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package webpush
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+)
+
+// ErrInvalidKeys is returned for VAPID keys that are not a matching P-256 key pair
+var ErrInvalidKeys = errors.New("invalid VAPID keys")
+
+// VAPIDKeys is the key pair identifying the application server to push services (RFC 8292)
+type VAPIDKeys struct {
+	PublicKey  string // Uncompressed P-256 point, base64url without padding; given to browsers as applicationServerKey
+	PrivateKey string // P-256 scalar, base64url without padding
+
+	signer *ecdsa.PrivateKey
+}
+
+// GenerateVAPIDKeys creates a new VAPID key pair
+func GenerateVAPIDKeys() (*VAPIDKeys, error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return ParseVAPIDKeys(encode(key.PublicKey().Bytes()), encode(key.Bytes()))
+}
+
+// ParseVAPIDKeys validates a base64url-encoded VAPID key pair
+func ParseVAPIDKeys(publicKey, privateKey string) (*VAPIDKeys, error) {
+	private, err := decode(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: private key is not base64url", ErrInvalidKeys)
+	}
+	key, err := ecdh.P256().NewPrivateKey(private)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeys, err)
+	}
+	public := key.PublicKey().Bytes()
+	if encode(public) != publicKey {
+		return nil, fmt.Errorf("%w: public key does not match private key", ErrInvalidKeys)
+	}
+
+	// ecdsa has no constructor from raw bytes; the point is taken from the validated ecdh key
+	signer := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(public[1:33]),
+			Y:     new(big.Int).SetBytes(public[33:]),
+		},
+		D: new(big.Int).SetBytes(private),
+	}
+	return &VAPIDKeys{PublicKey: publicKey, PrivateKey: privateKey, signer: signer}, nil
+}
+
+// authorization returns the VAPID Authorization header for a push endpoint
+// The signed token is scoped to the endpoint's origin and expires after expiry (at most 24 hours)
+func (k *VAPIDKeys) authorization(endpoint string, subject string, expiry time.Duration) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid push endpoint: %s", endpoint)
+	}
+
+	header, _ := json.Marshal(map[string]string{"typ": "JWT", "alg": "ES256"})
+	claims, _ := json.Marshal(map[string]any{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(expiry).Unix(),
+		"sub": subject,
+	})
+	unsigned := encode(header) + "." + encode(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, k.signer, digest[:])
+	if err != nil {
+		return "", err
+	}
+	// JWS encodes ES256 signatures as fixed-width r || s
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	return fmt.Sprintf("vapid t=%s.%s, k=%s", unsigned, encode(signature), k.PublicKey), nil
+}
+
+// encode returns unpadded base64url, the encoding used throughout Web Push
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decode accepts base64url with or without padding, as browsers differ
+func decode(text string) ([]byte, error) {
+	if data, err := base64.RawURLEncoding.DecodeString(text); err == nil {
+		return data, nil
+	}
+	return base64.URLEncoding.DecodeString(text)
+}
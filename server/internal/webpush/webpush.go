@@ -0,0 +1,93 @@
+// Package webpush sends encrypted Web Push messages authenticated with VAPID (RFC 8030, 8291 and 8292)
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ErrSubscriptionGone is returned when the push service reports that a subscription expired or was revoked
+var ErrSubscriptionGone = errors.New("push subscription is gone")
+
+// Subscription is where and how to reach one browser, as given by PushSubscription.toJSON()
+type Subscription struct {
+	Endpoint string
+	P256dh   string // The browser's P-256 public key, base64url
+	Auth     string // The browser's 16-byte authentication secret, base64url
+}
+
+// Validate checks that a subscription has a usable endpoint and keys
+func (s Subscription) Validate() error {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return errors.New("endpoint must be an http or https URL")
+	}
+	publicKey, err := decode(s.P256dh)
+	if err != nil {
+		return errors.New("p256dh must be base64url")
+	}
+	if _, err := ecdh.P256().NewPublicKey(publicKey); err != nil {
+		return errors.New("p256dh is not a P-256 public key")
+	}
+	if authSecret, err := decode(s.Auth); err != nil || len(authSecret) != 16 {
+		return errors.New("auth must be a base64url 16-byte secret")
+	}
+	return nil
+}
+
+// Client sends push messages on behalf of the application server
+type Client struct {
+	Keys    *VAPIDKeys
+	Subject string // Contact for the push service operator, a mailto: or https: URL
+	HTTP    *http.Client
+}
+
+// NewClient creates a push client with the given VAPID identity
+func NewClient(keys *VAPIDKeys, subject string) *Client {
+	return &Client{Keys: keys, Subject: subject, HTTP: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Send encrypts a message and posts it to the subscription's push service
+// ttl is how long the push service keeps the message for an offline browser
+func (c *Client) Send(ctx context.Context, sub Subscription, payload []byte, ttl time.Duration) error {
+	body, err := Encrypt(sub, payload)
+	if err != nil {
+		return err
+	}
+	authorization, err := c.Keys.authorization(sub.Endpoint, c.Subject, 12*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(ttl.Seconds())))
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrSubscriptionGone
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("push service returned status %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}